@@ -1,38 +1,108 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"main/internal/api"
+	"main/internal/cache"
+	"main/internal/cli"
 	"main/internal/config"
+	"main/internal/domain"
+	"main/internal/journal"
 	"main/internal/ui"
 )
 
 func main() {
-	cfg, err := config.Load()
+	flags := flag.NewFlagSet("clockify-tui", flag.ContinueOnError)
+	flags.Usage = func() { cli.PrintUsage(os.Stderr) }
+	profile := flags.String("profile", "", "config profile to use")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+
+	args := flags.Args()
+	if len(args) > 0 && cli.IsHelp(args[0]) {
+		cli.PrintUsage(os.Stdout)
+		return
+	}
+	if len(args) > 0 && !cli.IsCommand(args[0]) {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+		cli.PrintUsage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*profile)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	client := api.NewClient(cfg.APIKey, cfg.BaseURL)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := api.NewClient(cfg.APIKey, cfg.BaseURL, api.WithReportsURL(cfg.ReportsURL))
 
-	user, err := client.GetCurrentUser()
+	identity, err := currentIdentity(ctx, client, cfg)
+	if api.IsUnauthorized(err) {
+		log.Fatalf("Failed to authenticate: API key rejected by Clockify")
+	}
 	if err != nil {
 		log.Fatalf("Failed to authenticate: %v", err)
 	}
 
-	client.SetUserID(user.ID)
+	client.SetUserID(identity.UserID)
 
 	workspaceID := cfg.WorkspaceID
 	if workspaceID == "" {
-		workspaceID = user.ActiveWorkspace
+		workspaceID = identity.ActiveWorkspace
 	}
 	client.SetWorkspace(workspaceID)
 
-	app := ui.NewApp(client)
+	cacheInstance := loadCache(cfg.CacheTTL, workspaceID)
+	offlineJournal := openJournal()
+
+	if len(args) > 0 {
+		runner := cli.NewRunner(client, cacheInstance, offlineJournal, os.Stdout)
+		err := runner.Run(ctx, args)
+		stop()
+		cacheInstance.Save()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The TUI handles ctrl+c itself and cancels its own requests on quit.
+	stop()
+
+	app := ui.NewApp(client, cacheInstance, offlineJournal,
+		ui.WithIdleTimeout(cfg.IdleTimeout),
+		ui.WithHourTargets(domain.NewHourTargets(cfg.Targets.Daily, cfg.Targets.Weekly,
+			cfg.Targets.Weekdays, cfg.Targets.Holidays, cfg.Targets.BalanceSince)),
+		ui.WithPomodoro(domain.PomodoroSettings{
+			Work:           cfg.Pomodoro.Work,
+			ShortBreak:     cfg.Pomodoro.ShortBreak,
+			LongBreak:      cfg.Pomodoro.LongBreak,
+			LongBreakEvery: cfg.Pomodoro.LongBreakEvery,
+			StopOnBreak:    cfg.Pomodoro.StopOnBreak,
+		}),
+		ui.WithWorkingHours(domain.WorkingHours{
+			Start:  cfg.WorkingHours.Start,
+			End:    cfg.WorkingHours.End,
+			MinGap: cfg.WorkingHours.MinGap,
+		}),
+	)
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -40,3 +110,44 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadCache restores the workspace's disk cache. Caching is best effort: if
+// the cache directory is unavailable the cache simply stays in memory.
+func loadCache(ttl time.Duration, workspaceID string) *cache.Cache {
+	path, err := cache.DiskPath(workspaceID)
+	if err != nil {
+		return cache.NewCache(ttl)
+	}
+
+	cacheInstance := cache.NewDiskCache(ttl, path)
+	cacheInstance.Load()
+	return cacheInstance
+}
+
+// currentIdentity asks Clockify for the current user and remembers the answer,
+// so that a later start without network access can still queue writes.
+func currentIdentity(ctx context.Context, client *api.Client, cfg *config.Config) (*cache.Identity, error) {
+	user, err := client.GetCurrentUser(ctx)
+	if api.IsNetworkError(err) {
+		if identity, ok := cache.LoadIdentity(cfg.APIKey, cfg.BaseURL); ok {
+			return identity, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &cache.Identity{UserID: user.ID, ActiveWorkspace: user.ActiveWorkspace}
+	cache.SaveIdentity(cfg.APIKey, cfg.BaseURL, *identity)
+	return identity, nil
+}
+
+// openJournal returns the offline journal, or nil (offline mode disabled) when
+// no state directory is available.
+func openJournal() *journal.Journal {
+	path, err := journal.Path()
+	if err != nil {
+		return nil
+	}
+	return journal.Open(path)
+}
@@ -1,24 +1,38 @@
 package api
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-func (c *Client) GetCurrentUser() (*User, error) {
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
 	var user User
-	if err := c.get("/user", &user); err != nil {
+	if err := c.get(ctx, "/user", &user); err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 	return &user, nil
 }
 
-func (c *Client) GetWorkspaces() ([]Workspace, error) {
+func (c *Client) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
 	var workspaces []Workspace
-	if err := c.get("/workspaces", &workspaces); err != nil {
+	if err := c.get(ctx, "/workspaces", &workspaces); err != nil {
 		return nil, fmt.Errorf("failed to get workspaces: %w", err)
 	}
 	return workspaces, nil
 }
 
-func (c *Client) ValidateAPIKey() error {
-	_, err := c.GetCurrentUser()
+// GetUsers returns the members of the current workspace.
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	path := fmt.Sprintf("/workspaces/%s/users", c.GetWorkspaceID())
+
+	users, err := getAllPages[User](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return users, nil
+}
+
+func (c *Client) ValidateAPIKey(ctx context.Context) error {
+	_, err := c.GetCurrentUser(ctx)
 	return err
 }
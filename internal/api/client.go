@@ -2,32 +2,57 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
+// pageSize is the number of items requested per page from list endpoints.
+// Clockify defaults to 50 when no page size is given.
+const pageSize = 200
+
 type Client struct {
 	httpClient  *http.Client
 	baseURL     string
+	reportsURL  string
 	apiKey      string
 	workspaceID string
 	userID      string
+	retryPolicy RetryPolicy
+
+	// workspaceMutex guards workspaceID, which the TUI can switch while
+	// requests are in flight.
+	workspaceMutex sync.RWMutex
 }
 
-func NewClient(apiKey, baseURL string) *Client {
-	return &Client{
+func NewClient(apiKey, baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		baseURL: baseURL,
-		apiKey:  apiKey,
+		baseURL:     baseURL,
+		apiKey:      apiKey,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.reportsURL == "" {
+		c.reportsURL = DefaultReportsURL(baseURL)
+	}
+
+	return c
 }
 
 func (c *Client) SetWorkspace(workspaceID string) {
+	c.workspaceMutex.Lock()
+	defer c.workspaceMutex.Unlock()
 	c.workspaceID = workspaceID
 }
 
@@ -36,6 +61,8 @@ func (c *Client) SetUserID(userID string) {
 }
 
 func (c *Client) GetWorkspaceID() string {
+	c.workspaceMutex.RLock()
+	defer c.workspaceMutex.RUnlock()
 	return c.workspaceID
 }
 
@@ -43,20 +70,85 @@ func (c *Client) GetUserID() string {
 	return c.userID
 }
 
-func (c *Client) doRequest(method, path string, body any, result any) error {
-	var reqBody io.Reader
+func (c *Client) doRequest(ctx context.Context, method, path string, body any, result any) error {
+	return c.doRequestWithRetry(ctx, method, c.baseURL+path, body, result, isIdempotent(method))
+}
+
+// doRequestWithRetry sends the request, retrying transient failures according
+// to the client's RetryPolicy when retryable is set.
+func (c *Client) doRequestWithRetry(ctx context.Context, method, url string, body any, result any, retryable bool) error {
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		data, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		jsonData = data
+	}
+
+	maxAttempts := 1
+	if retryable && c.retryPolicy.MaxAttempts > 1 {
+		maxAttempts = c.retryPolicy.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, url, jsonData)
+
+		var requestErr error
+		transient := false
+		switch {
+		case err != nil:
+			// Only failures to reach the server are worth another try;
+			// errors building the request or reading the body would repeat.
+			requestErr = err
+			transient = IsNetworkError(err)
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			requestErr = newAPIError(resp.StatusCode, respBody)
+			transient = isRetryableStatus(resp.StatusCode)
+		}
+
+		if requestErr == nil {
+			if result != nil && len(respBody) > 0 {
+				if err := json.Unmarshal(respBody, result); err != nil {
+					return fmt.Errorf("failed to unmarshal response: %w", err)
+				}
+			}
+			return nil
+		}
+
+		if !transient || attempt >= maxAttempts {
+			return requestErr
+		}
+
+		delay := c.retryPolicy.backoff(attempt)
+		if resp != nil {
+			if wait, ok := serverDelay(resp.Header, time.Now()); ok {
+				if wait > c.retryPolicy.MaxServerDelay {
+					return requestErr
+				}
+				delay = wait
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, url string, jsonData []byte) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
 	}
 
-	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("X-Api-Key", c.apiKey)
@@ -64,44 +156,66 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, &NetworkError{Err: err}
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+	return resp, respBody, nil
+}
+
+// getAllPages fetches every page of a list endpoint and concatenates the
+// results, stopping at the first page that comes back short.
+func getAllPages[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
 	}
 
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
+	var all []T
+	for page := 1; ; page++ {
+		var items []T
+		pagePath := fmt.Sprintf("%s%spage=%d&page-size=%d", path, separator, page, pageSize)
+		if err := c.get(ctx, pagePath, &items); err != nil {
+			return nil, err
+		}
+
+		all = append(all, items...)
+		if len(items) < pageSize {
+			return all, nil
 		}
 	}
+}
 
-	return nil
+func (c *Client) get(ctx context.Context, path string, result any) error {
+	return c.doRequest(ctx, "GET", path, nil, result)
 }
 
-func (c *Client) get(path string, result any) error {
-	return c.doRequest("GET", path, nil, result)
+func (c *Client) post(ctx context.Context, path string, body any, result any) error {
+	return c.doRequest(ctx, "POST", path, body, result)
 }
 
-func (c *Client) post(path string, body any, result any) error {
-	return c.doRequest("POST", path, body, result)
+// postReport queries the reports API. Report queries have no side effects,
+// which makes them safe to retry.
+func (c *Client) postReport(ctx context.Context, path string, body any, result any) error {
+	return c.doRequestWithRetry(ctx, "POST", c.reportsURL+path, body, result, true)
 }
 
-func (c *Client) patch(path string, body any, result any) error {
-	return c.doRequest("PATCH", path, body, result)
+func (c *Client) patch(ctx context.Context, path string, body any, result any) error {
+	return c.doRequest(ctx, "PATCH", path, body, result)
 }
 
-func (c *Client) put(path string, body any, result any) error {
-	return c.doRequest("PUT", path, body, result)
+func (c *Client) put(ctx context.Context, path string, body any, result any) error {
+	return c.doRequest(ctx, "PUT", path, body, result)
 }
 
-func (c *Client) delete(path string) error {
-	return c.doRequest("DELETE", path, nil, nil)
+func (c *Client) delete(ctx context.Context, path string) error {
+	return c.doRequest(ctx, "DELETE", path, nil, nil)
 }
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+func (c *Client) GetClients(ctx context.Context) ([]ClockifyClient, error) {
+	path := fmt.Sprintf("/workspaces/%s/clients?archived=false", c.GetWorkspaceID())
+
+	clients, err := getAllPages[ClockifyClient](ctx, c, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clients: %w", err)
+	}
+	return clients, nil
+}
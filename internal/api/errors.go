@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned for any non-2xx response from Clockify.
+type APIError struct {
+	StatusCode int
+	// Code is Clockify's own error code from the response body, if any.
+	Code    int
+	Message string
+	Body    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+
+	var payload struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Message = payload.Message
+		apiErr.Code = payload.Code
+	}
+
+	return apiErr
+}
+
+// NetworkError means Clockify could not be reached at all, as opposed to
+// answering with an error status.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("failed to execute request: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+func IsNetworkError(err error) bool {
+	var netErr *NetworkError
+	return errors.As(err, &netErr)
+}
+
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+func hasStatus(err error, statuses ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, status := range statuses {
+		if apiErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+func IsServerError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode >= 500
+}
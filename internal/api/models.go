@@ -26,6 +26,7 @@ type TimeEntry struct {
 	ProjectID    *string      `json:"projectId,omitempty"`
 	TaskID       *string      `json:"taskId,omitempty"`
 	TagIDs       []string     `json:"tagIds,omitempty"`
+	Billable     bool         `json:"billable"`
 	WorkspaceID  string       `json:"workspaceId"`
 	UserID       string       `json:"userId"`
 	TimeInterval TimeInterval `json:"timeInterval"`
@@ -38,6 +39,7 @@ type TimeEntryRequest struct {
 	ProjectID   *string   `json:"projectId,omitempty"`
 	TaskID      *string   `json:"taskId,omitempty"`
 	TagIDs      []string  `json:"tagIds,omitempty"`
+	Billable    bool      `json:"billable"`
 }
 
 type Project struct {
@@ -46,6 +48,16 @@ type Project struct {
 	ClientID *string `json:"clientId,omitempty"`
 	Color    string  `json:"color"`
 	Archived bool    `json:"archived"`
+	Billable bool    `json:"billable"`
+}
+
+// ClockifyClient is a Clockify client, the customer a project is billed to.
+// Named to avoid confusion with the API Client.
+type ClockifyClient struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	WorkspaceID string `json:"workspaceId"`
+	Archived    bool   `json:"archived"`
 }
 
 type Task struct {
@@ -62,10 +74,36 @@ type Tag struct {
 	Archived    bool   `json:"archived"`
 }
 
+// ReportFilters are the filters shared by the report endpoints; nil fields
+// do not filter.
+type ReportFilters struct {
+	Billable *bool               `json:"billable,omitempty"`
+	Projects *ReportEntityFilter `json:"projects,omitempty"`
+	Clients  *ReportEntityFilter `json:"clients,omitempty"`
+	Tasks    *ReportEntityFilter `json:"tasks,omitempty"`
+	Users    *ReportEntityFilter `json:"users,omitempty"`
+	Tags     *ReportTagFilter    `json:"tags,omitempty"`
+}
+
+type ReportEntityFilter struct {
+	IDs      []string `json:"ids"`
+	Contains string   `json:"contains"`
+	Status   string   `json:"status"`
+}
+
+type ReportTagFilter struct {
+	IDs                  []string `json:"ids"`
+	ContainedInTimeentry string   `json:"containedInTimeentry"`
+	Status               string   `json:"status"`
+}
+
 type DetailedReportRequest struct {
 	DateRangeStart time.Time      `json:"dateRangeStart"`
 	DateRangeEnd   time.Time      `json:"dateRangeEnd"`
+	TimeZone       string         `json:"timeZone,omitempty"`
+	AmountShown    string         `json:"amountShown"`
 	DetailedFilter DetailedFilter `json:"detailedFilter"`
+	ReportFilters
 }
 
 type DetailedFilter struct {
@@ -76,7 +114,10 @@ type DetailedFilter struct {
 type SummaryReportRequest struct {
 	DateRangeStart time.Time     `json:"dateRangeStart"`
 	DateRangeEnd   time.Time     `json:"dateRangeEnd"`
+	TimeZone       string        `json:"timeZone,omitempty"`
+	AmountShown    string        `json:"amountShown"`
 	SummaryFilter  SummaryFilter `json:"summaryFilter"`
+	ReportFilters
 }
 
 type SummaryFilter struct {
@@ -85,29 +126,70 @@ type SummaryFilter struct {
 
 type DetailedReport struct {
 	TimeEntries []ReportTimeEntry `json:"timeentries"`
-	TotalsMap   []TotalMap        `json:"totals"`
+	Totals      []ReportTotals    `json:"totals"`
 }
 
+// ReportTimeEntry is a time entry as returned by the detailed report, with
+// names resolved by the server. Amounts are in the currency's minor unit.
 type ReportTimeEntry struct {
-	ID          string       `json:"_id"`
-	Description string       `json:"description"`
-	ProjectID   string       `json:"projectId"`
-	ProjectName string       `json:"projectName"`
-	TaskID      string       `json:"taskId"`
-	TaskName    string       `json:"taskName"`
-	TimeInterval TimeInterval `json:"timeInterval"`
+	ID           string             `json:"_id"`
+	Description  string             `json:"description"`
+	UserID       string             `json:"userId"`
+	UserName     string             `json:"userName"`
+	Billable     bool               `json:"billable"`
+	ProjectID    string             `json:"projectId"`
+	ProjectName  string             `json:"projectName"`
+	ClientID     string             `json:"clientId"`
+	ClientName   string             `json:"clientName"`
+	TaskID       string             `json:"taskId"`
+	TaskName     string             `json:"taskName"`
+	Tags         []ReportTag        `json:"tags"`
+	TimeInterval ReportTimeInterval `json:"timeInterval"`
+	Amount       float64            `json:"amount"`
+	Currency     string             `json:"currency"`
+}
+
+type ReportTag struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+// ReportTimeInterval differs from TimeInterval in giving the duration in
+// seconds.
+type ReportTimeInterval struct {
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end"`
+	Duration int64      `json:"duration"`
+}
+
+// ReportTotals holds durations in seconds.
+type ReportTotals struct {
+	TotalTime         int64          `json:"totalTime"`
+	TotalBillableTime int64          `json:"totalBillableTime"`
+	EntriesCount      int            `json:"entriesCount"`
+	Amounts           []ReportAmount `json:"amounts"`
+}
+
+type ReportAmount struct {
+	Type               string           `json:"type"`
+	AmountByCurrencies []CurrencyAmount `json:"amountByCurrencies"`
 }
 
-type TotalMap struct {
-	TotalTime int64 `json:"totalTime"`
+type CurrencyAmount struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
 }
 
 type SummaryReport struct {
+	Totals   []ReportTotals `json:"totals"`
 	GroupOne []SummaryGroup `json:"groupOne"`
 }
 
+// SummaryGroup is one row of a summary report; Duration is in seconds and ID
+// is empty for time without e.g. a project.
 type SummaryGroup struct {
-	Duration int64           `json:"duration"`
-	Name     string          `json:"name"`
-	Children []SummaryGroup  `json:"children,omitempty"`
+	ID       string         `json:"_id"`
+	Duration int64          `json:"duration"`
+	Name     string         `json:"name"`
+	Children []SummaryGroup `json:"children,omitempty"`
 }
@@ -1,23 +1,36 @@
 package api
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-func (c *Client) GetProjects() ([]Project, error) {
-	path := fmt.Sprintf("/workspaces/%s/projects?archived=false", c.workspaceID)
+func (c *Client) GetProjects(ctx context.Context) ([]Project, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects?archived=false", c.GetWorkspaceID())
 
-	var projects []Project
-	if err := c.get(path, &projects); err != nil {
+	projects, err := getAllPages[Project](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
 	return projects, nil
 }
 
-func (c *Client) GetProjectByID(id string) (*Project, error) {
-	path := fmt.Sprintf("/workspaces/%s/projects/%s", c.workspaceID, id)
+func (c *Client) GetProjectByID(ctx context.Context, id string) (*Project, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects/%s", c.GetWorkspaceID(), id)
 
 	var project Project
-	if err := c.get(path, &project); err != nil {
+	if err := c.get(ctx, path, &project); err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
 	return &project, nil
 }
+
+func (c *Client) CreateProject(ctx context.Context, name string) (*Project, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects", c.GetWorkspaceID())
+
+	var project Project
+	if err := c.post(ctx, path, map[string]string{"name": name}, &project); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	return &project, nil
+}
@@ -1,49 +1,157 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
-func (c *Client) GetDetailedReport(start, end time.Time) (*DetailedReport, error) {
-	req := DetailedReportRequest{
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		DetailedFilter: DetailedFilter{
-			Page:     1,
-			PageSize: 1000,
-		},
+// Summary report groupings, outermost first.
+const (
+	GroupClient  = "CLIENT"
+	GroupProject = "PROJECT"
+	GroupTask    = "TASK"
+	GroupDate    = "DATE"
+)
+
+// detailedPageSize is the largest page the detailed report accepts.
+const detailedPageSize = 1000
+
+// ReportFilter narrows a report. Empty ID lists do not filter; a nil
+// Billable includes billable and non-billable time.
+type ReportFilter struct {
+	ProjectIDs []string
+	ClientIDs  []string
+	TaskIDs    []string
+	TagIDs     []string
+	UserIDs    []string
+	Billable   *bool
+}
+
+func (f ReportFilter) request() ReportFilters {
+	filters := ReportFilters{
+		Billable: f.Billable,
+		Projects: entityFilter(f.ProjectIDs),
+		Clients:  entityFilter(f.ClientIDs),
+		Tasks:    entityFilter(f.TaskIDs),
+		Users:    entityFilter(f.UserIDs),
+	}
+	if len(f.TagIDs) > 0 {
+		filters.Tags = &ReportTagFilter{IDs: f.TagIDs, ContainedInTimeentry: "CONTAINS", Status: "ALL"}
 	}
+	return filters
+}
+
+func entityFilter(ids []string) *ReportEntityFilter {
+	if len(ids) == 0 {
+		return nil
+	}
+	return &ReportEntityFilter{IDs: ids, Contains: "CONTAINS", Status: "ALL"}
+}
 
-	path := fmt.Sprintf("/workspaces/%s/reports/detailed", c.workspaceID)
+// DefaultReportsURL derives the reports API location from the regular API
+// base URL: reports.api.clockify.me for the global API, and /report/v1 on the
+// same host for regional and self-hosted servers.
+func DefaultReportsURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+	if u.Host == "api.clockify.me" {
+		return "https://reports.api.clockify.me/v1"
+	}
+	if prefix, ok := strings.CutSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v1"); ok {
+		u.Path = prefix + "/report/v1"
+		return u.String()
+	}
+	return baseURL
+}
 
-	var report DetailedReport
-	if err := c.post(path, req, &report); err != nil {
-		return nil, fmt.Errorf("failed to get detailed report: %w", err)
+// WithReportsURL overrides the reports API location, which otherwise is
+// derived from the base URL.
+func WithReportsURL(reportsURL string) ClientOption {
+	return func(c *Client) {
+		c.reportsURL = reportsURL
 	}
+}
 
-	return &report, nil
+// GetDetailedReport returns every entry in the range, fetching all pages.
+func (c *Client) GetDetailedReport(ctx context.Context, start, end time.Time, filter ReportFilter) (*DetailedReport, error) {
+	path := fmt.Sprintf("/workspaces/%s/reports/detailed", c.GetWorkspaceID())
+
+	report := &DetailedReport{}
+	for page := 1; ; page++ {
+		req := DetailedReportRequest{
+			DateRangeStart: start.UTC(),
+			DateRangeEnd:   end.UTC(),
+			TimeZone:       localTimeZone(),
+			AmountShown:    "EARNED",
+			DetailedFilter: DetailedFilter{
+				Page:     page,
+				PageSize: detailedPageSize,
+			},
+			ReportFilters: filter.request(),
+		}
+
+		var pageReport DetailedReport
+		if err := c.postReport(ctx, path, req, &pageReport); err != nil {
+			return nil, fmt.Errorf("failed to get detailed report: %w", err)
+		}
+
+		report.TimeEntries = append(report.TimeEntries, pageReport.TimeEntries...)
+		if page == 1 {
+			report.Totals = pageReport.Totals
+		}
+		if len(pageReport.TimeEntries) < detailedPageSize {
+			return report, nil
+		}
+	}
 }
 
-func (c *Client) GetSummaryReport(start, end time.Time, groups []string) (*SummaryReport, error) {
+func (c *Client) GetSummaryReport(ctx context.Context, start, end time.Time, groups []string, filter ReportFilter) (*SummaryReport, error) {
 	if groups == nil {
-		groups = []string{"PROJECT", "TASK"}
+		groups = []string{GroupProject, GroupTask}
 	}
 
 	req := SummaryReportRequest{
-		DateRangeStart: start,
-		DateRangeEnd:   end,
+		DateRangeStart: start.UTC(),
+		DateRangeEnd:   end.UTC(),
+		TimeZone:       localTimeZone(),
+		AmountShown:    "EARNED",
 		SummaryFilter: SummaryFilter{
 			Groups: groups,
 		},
+		ReportFilters: filter.request(),
 	}
 
-	path := fmt.Sprintf("/workspaces/%s/reports/summary", c.workspaceID)
+	path := fmt.Sprintf("/workspaces/%s/reports/summary", c.GetWorkspaceID())
 
 	var report SummaryReport
-	if err := c.post(path, req, &report); err != nil {
+	if err := c.postReport(ctx, path, req, &report); err != nil {
 		return nil, fmt.Errorf("failed to get summary report: %w", err)
 	}
 
 	return &report, nil
 }
+
+// localTimeZone returns the IANA name of the local time zone, so that the
+// server splits days where the user does. It is empty when unknown, in which
+// case the server uses the zone from the user's Clockify settings.
+func localTimeZone() string {
+	if name := time.Local.String(); name != "Local" && name != "" {
+		return name
+	}
+	if tz := os.Getenv("TZ"); tz != "" {
+		return strings.TrimPrefix(tz, ":")
+	}
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(target, "zoneinfo/"); ok {
+			return name
+		}
+	}
+	return ""
+}
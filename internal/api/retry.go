@@ -0,0 +1,101 @@
+package api
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient failures (network errors, 429 and 5xx
+// responses) are retried. Only idempotent requests and calls explicitly
+// marked as safe are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles per attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff.
+	MaxDelay time.Duration
+	// MaxServerDelay is the longest server-requested wait (Retry-After or
+	// rate-limit reset) that is honored; longer waits fail immediately.
+	MaxServerDelay time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		BaseDelay:      500 * time.Millisecond,
+		MaxDelay:       8 * time.Second,
+		MaxServerDelay: 30 * time.Second,
+	}
+}
+
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+type ClientOption func(*Client)
+
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the wait before retry number attempt (starting at 1), using
+// exponential growth with equal jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// serverDelay extracts the wait requested by the server from Retry-After
+// (seconds or HTTP date) or Clockify's X-RateLimit-Reset header.
+func serverDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+
+	if value := header.Get("X-RateLimit-Reset"); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil && reset >= 0 {
+			// Large values are epoch timestamps, small ones are relative seconds.
+			if reset > 1_000_000_000 {
+				return max(time.Unix(reset, 0).Sub(now), 0), true
+			}
+			return time.Duration(reset) * time.Second, true
+		}
+	}
+
+	return 0, false
+}
@@ -1,13 +1,26 @@
 package api
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-func (c *Client) GetTags() ([]Tag, error) {
-	path := fmt.Sprintf("/workspaces/%s/tags", c.workspaceID)
+func (c *Client) GetTags(ctx context.Context) ([]Tag, error) {
+	path := fmt.Sprintf("/workspaces/%s/tags", c.GetWorkspaceID())
 
-	var tags []Tag
-	if err := c.get(path, &tags); err != nil {
+	tags, err := getAllPages[Tag](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
 	return tags, nil
 }
+
+func (c *Client) CreateTag(ctx context.Context, name string) (*Tag, error) {
+	path := fmt.Sprintf("/workspaces/%s/tags", c.GetWorkspaceID())
+
+	var tag Tag
+	if err := c.post(ctx, path, map[string]string{"name": name}, &tag); err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return &tag, nil
+}
@@ -1,13 +1,26 @@
 package api
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-func (c *Client) GetTasksForProject(projectID string) ([]Task, error) {
-	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", c.workspaceID, projectID)
+func (c *Client) GetTasksForProject(ctx context.Context, projectID string) ([]Task, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", c.GetWorkspaceID(), projectID)
 
-	var tasks []Task
-	if err := c.get(path, &tasks); err != nil {
+	tasks, err := getAllPages[Task](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 	return tasks, nil
 }
+
+func (c *Client) CreateTask(ctx context.Context, projectID, name string) (*Task, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", c.GetWorkspaceID(), projectID)
+
+	var task Task
+	if err := c.post(ctx, path, map[string]string{"name": name}, &task); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	return &task, nil
+}
@@ -1,44 +1,45 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
 
-func (c *Client) GetTimeEntries(start, end time.Time) ([]TimeEntry, error) {
+func (c *Client) GetTimeEntries(ctx context.Context, start, end time.Time) ([]TimeEntry, error) {
 	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?start=%s&end=%s",
-		c.workspaceID,
+		c.GetWorkspaceID(),
 		c.userID,
 		start.UTC().Format(time.RFC3339),
 		end.UTC().Format(time.RFC3339))
 
-	var entries []TimeEntry
-	if err := c.get(path, &entries); err != nil {
+	entries, err := getAllPages[TimeEntry](ctx, c, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get time entries: %w", err)
 	}
 	return entries, nil
 }
 
-func (c *Client) GetTimeEntriesWithDescriptionContaining(description string) ([]TimeEntry, error) {
+func (c *Client) GetTimeEntriesWithDescriptionContaining(ctx context.Context, description string) ([]TimeEntry, error) {
 	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?description=%s",
-		c.workspaceID,
+		c.GetWorkspaceID(),
 		c.userID,
 		description)
 
 	var entries []TimeEntry
-	if err := c.get(path, &entries); err != nil {
+	if err := c.get(ctx, path, &entries); err != nil {
 		return nil, fmt.Errorf("failed to get time entries: %w", err)
 	}
 	return entries, nil
 }
 
-func (c *Client) GetCurrentTimer() (*TimeEntry, error) {
+func (c *Client) GetCurrentTimer(ctx context.Context) (*TimeEntry, error) {
 	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?in-progress=true",
-		c.workspaceID,
+		c.GetWorkspaceID(),
 		c.userID)
 
 	var entries []TimeEntry
-	if err := c.get(path, &entries); err != nil {
+	if err := c.get(ctx, path, &entries); err != nil {
 		return nil, fmt.Errorf("failed to get current timer: %w", err)
 	}
 
@@ -49,67 +50,89 @@ func (c *Client) GetCurrentTimer() (*TimeEntry, error) {
 	return &entries[0], nil
 }
 
-func (c *Client) StartTimer(description string, projectID, taskID *string, tagIDs []string) (*TimeEntry, error) {
+func (c *Client) StartTimer(ctx context.Context, description string, projectID, taskID *string, tagIDs []string, billable bool) (*TimeEntry, error) {
 	req := TimeEntryRequest{
 		Start:       time.Now().UTC(),
 		Description: description,
 		ProjectID:   projectID,
 		TaskID:      taskID,
 		TagIDs:      tagIDs,
+		Billable:    billable,
 	}
 
-	path := fmt.Sprintf("/workspaces/%s/time-entries", c.workspaceID)
+	path := fmt.Sprintf("/workspaces/%s/time-entries", c.GetWorkspaceID())
 
 	var entry TimeEntry
-	if err := c.post(path, req, &entry); err != nil {
+	if err := c.post(ctx, path, req, &entry); err != nil {
 		return nil, fmt.Errorf("failed to start timer: %w", err)
 	}
 
 	return &entry, nil
 }
 
-func (c *Client) StopTimer() (*TimeEntry, error) {
-	now := time.Now().UTC()
-	req := TimeEntryRequest{
-		End: &now,
+// stopTimerRequest only carries the end time, so that stopping leaves the
+// other fields of the running entry alone.
+type stopTimerRequest struct {
+	End time.Time `json:"end"`
+}
+
+func (c *Client) StopTimer(ctx context.Context) (*TimeEntry, error) {
+	return c.StopTimerAt(ctx, time.Now())
+}
+
+func (c *Client) StopTimerAt(ctx context.Context, end time.Time) (*TimeEntry, error) {
+	end = end.UTC()
+	req := stopTimerRequest{
+		End: end,
 	}
 
-	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", c.workspaceID, c.userID)
+	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", c.GetWorkspaceID(), c.userID)
 
 	var entry TimeEntry
-	if err := c.patch(path, req, &entry); err != nil {
+	if err := c.patch(ctx, path, req, &entry); err != nil {
 		return nil, fmt.Errorf("failed to stop timer: %w", err)
 	}
 
 	return &entry, nil
 }
 
-func (c *Client) CreateTimeEntry(entry TimeEntryRequest) (*TimeEntry, error) {
-	path := fmt.Sprintf("/workspaces/%s/time-entries", c.workspaceID)
+func (c *Client) GetTimeEntry(ctx context.Context, id string) (*TimeEntry, error) {
+	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", c.GetWorkspaceID(), id)
+
+	var entry TimeEntry
+	if err := c.get(ctx, path, &entry); err != nil {
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func (c *Client) CreateTimeEntry(ctx context.Context, entry TimeEntryRequest) (*TimeEntry, error) {
+	path := fmt.Sprintf("/workspaces/%s/time-entries", c.GetWorkspaceID())
 
 	var result TimeEntry
-	if err := c.post(path, entry, &result); err != nil {
+	if err := c.post(ctx, path, entry, &result); err != nil {
 		return nil, fmt.Errorf("failed to create time entry: %w", err)
 	}
 
 	return &result, nil
 }
 
-func (c *Client) UpdateTimeEntry(id string, entry TimeEntryRequest) (*TimeEntry, error) {
-	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", c.workspaceID, id)
+func (c *Client) UpdateTimeEntry(ctx context.Context, id string, entry TimeEntryRequest) (*TimeEntry, error) {
+	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", c.GetWorkspaceID(), id)
 
 	var result TimeEntry
-	if err := c.put(path, entry, &result); err != nil {
+	if err := c.put(ctx, path, entry, &result); err != nil {
 		return nil, fmt.Errorf("failed to update time entry: %w", err)
 	}
 
 	return &result, nil
 }
 
-func (c *Client) DeleteTimeEntry(id string) error {
-	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", c.workspaceID, id)
+func (c *Client) DeleteTimeEntry(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", c.GetWorkspaceID(), id)
 
-	if err := c.delete(path); err != nil {
+	if err := c.delete(ctx, path); err != nil {
 		return fmt.Errorf("failed to delete time entry: %w", err)
 	}
 
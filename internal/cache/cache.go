@@ -11,11 +11,16 @@ type Cache struct {
 	projects      []api.Project
 	tasks         map[string][]api.Task
 	tags          []api.Tag
+	clients       []api.ClockifyClient
 	projectsMutex sync.RWMutex
 	tasksMutex    sync.RWMutex
 	tagsMutex     sync.RWMutex
+	clientsMutex  sync.RWMutex
 	ttl           time.Duration
 	lastUpdate    time.Time
+
+	path      string
+	saveMutex sync.Mutex
 }
 
 func NewCache(ttl time.Duration) *Cache {
@@ -32,6 +37,19 @@ func (c *Cache) SetProjects(projects []api.Project) {
 	defer c.projectsMutex.Unlock()
 	c.projects = projects
 	c.lastUpdate = time.Now()
+
+	// Drop tasks of projects that no longer exist so they are not persisted.
+	known := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		known[p.ID] = true
+	}
+	c.tasksMutex.Lock()
+	defer c.tasksMutex.Unlock()
+	for projectID := range c.tasks {
+		if !known[projectID] {
+			delete(c.tasks, projectID)
+		}
+	}
 }
 
 func (c *Cache) GetProjects() ([]api.Project, bool) {
@@ -80,6 +98,27 @@ func (c *Cache) GetTags() ([]api.Tag, bool) {
 	return c.tags, true
 }
 
+func (c *Cache) SetClients(clients []api.ClockifyClient) {
+	c.clientsMutex.Lock()
+	defer c.clientsMutex.Unlock()
+	if clients == nil {
+		// Distinguish "no clients in the workspace" from "not loaded yet".
+		clients = []api.ClockifyClient{}
+	}
+	c.clients = clients
+}
+
+func (c *Cache) GetClients() ([]api.ClockifyClient, bool) {
+	c.clientsMutex.RLock()
+	defer c.clientsMutex.RUnlock()
+
+	if c.IsExpired() || c.clients == nil {
+		return nil, false
+	}
+
+	return c.clients, true
+}
+
 func (c *Cache) IsExpired() bool {
 	if c.lastUpdate.IsZero() {
 		return true
@@ -91,12 +130,15 @@ func (c *Cache) Clear() {
 	c.projectsMutex.Lock()
 	c.tasksMutex.Lock()
 	c.tagsMutex.Lock()
+	c.clientsMutex.Lock()
 	defer c.projectsMutex.Unlock()
 	defer c.tasksMutex.Unlock()
 	defer c.tagsMutex.Unlock()
+	defer c.clientsMutex.Unlock()
 
 	c.projects = nil
 	c.tasks = make(map[string][]api.Task)
 	c.tags = nil
+	c.clients = nil
 	c.lastUpdate = time.Time{}
 }
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"main/internal/api"
+)
+
+// diskFormatVersion must be bumped whenever the snapshot layout or the cached
+// API models change; files with another version are discarded on load.
+const diskFormatVersion = 3
+
+type diskSnapshot struct {
+	Version  int                   `json:"version"`
+	SavedAt  time.Time             `json:"savedAt"`
+	Projects []api.Project         `json:"projects"`
+	Tasks    map[string][]api.Task `json:"tasks"`
+	Tags     []api.Tag             `json:"tags"`
+	Clients  []api.ClockifyClient  `json:"clients"`
+}
+
+// Snapshot is a copy of everything the cache holds, regardless of expiry.
+type Snapshot struct {
+	Projects []api.Project
+	Tasks    map[string][]api.Task
+	Tags     []api.Tag
+	Clients  []api.ClockifyClient
+	SavedAt  time.Time
+}
+
+// DiskPath returns the cache file for a workspace under the user cache
+// directory ($XDG_CACHE_HOME/clockify-tui on Linux).
+func DiskPath(workspaceID string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "clockify-tui", workspaceID+".json"), nil
+}
+
+// NewDiskCache returns a cache that can be restored from and saved to path.
+func NewDiskCache(ttl time.Duration, path string) *Cache {
+	c := NewCache(ttl)
+	c.path = path
+	return c
+}
+
+// IsPersistent reports whether the cache is backed by a file.
+func (c *Cache) IsPersistent() bool {
+	c.saveMutex.Lock()
+	defer c.saveMutex.Unlock()
+	return c.path != ""
+}
+
+// SetPath points the cache at another file, e.g. after a workspace switch.
+func (c *Cache) SetPath(path string) {
+	c.saveMutex.Lock()
+	defer c.saveMutex.Unlock()
+	c.path = path
+}
+
+// Load restores the cache from disk. Data older than the TTL is kept but
+// reported as expired by the getters. A missing file is not an error; an
+// unreadable or outdated one is removed.
+func (c *Cache) Load() (bool, error) {
+	c.saveMutex.Lock()
+	path := c.path
+	c.saveMutex.Unlock()
+	if path == "" {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var snapshot diskSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.Version != diskFormatVersion {
+		os.Remove(path)
+		return false, nil
+	}
+
+	c.projectsMutex.Lock()
+	c.tasksMutex.Lock()
+	c.tagsMutex.Lock()
+	c.clientsMutex.Lock()
+	defer c.projectsMutex.Unlock()
+	defer c.tasksMutex.Unlock()
+	defer c.tagsMutex.Unlock()
+	defer c.clientsMutex.Unlock()
+
+	c.projects = snapshot.Projects
+	c.tasks = snapshot.Tasks
+	if c.tasks == nil {
+		c.tasks = make(map[string][]api.Task)
+	}
+	c.tags = snapshot.Tags
+	c.clients = snapshot.Clients
+	c.lastUpdate = snapshot.SavedAt
+
+	return true, nil
+}
+
+// Save writes the cache to disk, replacing the previous file atomically.
+func (c *Cache) Save() error {
+	c.saveMutex.Lock()
+	defer c.saveMutex.Unlock()
+
+	if c.path == "" {
+		return nil
+	}
+
+	current := c.Snapshot()
+	if current.SavedAt.IsZero() {
+		return nil
+	}
+
+	data, err := json.Marshal(diskSnapshot{
+		Version:  diskFormatVersion,
+		SavedAt:  current.SavedAt,
+		Projects: current.Projects,
+		Tasks:    current.Tasks,
+		Tags:     current.Tags,
+		Clients:  current.Clients,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cache-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+func (c *Cache) Snapshot() Snapshot {
+	c.projectsMutex.RLock()
+	c.tasksMutex.RLock()
+	c.tagsMutex.RLock()
+	c.clientsMutex.RLock()
+	defer c.projectsMutex.RUnlock()
+	defer c.tasksMutex.RUnlock()
+	defer c.tagsMutex.RUnlock()
+	defer c.clientsMutex.RUnlock()
+
+	tasks := make(map[string][]api.Task, len(c.tasks))
+	for projectID, projectTasks := range c.tasks {
+		tasks[projectID] = projectTasks
+	}
+
+	return Snapshot{
+		Projects: c.projects,
+		Tasks:    tasks,
+		Tags:     c.tags,
+		Clients:  c.clients,
+		SavedAt:  c.lastUpdate,
+	}
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Identity is the part of the current user needed to start without network
+// access.
+type Identity struct {
+	UserID          string `json:"userId"`
+	ActiveWorkspace string `json:"activeWorkspace"`
+}
+
+// identityPath keys the file by a hash of the credentials so that profiles
+// for different accounts do not share an identity, without storing the key.
+func identityPath(apiKey, baseURL string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + apiKey))
+	return filepath.Join(dir, "clockify-tui", "identity-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func SaveIdentity(apiKey, baseURL string, identity Identity) error {
+	path, err := identityPath(apiKey, baseURL)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func LoadIdentity(apiKey, baseURL string) (*Identity, bool) {
+	path, err := identityPath(apiKey, baseURL)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var identity Identity
+	if err := json.Unmarshal(data, &identity); err != nil || identity.UserID == "" {
+		return nil, false
+	}
+	return &identity, true
+}
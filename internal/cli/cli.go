@@ -0,0 +1,878 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/cache"
+	"main/internal/domain"
+	"main/internal/export"
+	"main/internal/importer"
+	"main/internal/journal"
+)
+
+const usage = `Usage: clockify-tui [--profile NAME] [command] [flags]
+
+Without a command the interactive TUI is started.
+
+Global flags:
+  --profile NAME Use the named profile from the config file
+
+Commands:
+  start [--project P] [--task T] [--tag TAG]... [--billable[=false]] [description]
+                 Start a timer; project, task and tags accept names or IDs.
+                 Billable defaults to the project's setting
+  stop           Stop the running timer
+  status         Show the running timer
+  continue       Restart the most recent stopped entry
+  list [--date YYYY-MM-DD] [--week | --month | --from/--to]
+                 List time entries for a day (default today), or the week or
+                 month containing --date
+  report [--date YYYY-MM-DD] [--week | --month]
+  report --from YYYY-MM-DD --to YYYY-MM-DD
+                 Summarize tracked time by project and task, with billable
+                 hours and earnings
+                 --group DIM[,DIM] groups by project, client, task, tag,
+                 description or weekday instead, e.g. --group tag,project
+                 Filters: --project P, --client C, --tag TAG, --task T
+                 (repeatable; tasks require --project), --user me|all|NAME
+                 (default me), --billable[=false]
+  export entries [--date YYYY-MM-DD] [--week | --month | --from/--to]
+  export report [report flags]
+                 Write entries or a report as CSV (default), JSON or a
+                 Markdown table: --format csv|json|md, --output FILE
+                 (default stdout; --force replaces an existing file)
+  import [--format csv|toggl|timewarrior] [--create] [--allow-overlaps]
+         [--commit] FILE
+                 Import entries from CSV, a Toggl CSV export or "timew export"
+                 JSON. Shows what would be imported; --commit creates the
+                 entries, --create also creates missing projects, tasks and
+                 tags. Duplicates, and overlaps unless allowed, are skipped
+  sync           Send changes recorded while offline to Clockify
+  help           Show this help
+
+Every command except help accepts --json for machine-readable output.
+
+When Clockify cannot be reached, start and stop are recorded locally and
+sent by the next command that finds the network back.
+`
+
+var commands = map[string]bool{
+	"start":    true,
+	"stop":     true,
+	"status":   true,
+	"continue": true,
+	"list":     true,
+	"report":   true,
+	"export":   true,
+	"import":   true,
+	"sync":     true,
+	"help":     true,
+}
+
+func IsCommand(name string) bool {
+	return commands[name]
+}
+
+func IsHelp(name string) bool {
+	return name == "help" || name == "-h" || name == "--help"
+}
+
+func PrintUsage(out io.Writer) {
+	fmt.Fprint(out, usage)
+}
+
+type Runner struct {
+	timerService     *domain.TimerService
+	entryService     *domain.TimeEntryService
+	reportService    *domain.ReportService
+	projectService   *domain.ProjectService
+	tagService       *domain.TagService
+	clientService    *domain.ClientService
+	workspaceService *domain.WorkspaceService
+	syncService      *domain.SyncService
+	importService    *domain.ImportService
+	out              io.Writer
+	errOut           io.Writer
+}
+
+func NewRunner(client *api.Client, cacheInstance *cache.Cache, j *journal.Journal, out io.Writer) *Runner {
+	entryService := domain.NewTimeEntryService(client, j)
+	projectService := domain.NewProjectService(client, cacheInstance)
+	tagService := domain.NewTagService(client, cacheInstance)
+
+	return &Runner{
+		timerService:     domain.NewTimerService(client, domain.NewTimerState(), j),
+		entryService:     entryService,
+		reportService:    domain.NewReportService(client),
+		projectService:   projectService,
+		tagService:       tagService,
+		clientService:    domain.NewClientService(client, cacheInstance),
+		workspaceService: domain.NewWorkspaceService(client, cacheInstance),
+		syncService:      domain.NewSyncService(client, j),
+		importService:    domain.NewImportService(projectService, tagService, entryService),
+		out:              out,
+		errOut:           os.Stderr,
+	}
+}
+
+func (r *Runner) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || IsHelp(args[0]) {
+		PrintUsage(r.out)
+		return nil
+	}
+
+	name, rest := args[0], args[1:]
+	if name == "sync" {
+		return r.runSync(ctx, rest)
+	}
+
+	if err := r.syncPending(ctx); err != nil {
+		return err
+	}
+	defer r.reportPending()
+
+	switch name {
+	case "start":
+		return r.runStart(ctx, rest)
+	case "stop":
+		return r.runStop(ctx, rest)
+	case "status":
+		return r.runStatus(ctx, rest)
+	case "continue":
+		return r.runContinue(ctx, rest)
+	case "list":
+		return r.runList(ctx, rest)
+	case "report":
+		return r.runReport(ctx, rest)
+	case "export":
+		return r.runExport(ctx, rest)
+	case "import":
+		return r.runImport(ctx, rest)
+	}
+
+	return fmt.Errorf("unknown command %q (run 'clockify-tui help' for usage)", name)
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func newFlagSet(name string, jsonOutput *bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.BoolVar(jsonOutput, "json", false, "print JSON instead of plain text")
+	return fs
+}
+
+func (r *Runner) runStart(ctx context.Context, args []string) error {
+	var jsonOutput, billableArg bool
+	var projectArg, taskArg string
+	var tagArgs stringList
+
+	fs := newFlagSet("start", &jsonOutput)
+	fs.StringVar(&projectArg, "project", "", "project name or ID")
+	fs.StringVar(&taskArg, "task", "", "task name or ID (requires --project)")
+	fs.Var(&tagArgs, "tag", "tag name or ID (repeatable)")
+	fs.BoolVar(&billableArg, "billable", false, "mark the entry billable (default: the project's setting)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if taskArg != "" && projectArg == "" {
+		return errors.New("--task requires --project")
+	}
+
+	var projectID, taskID *string
+	billable := false
+	if projectArg != "" {
+		project, err := r.projectService.FindProject(ctx, projectArg)
+		if err != nil {
+			return err
+		}
+		projectID = &project.ID
+		billable = project.Billable
+
+		if taskArg != "" {
+			task, err := r.projectService.FindTask(ctx, project.ID, taskArg)
+			if err != nil {
+				return err
+			}
+			taskID = &task.ID
+		}
+	}
+
+	tagIDs := []string{}
+	for _, tagArg := range tagArgs {
+		tag, err := r.tagService.FindTag(ctx, tagArg)
+		if err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "billable" {
+			billable = billableArg
+		}
+	})
+
+	description := strings.Join(fs.Args(), " ")
+	entry, err := r.timerService.StartTimer(ctx, description, projectID, taskID, tagIDs, billable)
+	if err != nil {
+		return err
+	}
+
+	return r.printEntry(ctx, "Started", entry, jsonOutput)
+}
+
+func (r *Runner) runStop(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	fs := newFlagSet("stop", &jsonOutput)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entry, alreadyStopped, err := r.timerService.StopTimer(ctx)
+	if err != nil {
+		return err
+	}
+	if alreadyStopped {
+		return r.printNoTimer(jsonOutput)
+	}
+	if entry == nil {
+		return r.printStopQueued(jsonOutput)
+	}
+
+	return r.printEntry(ctx, "Stopped", entry, jsonOutput)
+}
+
+func (r *Runner) runStatus(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	fs := newFlagSet("status", &jsonOutput)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entry, err := r.timerService.GetCurrentTimer(ctx)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return r.printNoTimer(jsonOutput)
+	}
+
+	return r.printEntry(ctx, "Running", entry, jsonOutput)
+}
+
+func (r *Runner) runContinue(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	fs := newFlagSet("continue", &jsonOutput)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	last, err := r.entryService.GetMostRecentEntry(ctx, 30*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		return errors.New("no recent time entry to continue")
+	}
+
+	tagIDs := last.TagIDs
+	if tagIDs == nil {
+		tagIDs = []string{}
+	}
+
+	entry, err := r.timerService.StartTimer(ctx, last.Description, last.ProjectID, last.TaskID, tagIDs, last.Billable)
+	if err != nil {
+		return err
+	}
+
+	return r.printEntry(ctx, "Started", entry, jsonOutput)
+}
+
+func (r *Runner) runList(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	var periodArgs periodArgs
+
+	fs := newFlagSet("list", &jsonOutput)
+	periodArgs.register(fs, "list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	p, err := periodArgs.parse()
+	if err != nil {
+		return err
+	}
+
+	entries, err := r.entryService.GetEntriesForRange(ctx, p.first, p.last.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	names, err := r.resolveNames(ctx, entries)
+	if err != nil {
+		return err
+	}
+
+	return r.printEntries(entries, names, jsonOutput)
+}
+
+// reportFilterArgs are the report flags that narrow what is summarized.
+type reportFilterArgs struct {
+	projects, clients, tags, tasks stringList
+	user                           string
+	billable                       *bool
+}
+
+// periodArgs are the flags choosing the days a list, report or export
+// covers.
+type periodArgs struct {
+	date, from, to string
+	week, month    bool
+}
+
+func (a *periodArgs) register(fs *flag.FlagSet, what string) {
+	fs.StringVar(&a.date, "date", "", "day of the "+what+" (YYYY-MM-DD, default today)")
+	fs.BoolVar(&a.week, "week", false, "the week containing --date")
+	fs.BoolVar(&a.month, "month", false, "the month containing --date")
+	fs.StringVar(&a.from, "from", "", "first day of a custom range (YYYY-MM-DD, requires --to)")
+	fs.StringVar(&a.to, "to", "", "last day of a custom range (YYYY-MM-DD, requires --from)")
+}
+
+type periodKind int
+
+const (
+	periodDay periodKind = iota
+	periodWeek
+	periodMonth
+	periodRange
+)
+
+// period is a parsed periodArgs: the days from first to last, both included.
+type period struct {
+	kind    periodKind
+	first   time.Time
+	last    time.Time
+	heading string
+}
+
+func (a *periodArgs) parse() (period, error) {
+	date, err := parseDateArg(a.date)
+	if err != nil {
+		return period{}, err
+	}
+
+	if a.from != "" || a.to != "" {
+		if a.from == "" || a.to == "" {
+			return period{}, errors.New("--from and --to must be used together")
+		}
+		if a.week || a.month || a.date != "" {
+			return period{}, errors.New("--from/--to cannot be combined with --date, --week or --month")
+		}
+		first, err := parseDayFlag("from", a.from)
+		if err != nil {
+			return period{}, err
+		}
+		last, err := parseDayFlag("to", a.to)
+		if err != nil {
+			return period{}, err
+		}
+		if last.Before(first) {
+			return period{}, errors.New("--to must not be before --from")
+		}
+		heading := fmt.Sprintf("%s - %s", first.Format("Jan 2, 2006"), last.Format("Jan 2, 2006"))
+		return period{periodRange, first, last, heading}, nil
+	}
+
+	switch {
+	case a.week && a.month:
+		return period{}, errors.New("--week and --month cannot be combined")
+	case a.month:
+		first := domain.MonthStart(date)
+		return period{periodMonth, first, first.AddDate(0, 1, -1), first.Format("January 2006")}, nil
+	case a.week:
+		first := domain.WeekStart(date)
+		last := first.AddDate(0, 0, 6)
+		heading := fmt.Sprintf("Week of %s - %s", first.Format("Jan 2"), last.Format("Jan 2, 2006"))
+		return period{periodWeek, first, last, heading}, nil
+	}
+	return period{periodDay, date, date, date.Format("Monday, January 2, 2006")}, nil
+}
+
+// reportArgs are the flags shared by report and export report.
+type reportArgs struct {
+	period   periodArgs
+	filter   reportFilterArgs
+	group    string
+	billable bool
+}
+
+func (a *reportArgs) register(fs *flag.FlagSet) {
+	a.period.register(fs, "report")
+	fs.StringVar(&a.group, "group", "", "group by one or two of project, client, task, tag, description, weekday (e.g. tag,project)")
+	fs.Var(&a.filter.projects, "project", "only time on this project, by name or ID (repeatable)")
+	fs.Var(&a.filter.clients, "client", "only time for this client, by name or ID (repeatable)")
+	fs.Var(&a.filter.tags, "tag", "only time with this tag, by name or ID (repeatable)")
+	fs.Var(&a.filter.tasks, "task", "only time on this task of a --project, by name or ID (repeatable)")
+	fs.StringVar(&a.filter.user, "user", "me", "whose time: me, all, or a user's name, email or ID")
+	fs.BoolVar(&a.billable, "billable", false, "only billable time, or with =false only non-billable time")
+}
+
+// reportQuery is a parsed reportArgs with the filter resolved to IDs.
+type reportQuery struct {
+	period    period
+	groupings []domain.GroupBy
+	filter    api.ReportFilter
+	labels    []string
+}
+
+func (r *Runner) parseReportArgs(ctx context.Context, fs *flag.FlagSet, a *reportArgs) (*reportQuery, error) {
+	p, err := a.period.parse()
+	if err != nil {
+		return nil, err
+	}
+	if len(a.filter.tasks) > 0 && len(a.filter.projects) == 0 {
+		return nil, errors.New("--task requires --project")
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "billable" {
+			a.filter.billable = &a.billable
+		}
+	})
+
+	q := &reportQuery{period: p}
+	if a.group != "" {
+		if q.groupings, err = parseGroupings(a.group); err != nil {
+			return nil, err
+		}
+	}
+
+	q.filter, q.labels, err = r.reportFilter(ctx, a.filter)
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// loadReport fetches the report a query asks for: a *domain.GroupedReport
+// when grouped, otherwise a daily, weekly or range summary.
+func (r *Runner) loadReport(ctx context.Context, q *reportQuery) (any, error) {
+	p := q.period
+	if q.groupings != nil {
+		return r.reportService.GetGroupedReport(ctx, p.first, p.last, q.filter, q.groupings[0], q.groupings[1])
+	}
+
+	switch p.kind {
+	case periodWeek:
+		return r.reportService.GetWeeklySummary(ctx, p.first, q.filter)
+	case periodMonth, periodRange:
+		return r.reportService.GetRangeSummary(ctx, p.first, p.last, q.filter)
+	}
+	return r.reportService.GetDailySummary(ctx, p.first, q.filter)
+}
+
+func (r *Runner) runReport(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	var reportArgs reportArgs
+
+	fs := newFlagSet("report", &jsonOutput)
+	reportArgs.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q, err := r.parseReportArgs(ctx, fs, &reportArgs)
+	if err != nil {
+		return err
+	}
+
+	report, err := r.loadReport(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	switch report := report.(type) {
+	case *domain.GroupedReport:
+		err = r.printGroupedReport(q.period.heading, report, jsonOutput)
+	case *domain.WeeklySummary:
+		err = r.printWeeklyReport(report, jsonOutput)
+	case *domain.RangeSummary:
+		err = r.printRangeReport(q.period.heading, report, jsonOutput)
+	case *domain.DailySummary:
+		err = r.printDailyReport(report, jsonOutput)
+	}
+	return r.printFilter(q.labels, jsonOutput, err)
+}
+
+func (r *Runner) runExport(ctx context.Context, args []string) error {
+	if len(args) == 0 || (args[0] != "entries" && args[0] != "report") {
+		return errors.New("export needs what to export: entries or report")
+	}
+	kind, args := args[0], args[1:]
+
+	var jsonOutput, force bool
+	var formatArg, outputArg string
+	var periodArgs periodArgs
+	var reportArgs reportArgs
+
+	fs := newFlagSet("export "+kind, &jsonOutput)
+	fs.StringVar(&formatArg, "format", "csv", "csv, json or md")
+	fs.StringVar(&outputArg, "output", "", "file to write (default stdout)")
+	fs.BoolVar(&force, "force", false, "replace the --output file if it exists")
+	if kind == "entries" {
+		periodArgs.register(fs, "export")
+	} else {
+		reportArgs.register(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(formatArg)
+	if err != nil {
+		return err
+	}
+
+	var table export.Table
+	if kind == "entries" {
+		table, err = r.entriesTable(ctx, &periodArgs)
+	} else {
+		table, err = r.reportTable(ctx, fs, &reportArgs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outputArg == "" || outputArg == "-" {
+		return export.Write(r.out, format, table)
+	}
+	if err := export.WriteFile(outputArg, format, table, force); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w (use --force to replace it)", err)
+		}
+		return err
+	}
+	return r.printExported(outputArg, len(table.Rows), jsonOutput)
+}
+
+// runImport shows what importing a file would do, and does it with
+// --commit.
+func (r *Runner) runImport(ctx context.Context, args []string) error {
+	var jsonOutput, commit bool
+	var formatArg string
+	var opts domain.ImportOptions
+
+	fs := newFlagSet("import", &jsonOutput)
+	fs.StringVar(&formatArg, "format", "auto", "csv, toggl or timewarrior (default: detected)")
+	fs.BoolVar(&opts.CreateMissing, "create", false, "create missing projects, tasks and tags")
+	fs.BoolVar(&opts.AllowOverlaps, "allow-overlaps", false, "import entries that overlap others")
+	fs.BoolVar(&commit, "commit", false, "create the entries instead of only showing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("import needs the file to read")
+	}
+
+	format, err := importer.ParseFormat(formatArg)
+	if err != nil {
+		return err
+	}
+	records, format, err := importer.ReadFile(fs.Arg(0), format)
+	if err != nil {
+		return err
+	}
+	plan, err := r.importService.Plan(ctx, records, opts)
+	if err != nil {
+		return err
+	}
+
+	out := toImportOutput(plan, format)
+	if !commit {
+		return r.printImport(out, jsonOutput)
+	}
+
+	out.Committed = true
+	out.Created, err = r.importService.Commit(ctx, plan)
+	// Commit drops names from the plan as it creates them.
+	out.NewProjects = out.NewProjects[:len(out.NewProjects)-len(plan.NewProjects)]
+	out.NewTasks = out.NewTasks[:len(out.NewTasks)-len(plan.NewTasks)]
+	out.NewTags = out.NewTags[:len(out.NewTags)-len(plan.NewTags)]
+	if printErr := r.printImport(out, jsonOutput); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func (r *Runner) entriesTable(ctx context.Context, a *periodArgs) (export.Table, error) {
+	p, err := a.parse()
+	if err != nil {
+		return export.Table{}, err
+	}
+
+	entries, err := r.entryService.GetEntriesForRange(ctx, p.first, p.last.AddDate(0, 0, 1))
+	if err != nil {
+		return export.Table{}, err
+	}
+	names, err := r.resolveNames(ctx, entries)
+	if err != nil {
+		return export.Table{}, err
+	}
+
+	// Oldest first reads naturally in a spreadsheet.
+	slices.Reverse(entries)
+	return export.Entries(p.heading, entries, names), nil
+}
+
+func (r *Runner) reportTable(ctx context.Context, fs *flag.FlagSet, a *reportArgs) (export.Table, error) {
+	q, err := r.parseReportArgs(ctx, fs, a)
+	if err != nil {
+		return export.Table{}, err
+	}
+	report, err := r.loadReport(ctx, q)
+	if err != nil {
+		return export.Table{}, err
+	}
+
+	var table export.Table
+	switch report := report.(type) {
+	case *domain.GroupedReport:
+		table = export.GroupedReport(q.period.heading, report)
+	case *domain.WeeklySummary:
+		table = export.WeeklyReport(report)
+	case *domain.RangeSummary:
+		table = export.RangeReport(q.period.heading, report)
+	case *domain.DailySummary:
+		table = export.DailyReport(report)
+	}
+	if len(q.labels) > 0 {
+		table.Notes = append(table.Notes, "Filter: "+strings.Join(q.labels, ", "))
+	}
+	return table, nil
+}
+
+// reportFilter resolves the filter flags to IDs, and describes the filter
+// for the text output.
+func (r *Runner) reportFilter(ctx context.Context, args reportFilterArgs) (api.ReportFilter, []string, error) {
+	var filter api.ReportFilter
+	var labels []string
+
+	var projects []*api.Project
+	for _, arg := range args.projects {
+		project, err := r.projectService.FindProject(ctx, arg)
+		if err != nil {
+			return filter, nil, err
+		}
+		projects = append(projects, project)
+		filter.ProjectIDs = append(filter.ProjectIDs, project.ID)
+		labels = append(labels, "project "+project.Name)
+	}
+
+	for _, arg := range args.tasks {
+		var task *api.Task
+		var err error
+		for _, project := range projects {
+			if task, err = r.projectService.FindTask(ctx, project.ID, arg); err == nil {
+				break
+			}
+		}
+		if task == nil {
+			return filter, nil, err
+		}
+		filter.TaskIDs = append(filter.TaskIDs, task.ID)
+		labels = append(labels, "task "+task.Name)
+	}
+
+	for _, arg := range args.clients {
+		client, err := r.clientService.FindClient(ctx, arg)
+		if err != nil {
+			return filter, nil, err
+		}
+		filter.ClientIDs = append(filter.ClientIDs, client.ID)
+		labels = append(labels, "client "+client.Name)
+	}
+
+	for _, arg := range args.tags {
+		tag, err := r.tagService.FindTag(ctx, arg)
+		if err != nil {
+			return filter, nil, err
+		}
+		filter.TagIDs = append(filter.TagIDs, tag.ID)
+		labels = append(labels, "tag "+tag.Name)
+	}
+
+	switch strings.ToLower(args.user) {
+	case "", "me":
+		filter.UserIDs = []string{r.reportService.CurrentUserID()}
+	case "all":
+		labels = append(labels, "all users")
+	default:
+		user, err := r.workspaceService.FindUser(ctx, args.user)
+		if err != nil {
+			return filter, nil, err
+		}
+		filter.UserIDs = []string{user.ID}
+		labels = append(labels, "user "+user.Name)
+	}
+
+	if args.billable != nil {
+		filter.Billable = args.billable
+		if *args.billable {
+			labels = append(labels, "billable")
+		} else {
+			labels = append(labels, "non-billable")
+		}
+	}
+
+	return filter, labels, nil
+}
+
+func (r *Runner) runSync(ctx context.Context, args []string) error {
+	var jsonOutput bool
+	fs := newFlagSet("sync", &jsonOutput)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	result, err := r.syncService.Replay(ctx)
+	if err != nil {
+		return err
+	}
+	return r.printSyncResult(result, jsonOutput)
+}
+
+// syncPending replays the offline journal before a command so that it sees
+// the server state including earlier offline changes.
+func (r *Runner) syncPending(ctx context.Context) error {
+	if r.syncService.PendingCount() == 0 {
+		return nil
+	}
+
+	result, err := r.syncService.Replay(ctx)
+	if err != nil {
+		return err
+	}
+	if result.Applied > 0 {
+		fmt.Fprintf(r.errOut, "Synced %d offline change(s)\n", result.Applied)
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(r.errOut, "Sync conflict: %s\n", conflict.Reason)
+	}
+	return nil
+}
+
+func (r *Runner) reportPending() {
+	if pending := r.syncService.PendingCount(); pending > 0 {
+		fmt.Fprintf(r.errOut, "Offline: %d change(s) queued, they will be sent once Clockify is reachable\n", pending)
+	}
+}
+
+// parseGroupings reads --group: one or two comma-separated dimensions. The
+// second is GroupNone when only one is given.
+func parseGroupings(value string) ([]domain.GroupBy, error) {
+	names := strings.Split(value, ",")
+	if len(names) > 2 {
+		return nil, errors.New("--group takes at most two dimensions")
+	}
+
+	groupings := []domain.GroupBy{domain.GroupNone, domain.GroupNone}
+	for i, name := range names {
+		g, err := domain.ParseGroupBy(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		groupings[i] = g
+	}
+	if groupings[0] == groupings[1] {
+		return nil, errors.New("--group dimensions must differ")
+	}
+	return groupings, nil
+}
+
+// parseDateArg returns the start of the day given by value, or of today
+// when it is empty.
+func parseDateArg(value string) (time.Time, error) {
+	if value == "" {
+		return domain.DayStart(time.Now()), nil
+	}
+	return parseDayFlag("date", value)
+}
+
+func parseDayFlag(name, value string) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q, expected YYYY-MM-DD", name, value)
+	}
+	return date, nil
+}
+
+// resolveNames looks up project, task and tag names, fetching tasks only for
+// the projects that appear in the given entries.
+func (r *Runner) resolveNames(ctx context.Context, entries []api.TimeEntry) (*domain.Names, error) {
+	names, err := r.loadProjectAndTagNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.ProjectID == nil || seen[*entry.ProjectID] {
+			continue
+		}
+		seen[*entry.ProjectID] = true
+		r.addTaskNames(ctx, names, *entry.ProjectID)
+	}
+
+	return names, nil
+}
+
+func (r *Runner) loadProjectAndTagNames(ctx context.Context) (*domain.Names, error) {
+	names := domain.NewNames()
+
+	projects, err := r.projectService.GetAllProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names.AddProjects(projects)
+
+	tags, err := r.tagService.GetAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names.AddTags(tags)
+
+	// Client names are only decoration; entries and reports still work
+	// without them.
+	if clients, err := r.clientService.GetAllClients(ctx); err == nil {
+		names.AddClients(clients)
+	}
+
+	return names, nil
+}
+
+func (r *Runner) addTaskNames(ctx context.Context, names *domain.Names, projectID string) {
+	tasks, err := r.projectService.GetTasksForProject(ctx, projectID)
+	if err != nil {
+		return
+	}
+	names.AddTasks(tasks)
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/api"
+	"main/internal/cache"
+	"main/internal/domain"
+	"main/internal/journal"
+)
+
+// entryRangeRecorder is a Clockify server without any data that records the
+// range of the last time entries request.
+type entryRangeRecorder struct {
+	start, end time.Time
+}
+
+func newTestRunner(t *testing.T) (*Runner, *entryRangeRecorder) {
+	t.Helper()
+
+	recorder := &entryRangeRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/time-entries") {
+			recorder.start, _ = time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+			recorder.end, _ = time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[]")
+	}))
+	t.Cleanup(server.Close)
+
+	client := api.NewClient("key", server.URL)
+	client.SetWorkspace("ws")
+	client.SetUserID("user")
+	j := journal.Open(filepath.Join(t.TempDir(), "journal.jsonl"))
+	return NewRunner(client, cache.NewCache(time.Minute), j, io.Discard), recorder
+}
+
+func TestPeriodArgsDefaultsToToday(t *testing.T) {
+	var a periodArgs
+	p, err := a.parse()
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	today := domain.DayStart(time.Now())
+	if p.kind != periodDay || !p.first.Equal(today) || !p.last.Equal(today) {
+		t.Errorf("period = %v %v-%v, want the day %v", p.kind, p.first, p.last, today)
+	}
+}
+
+func TestListDefaultsToWholeDay(t *testing.T) {
+	r, recorder := newTestRunner(t)
+
+	if err := r.Run(context.Background(), []string{"list"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	today := domain.DayStart(time.Now())
+	if !recorder.start.Equal(today) || !recorder.end.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("listed %v-%v, want all of %v", recorder.start, recorder.end, today)
+	}
+}
+
+func TestExportEntriesDefaultsToWholeDay(t *testing.T) {
+	r, recorder := newTestRunner(t)
+
+	if _, err := r.entriesTable(context.Background(), &periodArgs{}); err != nil {
+		t.Fatalf("entriesTable: %v", err)
+	}
+
+	today := domain.DayStart(time.Now())
+	if !recorder.start.Equal(today) || !recorder.end.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("exported %v-%v, want all of %v", recorder.start, recorder.end, today)
+	}
+}
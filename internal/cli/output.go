@@ -0,0 +1,702 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/domain"
+	"main/internal/importer"
+)
+
+type entryOutput struct {
+	ID              string     `json:"id"`
+	Description     string     `json:"description"`
+	ProjectID       *string    `json:"projectId,omitempty"`
+	Project         string     `json:"project,omitempty"`
+	Client          string     `json:"client,omitempty"`
+	TaskID          *string    `json:"taskId,omitempty"`
+	Task            string     `json:"task,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end,omitempty"`
+	DurationSeconds int64      `json:"durationSeconds"`
+	Running         bool       `json:"running"`
+	Billable        bool       `json:"billable"`
+}
+
+type syncOutput struct {
+	Applied   int      `json:"applied"`
+	Conflicts []string `json:"conflicts"`
+	Remaining int      `json:"remaining"`
+}
+
+type statusOutput struct {
+	Running bool         `json:"running"`
+	Entry   *entryOutput `json:"entry,omitempty"`
+}
+
+type clientOutput struct {
+	ID              string          `json:"id"`
+	Name            string          `json:"name"`
+	DurationSeconds int64           `json:"durationSeconds"`
+	Projects        []projectOutput `json:"projects"`
+}
+
+type projectOutput struct {
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	ClientID        string       `json:"clientId,omitempty"`
+	Client          string       `json:"client,omitempty"`
+	DurationSeconds int64        `json:"durationSeconds"`
+	Tasks           []taskOutput `json:"tasks"`
+}
+
+type taskOutput struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	DurationSeconds int64  `json:"durationSeconds"`
+}
+
+// billingOutput holds earnings as decimal amounts keyed by currency code.
+type billingOutput struct {
+	BillableDurationSeconds    int64              `json:"billableDurationSeconds"`
+	NonBillableDurationSeconds int64              `json:"nonBillableDurationSeconds"`
+	Earnings                   map[string]float64 `json:"earnings"`
+}
+
+type dailyReportOutput struct {
+	Date                 string `json:"date"`
+	TotalDurationSeconds int64  `json:"totalDurationSeconds"`
+	// RunningDurationSeconds is the part of the total tracked by the
+	// running timer, not included in the clients and projects.
+	RunningDurationSeconds int64 `json:"runningDurationSeconds,omitempty"`
+	billingOutput
+	Clients  []clientOutput  `json:"clients"`
+	Projects []projectOutput `json:"projects"`
+}
+
+type weeklyReportOutput struct {
+	StartDate              string `json:"startDate"`
+	EndDate                string `json:"endDate"`
+	TotalDurationSeconds   int64  `json:"totalDurationSeconds"`
+	RunningDurationSeconds int64  `json:"runningDurationSeconds,omitempty"`
+	billingOutput
+	ByDaySeconds map[string]int64 `json:"byDaySeconds"`
+	Clients      []clientOutput   `json:"clients"`
+	Projects     []projectOutput  `json:"projects"`
+}
+
+type rangeReportOutput struct {
+	StartDate              string `json:"startDate"`
+	EndDate                string `json:"endDate"`
+	TotalDurationSeconds   int64  `json:"totalDurationSeconds"`
+	RunningDurationSeconds int64  `json:"runningDurationSeconds,omitempty"`
+	billingOutput
+	ByDaySeconds  map[string]int64 `json:"byDaySeconds"`
+	ByWeekSeconds map[string]int64 `json:"byWeekSeconds"`
+	Clients       []clientOutput   `json:"clients"`
+	Projects      []projectOutput  `json:"projects"`
+}
+
+type exportOutput struct {
+	Path string `json:"path"`
+	Rows int    `json:"rows"`
+}
+
+type importItemOutput struct {
+	Line        int       `json:"line"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Description string    `json:"description"`
+	Project     string    `json:"project,omitempty"`
+	Task        string    `json:"task,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Billable    bool      `json:"billable"`
+}
+
+type importOutput struct {
+	Format      string             `json:"format"`
+	Committed   bool               `json:"committed"`
+	Created     int                `json:"created"`
+	Ready       int                `json:"ready"`
+	Duplicates  int                `json:"duplicates"`
+	Overlaps    int                `json:"overlaps"`
+	Invalid     int                `json:"invalid"`
+	NewProjects []string           `json:"newProjects"`
+	NewTasks    []string           `json:"newTasks"`
+	NewTags     []string           `json:"newTags"`
+	Items       []importItemOutput `json:"items"`
+}
+
+type groupOutput struct {
+	Key             string        `json:"key,omitempty"`
+	Name            string        `json:"name"`
+	DurationSeconds int64         `json:"durationSeconds"`
+	Groups          []groupOutput `json:"groups,omitempty"`
+}
+
+type groupedReportOutput struct {
+	StartDate            string   `json:"startDate"`
+	EndDate              string   `json:"endDate"`
+	GroupBy              []string `json:"groupBy"`
+	TotalDurationSeconds int64    `json:"totalDurationSeconds"`
+	// RunningDurationSeconds is the part of the total tracked by the
+	// running timer.
+	RunningDurationSeconds int64         `json:"runningDurationSeconds,omitempty"`
+	Groups                 []groupOutput `json:"groups"`
+}
+
+func (r *Runner) writeJSON(v any) error {
+	encoder := json.NewEncoder(r.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func toEntryOutput(entry *api.TimeEntry, names *domain.Names) entryOutput {
+	out := entryOutput{
+		ID:          entry.ID,
+		Description: entry.Description,
+		ProjectID:   entry.ProjectID,
+		TaskID:      entry.TaskID,
+		Start:       entry.TimeInterval.Start,
+		End:         entry.TimeInterval.End,
+		Running:     entry.TimeInterval.End == nil,
+		Billable:    entry.Billable,
+	}
+
+	if entry.ProjectID != nil {
+		out.Project = names.Project(*entry.ProjectID)
+		if _, client, ok := names.Client(*entry.ProjectID); ok {
+			out.Client = client
+		}
+	}
+	if entry.TaskID != nil {
+		out.Task = names.Task(*entry.TaskID)
+	}
+	for _, tagID := range entry.TagIDs {
+		out.Tags = append(out.Tags, names.Tag(tagID))
+	}
+
+	out.DurationSeconds = int64(entryDuration(entry).Seconds())
+	return out
+}
+
+func entryDuration(entry *api.TimeEntry) time.Duration {
+	if entry.TimeInterval.End != nil {
+		return entry.TimeInterval.End.Sub(entry.TimeInterval.Start)
+	}
+	return time.Since(entry.TimeInterval.Start)
+}
+
+func formatEntryLine(out entryOutput) string {
+	start := out.Start.Local().Format("15:04")
+	end := "now"
+	if out.End != nil {
+		end = out.End.Local().Format("15:04")
+	}
+
+	description := out.Description
+	if description == "" {
+		description = "(no description)"
+	}
+
+	line := fmt.Sprintf("%s - %s  %-12s %s", start, end,
+		domain.FormatDuration(time.Duration(out.DurationSeconds)*time.Second), description)
+
+	if out.Project != "" {
+		line += "  ["
+		if out.Client != "" {
+			line += out.Client + " / "
+		}
+		line += out.Project
+		if out.Task != "" {
+			line += " • " + out.Task
+		}
+		line += "]"
+	}
+	if len(out.Tags) > 0 {
+		line += "  #" + strings.Join(out.Tags, " #")
+	}
+	if out.Billable {
+		line += "  $"
+	}
+
+	return line
+}
+
+func (r *Runner) printEntry(ctx context.Context, verb string, entry *api.TimeEntry, jsonOutput bool) error {
+	names, err := r.resolveNames(ctx, []api.TimeEntry{*entry})
+	if err != nil {
+		return err
+	}
+
+	out := toEntryOutput(entry, names)
+	if jsonOutput {
+		return r.writeJSON(statusOutput{Running: out.Running, Entry: &out})
+	}
+
+	_, err = fmt.Fprintf(r.out, "%s: %s\n", verb, formatEntryLine(out))
+	return err
+}
+
+func (r *Runner) printNoTimer(jsonOutput bool) error {
+	if jsonOutput {
+		return r.writeJSON(statusOutput{Running: false})
+	}
+
+	_, err := fmt.Fprintln(r.out, "No timer running")
+	return err
+}
+
+func (r *Runner) printStopQueued(jsonOutput bool) error {
+	if jsonOutput {
+		return r.writeJSON(statusOutput{Running: false})
+	}
+
+	_, err := fmt.Fprintf(r.out, "Stop queued: the running timer will be stopped at %s once Clockify is reachable\n",
+		time.Now().Format("15:04"))
+	return err
+}
+
+func (r *Runner) printEntries(entries []api.TimeEntry, names *domain.Names, jsonOutput bool) error {
+	outputs := make([]entryOutput, 0, len(entries))
+	for i := range entries {
+		outputs = append(outputs, toEntryOutput(&entries[i], names))
+	}
+
+	if jsonOutput {
+		return r.writeJSON(outputs)
+	}
+
+	if len(outputs) == 0 {
+		_, err := fmt.Fprintln(r.out, "No time entries")
+		return err
+	}
+
+	var total time.Duration
+	currentDay := ""
+	for _, out := range outputs {
+		day := out.Start.Local().Format("Monday, January 2")
+		if day != currentDay {
+			if currentDay != "" {
+				fmt.Fprintln(r.out)
+			}
+			fmt.Fprintln(r.out, day)
+			currentDay = day
+		}
+		fmt.Fprintln(r.out, "  "+formatEntryLine(out))
+		total += time.Duration(out.DurationSeconds) * time.Second
+	}
+
+	_, err := fmt.Fprintf(r.out, "\nTotal: %s\n", domain.FormatDuration(total))
+	return err
+}
+
+func toProjectOutputs(byProject map[string]*domain.ProjectSummary) []projectOutput {
+	projects := make([]projectOutput, 0, len(byProject))
+	for _, ps := range byProject {
+		project := projectOutput{
+			ID:              ps.ProjectID,
+			Name:            ps.ProjectName,
+			DurationSeconds: int64(ps.TotalDuration.Seconds()),
+		}
+		if ps.ClientID != domain.NoClientID {
+			project.ClientID = ps.ClientID
+			project.Client = ps.ClientName
+		}
+		for _, ts := range ps.ByTask {
+			project.Tasks = append(project.Tasks, taskOutput{
+				ID:              ts.TaskID,
+				Name:            ts.TaskName,
+				DurationSeconds: int64(ts.Duration.Seconds()),
+			})
+		}
+		sort.Slice(project.Tasks, func(i, j int) bool {
+			return project.Tasks[i].DurationSeconds > project.Tasks[j].DurationSeconds
+		})
+		projects = append(projects, project)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].DurationSeconds > projects[j].DurationSeconds
+	})
+	return projects
+}
+
+func toClientOutputs(byClient map[string]*domain.ClientSummary) []clientOutput {
+	clients := make([]clientOutput, 0, len(byClient))
+	for _, cs := range byClient {
+		client := clientOutput{
+			ID:              cs.ClientID,
+			Name:            cs.ClientName,
+			DurationSeconds: int64(cs.TotalDuration.Seconds()),
+			Projects:        toProjectOutputs(cs.ByProject),
+		}
+		if cs.ClientID == domain.NoClientID {
+			client.ID = ""
+		}
+		clients = append(clients, client)
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].DurationSeconds > clients[j].DurationSeconds
+	})
+	return clients
+}
+
+func (r *Runner) printProjects(projects []projectOutput, indent string) {
+	for _, project := range projects {
+		fmt.Fprintf(r.out, "%s  %s - %s\n", indent, project.Name,
+			domain.FormatDuration(time.Duration(project.DurationSeconds)*time.Second))
+		for _, task := range project.Tasks {
+			fmt.Fprintf(r.out, "%s    • %s - %s\n", indent, task.Name,
+				domain.FormatDuration(time.Duration(task.DurationSeconds)*time.Second))
+		}
+	}
+}
+
+// printClients prints projects grouped by client, or flat when no project
+// in the report has a client.
+func (r *Runner) printClients(clients []clientOutput, projects []projectOutput) {
+	if !slices.ContainsFunc(clients, func(c clientOutput) bool { return c.ID != "" }) {
+		r.printProjects(projects, "")
+		return
+	}
+
+	for _, client := range clients {
+		fmt.Fprintf(r.out, "  %s - %s\n", client.Name,
+			domain.FormatDuration(time.Duration(client.DurationSeconds)*time.Second))
+		r.printProjects(client.Projects, "  ")
+	}
+}
+
+func toBillingOutput(billing domain.Billing) billingOutput {
+	out := billingOutput{
+		BillableDurationSeconds:    int64(billing.BillableDuration.Seconds()),
+		NonBillableDurationSeconds: int64(billing.NonBillableDuration.Seconds()),
+		Earnings:                   make(map[string]float64, len(billing.Earnings)),
+	}
+	for currency, amount := range billing.Earnings {
+		out.Earnings[currency] = float64(amount) / 100
+	}
+	return out
+}
+
+func (r *Runner) printBilling(billing domain.Billing) {
+	if billing.BillableDuration == 0 {
+		return
+	}
+	fmt.Fprintf(r.out, "Billable: %s, non-billable: %s\n", domain.FormatDuration(billing.BillableDuration),
+		domain.FormatDuration(billing.NonBillableDuration))
+	if len(billing.Earnings) > 0 {
+		fmt.Fprintf(r.out, "Earned: %s\n", billing.Earnings)
+	}
+}
+
+func (r *Runner) printDailyReport(report *domain.DailySummary, jsonOutput bool) error {
+	out := dailyReportOutput{
+		Date:                   report.Date.Format("2006-01-02"),
+		TotalDurationSeconds:   int64(report.TotalDuration.Seconds()),
+		RunningDurationSeconds: int64(report.Running.Seconds()),
+		billingOutput:          toBillingOutput(report.Billing),
+		Clients:                toClientOutputs(report.ByClient),
+		Projects:               toProjectOutputs(report.ByProject),
+	}
+
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintln(r.out, report.Date.Format("Monday, January 2, 2006"))
+	r.printClients(out.Clients, out.Projects)
+	fmt.Fprintf(r.out, "Total: %s\n", domain.FormatTotal(report.TotalDuration, report.Running))
+	r.printBilling(report.Billing)
+	return nil
+}
+
+func (r *Runner) printWeeklyReport(report *domain.WeeklySummary, jsonOutput bool) error {
+	out := weeklyReportOutput{
+		StartDate:              report.StartDate.Format("2006-01-02"),
+		EndDate:                report.EndDate.AddDate(0, 0, -1).Format("2006-01-02"),
+		TotalDurationSeconds:   int64(report.TotalDuration.Seconds()),
+		RunningDurationSeconds: int64(report.Running.Seconds()),
+		billingOutput:          toBillingOutput(report.Billing),
+		ByDaySeconds:           toSeconds(report.ByDay),
+		Clients:                toClientOutputs(report.ByClient),
+		Projects:               toProjectOutputs(report.ByProject),
+	}
+
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintf(r.out, "Week of %s - %s\n", report.StartDate.Format("Jan 2"),
+		report.EndDate.AddDate(0, 0, -1).Format("Jan 2, 2006"))
+	for i := range 7 {
+		date := report.StartDate.AddDate(0, 0, i)
+		duration := report.ByDay[date.Format("2006-01-02")]
+		value := "-"
+		if duration > 0 {
+			value = domain.FormatDuration(duration)
+		}
+		fmt.Fprintf(r.out, "  %s: %s\n", date.Format("Mon Jan 2"), value)
+	}
+
+	heading := "By Project:"
+	if domain.HasClients(report.ByClient) {
+		heading = "By Client:"
+	}
+	fmt.Fprintln(r.out, "\n"+heading)
+	r.printClients(out.Clients, out.Projects)
+	fmt.Fprintf(r.out, "Total: %s\n", domain.FormatTotal(report.TotalDuration, report.Running))
+	r.printBilling(report.Billing)
+	return nil
+}
+
+func toSeconds(durations map[string]time.Duration) map[string]int64 {
+	seconds := make(map[string]int64, len(durations))
+	for key, duration := range durations {
+		seconds[key] = int64(duration.Seconds())
+	}
+	return seconds
+}
+
+// printRangeReport prints a month or custom range: totals per week, then the
+// days that have tracked time.
+func (r *Runner) printRangeReport(heading string, report *domain.RangeSummary, jsonOutput bool) error {
+	out := rangeReportOutput{
+		StartDate:              report.Start.Format("2006-01-02"),
+		EndDate:                report.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		TotalDurationSeconds:   int64(report.TotalDuration.Seconds()),
+		RunningDurationSeconds: int64(report.Running.Seconds()),
+		billingOutput:          toBillingOutput(report.Billing),
+		ByDaySeconds:           toSeconds(report.ByDay),
+		ByWeekSeconds:          toSeconds(report.ByWeek),
+		Clients:                toClientOutputs(report.ByClient),
+		Projects:               toProjectOutputs(report.ByProject),
+	}
+
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintln(r.out, heading)
+	if report.TotalDuration == 0 {
+		_, err := fmt.Fprintln(r.out, "No time tracked")
+		return err
+	}
+
+	fmt.Fprintln(r.out, "\nBy Week:")
+	for _, week := range report.Weeks() {
+		value := "-"
+		if week.Duration > 0 {
+			value = domain.FormatDuration(week.Duration)
+		}
+		fmt.Fprintf(r.out, "  %s - %s: %s\n", week.First.Format("Jan 2"), week.Last.Format("Jan 2"), value)
+	}
+
+	fmt.Fprintln(r.out, "\nBy Day:")
+	for day := report.Start; day.Before(report.End); day = day.AddDate(0, 0, 1) {
+		if duration := report.ByDay[day.Format(domain.DayKeyLayout)]; duration > 0 {
+			fmt.Fprintf(r.out, "  %s: %s\n", day.Format("Mon Jan 2"), domain.FormatDuration(duration))
+		}
+	}
+
+	heading = "By Project:"
+	if domain.HasClients(report.ByClient) {
+		heading = "By Client:"
+	}
+	fmt.Fprintln(r.out, "\n"+heading)
+	r.printClients(out.Clients, out.Projects)
+	fmt.Fprintf(r.out, "Total: %s\n", domain.FormatTotal(report.TotalDuration, report.Running))
+	r.printBilling(report.Billing)
+	return nil
+}
+
+func toGroupOutputs(groups []domain.Group) []groupOutput {
+	outputs := make([]groupOutput, 0, len(groups))
+	for _, g := range groups {
+		out := groupOutput{
+			Key:             g.Key,
+			Name:            g.Name,
+			DurationSeconds: int64(g.Duration.Seconds()),
+		}
+		if g.Children != nil {
+			out.Groups = toGroupOutputs(g.Children)
+		}
+		outputs = append(outputs, out)
+	}
+	return outputs
+}
+
+func (r *Runner) printGroupedReport(heading string, report *domain.GroupedReport, jsonOutput bool) error {
+	out := groupedReportOutput{
+		StartDate:              report.Start.Format("2006-01-02"),
+		EndDate:                report.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy:                []string{report.First.String()},
+		TotalDurationSeconds:   int64(report.Total.Seconds()),
+		RunningDurationSeconds: int64(report.Running.Seconds()),
+		Groups:                 toGroupOutputs(report.Groups),
+	}
+	if report.Second != domain.GroupNone {
+		out.GroupBy = append(out.GroupBy, report.Second.String())
+	}
+
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintln(r.out, heading)
+	for _, g := range report.Groups {
+		fmt.Fprintf(r.out, "  %s - %s\n", g.Name, domain.FormatDuration(g.Duration))
+		for _, child := range g.Children {
+			fmt.Fprintf(r.out, "    • %s - %s\n", child.Name, domain.FormatDuration(child.Duration))
+		}
+	}
+	_, err := fmt.Fprintf(r.out, "Total: %s\n", domain.FormatTotal(report.Total, report.Running))
+	return err
+}
+
+func (r *Runner) printExported(path string, rows int, jsonOutput bool) error {
+	if jsonOutput {
+		return r.writeJSON(exportOutput{Path: path, Rows: rows})
+	}
+	_, err := fmt.Fprintf(r.out, "Exported %d rows to %s\n", rows, path)
+	return err
+}
+
+func toImportOutput(plan *domain.ImportPlan, format importer.Format) importOutput {
+	out := importOutput{
+		Format:      format.String(),
+		Ready:       plan.Count(domain.ImportReady),
+		Duplicates:  plan.Count(domain.ImportDuplicate),
+		Overlaps:    plan.Count(domain.ImportOverlap),
+		Invalid:     plan.Count(domain.ImportInvalid),
+		NewProjects: append([]string{}, plan.NewProjects...),
+		NewTasks:    []string{},
+		NewTags:     append([]string{}, plan.NewTags...),
+		Items:       []importItemOutput{},
+	}
+	for _, task := range plan.NewTasks {
+		out.NewTasks = append(out.NewTasks, task.String())
+	}
+	for _, item := range plan.Items {
+		out.Items = append(out.Items, importItemOutput{
+			Line:        item.Record.Line,
+			Status:      item.Status.String(),
+			Reason:      item.Reason,
+			Start:       item.Record.Start,
+			End:         item.Record.End,
+			Description: item.Record.Description,
+			Project:     item.Project,
+			Task:        item.Task,
+			Tags:        item.Tags,
+			Billable:    item.Billable,
+		})
+	}
+	return out
+}
+
+func (r *Runner) printImport(out importOutput, jsonOutput bool) error {
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintf(r.out, "Read %d record(s) as %s\n", len(out.Items), out.Format)
+	for _, item := range out.Items {
+		line := fmt.Sprintf("%4d  %-9s", item.Line, item.Status)
+		if !item.Start.IsZero() {
+			line += "  " + item.Start.Format("2006-01-02 15:04")
+		}
+		if !item.End.IsZero() {
+			line += " - " + item.End.Format("15:04")
+		}
+		if item.Description != "" {
+			line += "  " + item.Description
+		}
+		if item.Project != "" {
+			line += "  [" + item.Project
+			if item.Task != "" {
+				line += " • " + item.Task
+			}
+			line += "]"
+		}
+		if len(item.Tags) > 0 {
+			line += "  #" + strings.Join(item.Tags, " #")
+		}
+		if item.Billable {
+			line += "  $"
+		}
+		if item.Reason != "" {
+			line += "  (" + item.Reason + ")"
+		}
+		fmt.Fprintln(r.out, line)
+	}
+
+	fmt.Fprintf(r.out, "Ready: %d, duplicates: %d, overlaps: %d, invalid: %d\n", out.Ready, out.Duplicates, out.Overlaps, out.Invalid)
+	verb := "Will create"
+	if out.Committed {
+		verb = "Created"
+	}
+	for _, created := range []struct {
+		what  string
+		names []string
+	}{{"projects", out.NewProjects}, {"tasks", out.NewTasks}, {"tags", out.NewTags}} {
+		if len(created.names) > 0 {
+			fmt.Fprintf(r.out, "%s %s: %s\n", verb, created.what, strings.Join(created.names, ", "))
+		}
+	}
+
+	if out.Committed {
+		_, err := fmt.Fprintf(r.out, "Imported %d of %d entries\n", out.Created, out.Ready)
+		return err
+	}
+	if out.Ready == 0 {
+		_, err := fmt.Fprintln(r.out, "Nothing to import")
+		return err
+	}
+	_, err := fmt.Fprintf(r.out, "Nothing imported yet: run again with --commit to import the %d ready entries\n", out.Ready)
+	return err
+}
+
+// printFilter follows a text report with the filters it was narrowed by; err
+// is the error of printing the report itself.
+func (r *Runner) printFilter(labels []string, jsonOutput bool, err error) error {
+	if err != nil || jsonOutput || len(labels) == 0 {
+		return err
+	}
+	_, err = fmt.Fprintf(r.out, "Filter: %s\n", strings.Join(labels, ", "))
+	return err
+}
+
+func (r *Runner) printSyncResult(result *domain.SyncResult, jsonOutput bool) error {
+	out := syncOutput{
+		Applied:   result.Applied,
+		Conflicts: []string{},
+		Remaining: result.Remaining,
+	}
+	for _, conflict := range result.Conflicts {
+		out.Conflicts = append(out.Conflicts, conflict.Reason)
+	}
+
+	if jsonOutput {
+		return r.writeJSON(out)
+	}
+
+	fmt.Fprintf(r.out, "Synced %d change(s)\n", out.Applied)
+	for _, conflict := range out.Conflicts {
+		fmt.Fprintf(r.out, "Conflict: %s\n", conflict)
+	}
+	if out.Remaining > 0 {
+		fmt.Fprintf(r.out, "%d change(s) still queued: Clockify is not reachable\n", out.Remaining)
+	}
+	return nil
+}
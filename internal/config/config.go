@@ -2,47 +2,503 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultBaseURL  = "https://api.clockify.me/api/v1"
+	defaultCacheTTL = 5 * time.Minute
+
+	defaultIdleTimeout = 10 * time.Minute
 )
 
+// Pomodoro holds the interval lengths of the timer's Pomodoro mode.
+type Pomodoro struct {
+	Work           time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int
+	StopOnBreak    bool
+}
+
+var defaultPomodoro = Pomodoro{
+	Work:           25 * time.Minute,
+	ShortBreak:     5 * time.Minute,
+	LongBreak:      15 * time.Minute,
+	LongBreakEvery: 4,
+	StopOnBreak:    true,
+}
+
+// Targets holds the expected hours. Weekdays overrides the daily target of
+// single days of the week; a zero Daily and Weekly turn targets off.
+type Targets struct {
+	Daily        time.Duration
+	Weekly       time.Duration
+	Weekdays     map[time.Weekday]time.Duration
+	Holidays     []time.Time
+	BalanceSince time.Time
+}
+
+// WorkingHours is the part of the day in which untracked time is reported
+// as a gap in the Entries view. Start and End are offsets from midnight.
+type WorkingHours struct {
+	Start  time.Duration
+	End    time.Duration
+	MinGap time.Duration
+}
+
+var defaultWorkingHours = WorkingHours{
+	Start:  9 * time.Hour,
+	End:    17 * time.Hour,
+	MinGap: 5 * time.Minute,
+}
+
 type Config struct {
 	APIKey      string
 	WorkspaceID string
 	BaseURL     string
+	// ReportsURL is where the reports API lives; empty derives it from
+	// BaseURL.
+	ReportsURL string
+	// CacheTTL is how long cached projects, tasks and tags are used before
+	// they are fetched again.
+	CacheTTL time.Duration
+	// IdleTimeout is how long the user can be away while a timer runs before
+	// the TUI asks what to do with the time; zero disables idle detection.
+	IdleTimeout  time.Duration
+	Pomodoro     Pomodoro
+	Targets      Targets
+	WorkingHours WorkingHours
+
+	// Profile is the name of the profile that was loaded, empty when the
+	// configuration came from environment variables only.
+	Profile string
+
+	cacheTTL     string
+	idleTimeout  string
+	pomodoro     filePomodoro
+	targets      fileTargets
+	workingHours fileWorkingHours
+	keyPrefix    string
+	envOverrides map[string]bool
 }
 
-func Load() (*Config, error) {
-	apiKey := os.Getenv("CLOCKIFY_API_KEY")
-	if apiKey == "" {
-		return nil, errors.New("CLOCKIFY_API_KEY environment variable is required")
+// ValidationError points at the configuration key that failed validation.
+type ValidationError struct {
+	Key     string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+type fileConfig struct {
+	DefaultProfile string                 `yaml:"default_profile"`
+	Profiles       map[string]fileProfile `yaml:"profiles"`
+}
+
+type fileProfile struct {
+	APIKey       string           `yaml:"api_key"`
+	WorkspaceID  string           `yaml:"workspace_id"`
+	BaseURL      string           `yaml:"base_url"`
+	ReportsURL   string           `yaml:"reports_url"`
+	CacheTTL     string           `yaml:"cache_ttl"`
+	IdleTimeout  string           `yaml:"idle_timeout"`
+	Pomodoro     filePomodoro     `yaml:"pomodoro"`
+	Targets      fileTargets      `yaml:"targets"`
+	WorkingHours fileWorkingHours `yaml:"working_hours"`
+}
+
+type fileWorkingHours struct {
+	Start  string `yaml:"start"`
+	End    string `yaml:"end"`
+	MinGap string `yaml:"min_gap"`
+}
+
+type fileTargets struct {
+	Daily        string            `yaml:"daily"`
+	Weekly       string            `yaml:"weekly"`
+	Weekdays     map[string]string `yaml:"weekdays"`
+	Holidays     []string          `yaml:"holidays"`
+	BalanceSince string            `yaml:"balance_since"`
+}
+
+type filePomodoro struct {
+	Work           string `yaml:"work"`
+	ShortBreak     string `yaml:"short_break"`
+	LongBreak      string `yaml:"long_break"`
+	LongBreakEvery *int   `yaml:"long_break_every"`
+	StopOnBreak    *bool  `yaml:"stop_on_break"`
+}
+
+// Path returns the location of the config file, honoring CLOCKIFY_CONFIG and
+// falling back to $XDG_CONFIG_HOME/clockify-tui/config.yaml.
+func Path() (string, error) {
+	if path := os.Getenv("CLOCKIFY_CONFIG"); path != "" {
+		return path, nil
 	}
 
-	workspaceID := os.Getenv("CLOCKIFY_WORKSPACE_ID")
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "clockify-tui", "config.yaml"), nil
+}
 
-	baseURL := os.Getenv("CLOCKIFY_BASE_URL")
-	if baseURL == "" {
-		baseURL = "https://api.clockify.me/api/v1"
+// Load reads the config file (if present), selects a profile and applies
+// environment variable overrides. An empty profile selects CLOCKIFY_PROFILE,
+// then default_profile, then the only profile if there is exactly one.
+func Load(profile string) (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := readFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	cfg := &Config{
-		APIKey:      apiKey,
-		WorkspaceID: workspaceID,
-		BaseURL:     baseURL,
+	if profile == "" {
+		profile = os.Getenv("CLOCKIFY_PROFILE")
 	}
 
-	if err := cfg.Validate(); err != nil {
+	cfg, err := file.resolve(path, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.envOverrides = make(map[string]bool)
+	if apiKey := os.Getenv("CLOCKIFY_API_KEY"); apiKey != "" {
+		cfg.APIKey = apiKey
+		cfg.envOverrides["api_key"] = true
+	}
+	if workspaceID := os.Getenv("CLOCKIFY_WORKSPACE_ID"); workspaceID != "" {
+		cfg.WorkspaceID = workspaceID
+		cfg.envOverrides["workspace_id"] = true
+	}
+	if baseURL := os.Getenv("CLOCKIFY_BASE_URL"); baseURL != "" {
+		cfg.BaseURL = baseURL
+		cfg.envOverrides["base_url"] = true
+	}
+	if reportsURL := os.Getenv("CLOCKIFY_REPORTS_URL"); reportsURL != "" {
+		cfg.ReportsURL = reportsURL
+		cfg.envOverrides["reports_url"] = true
+	}
+	if cacheTTL := os.Getenv("CLOCKIFY_CACHE_TTL"); cacheTTL != "" {
+		cfg.cacheTTL = cacheTTL
+		cfg.envOverrides["cache_ttl"] = true
+	}
+	if idleTimeout := os.Getenv("CLOCKIFY_IDLE_TIMEOUT"); idleTimeout != "" {
+		cfg.idleTimeout = idleTimeout
+		cfg.envOverrides["idle_timeout"] = true
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+
+	err = cfg.parseCacheTTL()
+	if err == nil {
+		err = cfg.parseIdleTimeout()
+	}
+	if err == nil {
+		err = cfg.parsePomodoro()
+	}
+	if err == nil {
+		err = cfg.parseTargets()
+	}
+	if err == nil {
+		err = cfg.parseWorkingHours()
+	}
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) && strings.HasPrefix(validationErr.Key, "profiles.") {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
 		return nil, err
 	}
 
 	return cfg, nil
 }
 
+func readFile(path string) (*fileConfig, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &fileConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	var file fileConfig
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &file, nil
+}
+
+func (f *fileConfig) resolve(path, profile string) (*Config, error) {
+	if profile == "" {
+		profile = f.DefaultProfile
+		if profile != "" {
+			if _, ok := f.Profiles[profile]; !ok {
+				return nil, fmt.Errorf("%s: %w", path, &ValidationError{
+					Key:     "default_profile",
+					Message: fmt.Sprintf("profile %q is not defined under profiles", profile),
+				})
+			}
+		}
+	}
+
+	if profile == "" && len(f.Profiles) == 1 {
+		for name := range f.Profiles {
+			profile = name
+		}
+	}
+
+	if profile == "" {
+		if len(f.Profiles) > 1 {
+			return nil, fmt.Errorf("%s: several profiles defined (%s); pick one with --profile or set default_profile",
+				path, strings.Join(f.profileNames(), ", "))
+		}
+		return &Config{}, nil
+	}
+
+	p, ok := f.Profiles[profile]
+	if !ok {
+		available := "none defined"
+		if len(f.Profiles) > 0 {
+			available = "available: " + strings.Join(f.profileNames(), ", ")
+		}
+		return nil, fmt.Errorf("profile %q not found in %s (%s)", profile, path, available)
+	}
+
+	return &Config{
+		APIKey:       p.APIKey,
+		WorkspaceID:  p.WorkspaceID,
+		BaseURL:      p.BaseURL,
+		ReportsURL:   p.ReportsURL,
+		cacheTTL:     p.CacheTTL,
+		idleTimeout:  p.IdleTimeout,
+		pomodoro:     p.Pomodoro,
+		targets:      p.Targets,
+		workingHours: p.WorkingHours,
+		Profile:      profile,
+		keyPrefix:    fmt.Sprintf("profiles.%s.", profile),
+	}, nil
+}
+
+func (f *fileConfig) profileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// key names the setting as the user wrote it: the environment variable when
+// it was overridden or no profile is in use, otherwise the profile key path.
+func (c *Config) key(name string) string {
+	if c.keyPrefix == "" || c.envOverrides[name] {
+		return "CLOCKIFY_" + strings.ToUpper(name)
+	}
+	return c.keyPrefix + name
+}
+
+func (c *Config) parseCacheTTL() error {
+	if c.cacheTTL == "" {
+		c.CacheTTL = defaultCacheTTL
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(c.cacheTTL)
+	if err != nil || ttl < 0 {
+		return &ValidationError{Key: c.key("cache_ttl"), Message: fmt.Sprintf("%q is not a valid duration (e.g. 10m or 1h)", c.cacheTTL)}
+	}
+	c.CacheTTL = ttl
+	return nil
+}
+
+func (c *Config) parseIdleTimeout() error {
+	if c.idleTimeout == "" {
+		c.IdleTimeout = defaultIdleTimeout
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(c.idleTimeout)
+	if err != nil || timeout < 0 {
+		return &ValidationError{Key: c.key("idle_timeout"), Message: fmt.Sprintf("%q is not a valid duration (e.g. 5m, or 0 to disable)", c.idleTimeout)}
+	}
+	c.IdleTimeout = timeout
+	return nil
+}
+
+func (c *Config) parsePomodoro() error {
+	c.Pomodoro = defaultPomodoro
+	lengths := []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"work", c.pomodoro.Work, &c.Pomodoro.Work},
+		{"short_break", c.pomodoro.ShortBreak, &c.Pomodoro.ShortBreak},
+		{"long_break", c.pomodoro.LongBreak, &c.Pomodoro.LongBreak},
+	}
+	for _, length := range lengths {
+		if length.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(length.value)
+		if err != nil || d <= 0 {
+			return &ValidationError{Key: c.key("pomodoro." + length.name), Message: fmt.Sprintf("%q is not a valid duration (e.g. 25m)", length.value)}
+		}
+		*length.dst = d
+	}
+
+	if every := c.pomodoro.LongBreakEvery; every != nil {
+		if *every < 1 {
+			return &ValidationError{Key: c.key("pomodoro.long_break_every"), Message: "must be at least 1"}
+		}
+		c.Pomodoro.LongBreakEvery = *every
+	}
+	if stop := c.pomodoro.StopOnBreak; stop != nil {
+		c.Pomodoro.StopOnBreak = *stop
+	}
+	return nil
+}
+
+// dateLayout is the format of dates in the config file.
+const dateLayout = "2006-01-02"
+
+func (c *Config) parseTargets() error {
+	hours := func(name, value string) (time.Duration, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, &ValidationError{Key: c.key("targets." + name), Message: fmt.Sprintf("%q is not a valid duration (e.g. 8h or 7h30m)", value)}
+		}
+		return d, nil
+	}
+
+	var err error
+	if c.Targets.Daily, err = hours("daily", c.targets.Daily); err != nil {
+		return err
+	}
+	if c.Targets.Weekly, err = hours("weekly", c.targets.Weekly); err != nil {
+		return err
+	}
+
+	c.Targets.Weekdays = make(map[time.Weekday]time.Duration, len(c.targets.Weekdays))
+	for name, value := range c.targets.Weekdays {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return &ValidationError{Key: c.key("targets.weekdays." + name), Message: "not a day of the week (e.g. friday)"}
+		}
+		if c.Targets.Weekdays[day], err = hours("weekdays."+name, value); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range c.targets.Holidays {
+		day, err := time.ParseInLocation(dateLayout, value, time.Local)
+		if err != nil {
+			return &ValidationError{Key: c.key("targets.holidays"), Message: fmt.Sprintf("%q is not a date (YYYY-MM-DD)", value)}
+		}
+		c.Targets.Holidays = append(c.Targets.Holidays, day)
+	}
+
+	if value := c.targets.BalanceSince; value != "" {
+		day, err := time.ParseInLocation(dateLayout, value, time.Local)
+		if err != nil {
+			return &ValidationError{Key: c.key("targets.balance_since"), Message: fmt.Sprintf("%q is not a date (YYYY-MM-DD)", value)}
+		}
+		c.Targets.BalanceSince = day
+	}
+	return nil
+}
+
+func (c *Config) parseWorkingHours() error {
+	c.WorkingHours = defaultWorkingHours
+
+	clock := func(name, value string, dst *time.Duration) error {
+		if value == "" {
+			return nil
+		}
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			return &ValidationError{Key: c.key("working_hours." + name), Message: fmt.Sprintf("%q is not a time of day (HH:MM)", value)}
+		}
+		*dst = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		return nil
+	}
+	if err := clock("start", c.workingHours.Start, &c.WorkingHours.Start); err != nil {
+		return err
+	}
+	if err := clock("end", c.workingHours.End, &c.WorkingHours.End); err != nil {
+		return err
+	}
+	if c.WorkingHours.End <= c.WorkingHours.Start {
+		return &ValidationError{Key: c.key("working_hours.end"), Message: "must be after working_hours.start"}
+	}
+
+	if value := c.workingHours.MinGap; value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return &ValidationError{Key: c.key("working_hours.min_gap"), Message: fmt.Sprintf("%q is not a valid duration (e.g. 15m)", value)}
+		}
+		c.WorkingHours.MinGap = d
+	}
+	return nil
+}
+
+// parseWeekday accepts English day names and their three-letter
+// abbreviations in any case.
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
 func (c *Config) Validate() error {
 	if c.APIKey == "" {
-		return errors.New("API key is required")
+		return &ValidationError{Key: c.key("api_key"), Message: "API key is required (set it in the config file or via CLOCKIFY_API_KEY)"}
 	}
 	if c.BaseURL == "" {
-		return errors.New("base URL is required")
+		return &ValidationError{Key: c.key("base_url"), Message: "base URL is required"}
+	}
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Key: c.key("base_url"), Message: fmt.Sprintf("%q is not an absolute http(s) URL", c.BaseURL)}
+	}
+	if c.ReportsURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(c.ReportsURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Key: c.key("reports_url"), Message: fmt.Sprintf("%q is not an absolute http(s) URL", c.ReportsURL)}
 	}
 	return nil
 }
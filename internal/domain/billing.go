@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"main/internal/api"
+)
+
+// Earnings maps currency codes to amounts in the currency's minor unit.
+type Earnings map[string]int64
+
+// String formats the amounts as e.g. "120.50 EUR, 80.00 USD", sorted by
+// currency.
+func (e Earnings) String() string {
+	currencies := make([]string, 0, len(e))
+	for currency := range e {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	parts := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		parts = append(parts, FormatAmount(e[currency], currency))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// earningsFromTotals reads the earned amounts of a report.
+func earningsFromTotals(totals api.ReportTotals) Earnings {
+	earnings := make(Earnings)
+	for _, amount := range totals.Amounts {
+		if amount.Type != "EARNED" {
+			continue
+		}
+		for _, a := range amount.AmountByCurrencies {
+			earnings[a.Currency] += int64(math.Round(a.Amount))
+		}
+	}
+	return earnings
+}
+
+// FormatAmount formats an amount in minor units, e.g. 12050 USD as
+// "120.50 USD".
+func FormatAmount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, currency)
+}
+
+// ProjectBillable returns the project's default billable setting, used for new
+// entries on it.
+func ProjectBillable(projects []api.Project, projectID *string) bool {
+	if projectID == nil {
+		return false
+	}
+	for _, p := range projects {
+		if p.ID == *projectID {
+			return p.Billable
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"main/internal/api"
+	"main/internal/cache"
+)
+
+type ClientService struct {
+	apiClient *api.Client
+	cache     *cache.Cache
+}
+
+func NewClientService(client *api.Client, cache *cache.Cache) *ClientService {
+	return &ClientService{
+		apiClient: client,
+		cache:     cache,
+	}
+}
+
+func (s *ClientService) GetAllClients(ctx context.Context) ([]api.ClockifyClient, error) {
+	if clients, ok := s.cache.GetClients(); ok {
+		return clients, nil
+	}
+
+	clients, err := s.ReloadClients(ctx)
+	if api.IsNetworkError(err) {
+		if stale := s.cache.Snapshot().Clients; stale != nil {
+			return stale, nil
+		}
+	}
+	return clients, err
+}
+
+func (s *ClientService) ReloadClients(ctx context.Context) ([]api.ClockifyClient, error) {
+	return reload(ctx, s.apiClient, s.apiClient.GetClients, s.cache.SetClients)
+}
+
+func (s *ClientService) FindClient(ctx context.Context, nameOrID string) (*api.ClockifyClient, error) {
+	clients, err := s.GetAllClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range clients {
+		if clients[i].ID == nameOrID || strings.EqualFold(clients[i].Name, nameOrID) {
+			return &clients[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("client %q not found", nameOrID)
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"sort"
+	"unicode"
+)
+
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 8
+	fuzzyWordStartBonus   = 10
+	fuzzyMaxGapPenalty    = 5
+)
+
+// FuzzyMatch is a candidate that matched a query. Positions are the rune
+// indexes of the matched characters, for highlighting.
+type FuzzyMatch struct {
+	Index     int
+	Score     int
+	Positions []int
+}
+
+// FuzzyScore matches query as a case-insensitive subsequence of target and
+// returns the score of the best alignment. Consecutive characters and
+// characters at the start of a word score higher, gaps cost a little, so "wb"
+// prefers the "B" of "Web Backend" over the one in "Web".
+func FuzzyScore(query, target string) (int, []int, bool) {
+	q := []rune(query)
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(target)
+	if len(q) > len(t) {
+		return 0, nil, false
+	}
+
+	// best[i][j] is the best score for q[:i+1] with q[i] matched at t[j];
+	// from[i][j] is where q[i-1] was matched in that alignment.
+	const none = -1 << 30
+	best := make([][]int, len(q))
+	from := make([][]int, len(q))
+	for i := range q {
+		best[i] = make([]int, len(t))
+		from[i] = make([]int, len(t))
+		qr := unicode.ToLower(q[i])
+
+		for j := range t {
+			best[i][j] = none
+			if unicode.ToLower(t[j]) != qr {
+				continue
+			}
+
+			base := fuzzyMatchScore
+			if isWordStart(t, j) {
+				base += fuzzyWordStartBonus
+			}
+
+			if i == 0 {
+				best[i][j] = base - min(j, fuzzyMaxGapPenalty)
+				continue
+			}
+			for k := i - 1; k < j; k++ {
+				if best[i-1][k] == none {
+					continue
+				}
+				score := best[i-1][k] + base
+				if k == j-1 {
+					score += fuzzyConsecutiveBonus
+				} else {
+					score -= min(j-k-1, fuzzyMaxGapPenalty)
+				}
+				if score > best[i][j] {
+					best[i][j] = score
+					from[i][j] = k
+				}
+			}
+		}
+	}
+
+	last := len(q) - 1
+	end := -1
+	for j := range t {
+		if best[last][j] != none && (end < 0 || best[last][j] > best[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, len(q))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = j
+		j = from[i][j]
+	}
+	return best[last][end], positions, true
+}
+
+func isWordStart(t []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := t[i-1], t[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// FuzzyFilter returns the candidates matching query, best first. boost, if
+// not nil, is added to the score of the candidate at the same index, e.g. to
+// favour recently used projects. Ties keep the original order.
+func FuzzyFilter(query string, candidates []string, boost []int) []FuzzyMatch {
+	matches := make([]FuzzyMatch, 0, len(candidates))
+	for i, candidate := range candidates {
+		score, positions, ok := FuzzyScore(query, candidate)
+		if !ok {
+			continue
+		}
+		if i < len(boost) {
+			score += boost[i]
+		}
+		matches = append(matches, FuzzyMatch{Index: i, Score: score, Positions: positions})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
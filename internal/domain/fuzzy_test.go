@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		target        string
+		wantOK        bool
+		wantPositions []int
+	}{
+		{name: "empty query matches anything", query: "", target: "Web", wantOK: true},
+		{name: "case-insensitive subsequence", query: "wbe", target: "Web Backend", wantOK: true, wantPositions: []int{0, 4, 8}},
+		{name: "word start preferred", query: "wb", target: "Web Backend", wantOK: true, wantPositions: []int{0, 4}},
+		{name: "camel case word start", query: "cb", target: "ClientBilling", wantOK: true, wantPositions: []int{0, 6}},
+		{name: "out of order", query: "bw", target: "Web", wantOK: false},
+		{name: "query longer than target", query: "webs", target: "Web", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := FuzzyScore(tt.query, tt.target)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !slices.Equal(positions, tt.wantPositions) {
+				t.Errorf("positions = %v, want %v", positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilterRanking(t *testing.T) {
+	candidates := []string{"Sweb", "Website", "Internal", "Web Backend"}
+
+	tests := []struct {
+		name  string
+		query string
+		boost []int
+		want  []int
+	}{
+		{name: "prefix before mid-word match", query: "web", want: []int{1, 3, 0}},
+		{name: "word starts before inner letters", query: "wb", want: []int{3, 1, 0}},
+		{name: "boost lifts a weaker match", query: "web", boost: []int{100}, want: []int{0, 1, 3}},
+		{name: "no match", query: "xyz", want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := FuzzyFilter(tt.query, candidates, tt.boost)
+			got := make([]int, len(matches))
+			for i, match := range matches {
+				got[i] = match.Index
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
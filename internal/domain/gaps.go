@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"main/internal/api"
+)
+
+// WorkingHours is the part of each day in which untracked time counts as a
+// gap. Start and End are offsets from midnight.
+type WorkingHours struct {
+	Start time.Duration
+	End   time.Duration
+	// MinGap is the shortest untracked stretch reported as a gap.
+	MinGap time.Duration
+}
+
+func DefaultWorkingHours() WorkingHours {
+	return WorkingHours{
+		Start:  9 * time.Hour,
+		End:    17 * time.Hour,
+		MinGap: 5 * time.Minute,
+	}
+}
+
+// On returns the working hours of day as wall-clock times.
+func (w WorkingHours) On(day time.Time) (start, end time.Time) {
+	midnight := DayStart(day)
+	return midnight.Add(w.Start), midnight.Add(w.End)
+}
+
+// EntryGap is untracked time within the working hours.
+type EntryGap struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (g EntryGap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// EntryOverlap is time tracked by two entries at once. First is the entry
+// that started earlier.
+type EntryOverlap struct {
+	Start  time.Time
+	End    time.Time
+	First  api.TimeEntry
+	Second api.TimeEntry
+}
+
+func (o EntryOverlap) Duration() time.Duration {
+	return o.End.Sub(o.Start)
+}
+
+// Other returns the entry that overlaps the one with entryID.
+func (o EntryOverlap) Other(entryID string) api.TimeEntry {
+	if o.First.ID == entryID {
+		return o.Second
+	}
+	return o.First
+}
+
+// DayAnalysis holds the gaps and overlaps of the entries of a day, each
+// ordered by start.
+type DayAnalysis struct {
+	Gaps     []EntryGap
+	Overlaps []EntryOverlap
+}
+
+// OverlapsOf returns the overlaps the entry with entryID is part of.
+func (a *DayAnalysis) OverlapsOf(entryID string) []EntryOverlap {
+	if a == nil {
+		return nil
+	}
+	var overlaps []EntryOverlap
+	for _, overlap := range a.Overlaps {
+		if overlap.First.ID == entryID || overlap.Second.ID == entryID {
+			overlaps = append(overlaps, overlap)
+		}
+	}
+	return overlaps
+}
+
+// entryEnd returns when an entry ends, taking now for the running one.
+func entryEnd(entry *api.TimeEntry, now time.Time) time.Time {
+	if entry.TimeInterval.End == nil {
+		return now
+	}
+	return *entry.TimeInterval.End
+}
+
+// AnalyzeDay finds the gaps between the entries of day within the working
+// hours, and the entries that overlap. Gaps are only reported up to now, so
+// the rest of today is not counted as missing.
+func (s *TimeEntryService) AnalyzeDay(entries []api.TimeEntry, day time.Time, hours WorkingHours, now time.Time) *DayAnalysis {
+	sorted := append([]api.TimeEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeInterval.Start.Before(sorted[j].TimeInterval.Start)
+	})
+
+	analysis := &DayAnalysis{}
+
+	dayStart, dayEnd := hours.On(day)
+	if now.Before(dayEnd) {
+		dayEnd = now
+	}
+	addGap := func(start, end time.Time) {
+		start = latest(start, dayStart)
+		if dayEnd.Before(end) {
+			end = dayEnd
+		}
+		if end.Sub(start) >= max(hours.MinGap, time.Minute) {
+			analysis.Gaps = append(analysis.Gaps, EntryGap{Start: start, End: end})
+		}
+	}
+
+	covered := dayStart
+	for i := range sorted {
+		start := sorted[i].TimeInterval.Start
+		end := entryEnd(&sorted[i], now)
+		if start.After(covered) {
+			addGap(covered, start)
+		}
+		covered = latest(covered, end)
+
+		for j := i + 1; j < len(sorted); j++ {
+			next := sorted[j].TimeInterval.Start
+			if !next.Before(end) {
+				break
+			}
+			analysis.Overlaps = append(analysis.Overlaps, EntryOverlap{
+				Start:  next,
+				End:    earliest(end, entryEnd(&sorted[j], now)),
+				First:  sorted[i],
+				Second: sorted[j],
+			})
+		}
+	}
+	addGap(covered, dayEnd)
+
+	return analysis
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// TrimOverlap returns the update that makes the entry with entryID stop
+// overlapping: the earlier entry is ended when the other starts, the later
+// one is started when the other ends. It fails when that would leave
+// nothing of the entry, cut off its time after an entry nested in it, or
+// stop the running timer.
+func TrimOverlap(overlap EntryOverlap, entryID string) (api.TimeEntryRequest, error) {
+	if overlap.First.ID == entryID {
+		entry := overlap.First
+		if entry.TimeInterval.End == nil {
+			return api.TimeEntryRequest{}, errors.New("the running entry can't be trimmed, trim the other entry instead")
+		}
+		end := overlap.Second.TimeInterval.Start
+		if !end.After(entry.TimeInterval.Start) {
+			return api.TimeEntryRequest{}, errors.New("both entries start at the same time, trim the other entry instead")
+		}
+		// Ending the entry where the other starts would drop its time after
+		// the other ends.
+		if second := overlap.Second.TimeInterval.End; second != nil && second.Before(*entry.TimeInterval.End) {
+			return api.TimeEntryRequest{}, errors.New("the other entry lies entirely within this one, edit or delete it instead")
+		}
+		req := EntryToRequest(&entry)
+		req.End = &end
+		return req, nil
+	}
+
+	entry := overlap.Second
+	if overlap.First.TimeInterval.End == nil {
+		return api.TimeEntryRequest{}, errors.New("the entry lies within the running entry, edit or delete it instead")
+	}
+	start := *overlap.First.TimeInterval.End
+	if entry.TimeInterval.End != nil && !entry.TimeInterval.End.After(start) {
+		return api.TimeEntryRequest{}, errors.New("the entry lies entirely within the other, edit or delete it instead")
+	}
+	req := EntryToRequest(&entry)
+	req.Start = start
+	return req, nil
+}
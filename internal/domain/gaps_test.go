@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/api"
+)
+
+var gapsDay = time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+
+func at(hour, minute int) time.Time {
+	return gapsDay.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func entry(id string, start time.Time, end *time.Time) api.TimeEntry {
+	return api.TimeEntry{
+		ID:           id,
+		TimeInterval: api.TimeInterval{Start: start, End: end},
+	}
+}
+
+func ended(id string, start, end time.Time) api.TimeEntry {
+	return entry(id, start, &end)
+}
+
+func TestAnalyzeDay(t *testing.T) {
+	entries := []api.TimeEntry{
+		ended("late", at(13, 0), at(15, 0)),
+		ended("early", at(8, 30), at(10, 0)),
+		ended("inside", at(9, 30), at(9, 45)),
+		ended("short-gap", at(10, 3), at(12, 0)),
+	}
+
+	analysis := (&TimeEntryService{}).AnalyzeDay(entries, gapsDay, DefaultWorkingHours(), at(16, 0))
+
+	wantGaps := []EntryGap{
+		{Start: at(12, 0), End: at(13, 0)},
+		{Start: at(15, 0), End: at(16, 0)},
+	}
+	if len(analysis.Gaps) != len(wantGaps) {
+		t.Fatalf("gaps = %+v, want %+v", analysis.Gaps, wantGaps)
+	}
+	for i, want := range wantGaps {
+		got := analysis.Gaps[i]
+		if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+			t.Errorf("gap %d = %v-%v, want %v-%v", i, got.Start, got.End, want.Start, want.End)
+		}
+	}
+
+	if len(analysis.Overlaps) != 1 {
+		t.Fatalf("overlaps = %+v, want one", analysis.Overlaps)
+	}
+	overlap := analysis.Overlaps[0]
+	if overlap.First.ID != "early" || overlap.Second.ID != "inside" {
+		t.Errorf("overlap of %s and %s, want early and inside", overlap.First.ID, overlap.Second.ID)
+	}
+	if overlap.Duration() != 15*time.Minute {
+		t.Errorf("overlap duration = %v, want 15m", overlap.Duration())
+	}
+	if got := analysis.OverlapsOf("late"); len(got) != 0 {
+		t.Errorf("OverlapsOf(late) = %+v, want none", got)
+	}
+}
+
+func TestTrimOverlap(t *testing.T) {
+	tests := []struct {
+		name      string
+		first     api.TimeEntry
+		second    api.TimeEntry
+		trim      string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "first entry ends when the second starts",
+			first:     ended("a", at(9, 0), at(10, 0)),
+			second:    ended("b", at(9, 30), at(11, 0)),
+			trim:      "a",
+			wantStart: at(9, 0),
+			wantEnd:   at(9, 30),
+		},
+		{
+			name:      "second entry starts when the first ends",
+			first:     ended("a", at(9, 0), at(10, 0)),
+			second:    ended("b", at(9, 30), at(11, 0)),
+			trim:      "b",
+			wantStart: at(10, 0),
+			wantEnd:   at(11, 0),
+		},
+		{
+			name:    "running first entry",
+			first:   entry("a", at(9, 0), nil),
+			second:  ended("b", at(9, 30), at(11, 0)),
+			trim:    "a",
+			wantErr: true,
+		},
+		{
+			name:    "both start at the same time",
+			first:   ended("a", at(9, 0), at(10, 0)),
+			second:  ended("b", at(9, 0), at(11, 0)),
+			trim:    "a",
+			wantErr: true,
+		},
+		{
+			name:    "first entry around the second",
+			first:   ended("a", at(9, 0), at(12, 0)),
+			second:  ended("b", at(9, 30), at(11, 0)),
+			trim:    "a",
+			wantErr: true,
+		},
+		{
+			name:      "first entry ending with the second",
+			first:     ended("a", at(9, 0), at(11, 0)),
+			second:    ended("b", at(9, 30), at(11, 0)),
+			trim:      "a",
+			wantStart: at(9, 0),
+			wantEnd:   at(9, 30),
+		},
+		{
+			name:    "second lies within the running entry",
+			first:   entry("a", at(9, 0), nil),
+			second:  ended("b", at(9, 30), at(11, 0)),
+			trim:    "b",
+			wantErr: true,
+		},
+		{
+			name:    "second lies entirely within the first",
+			first:   ended("a", at(9, 0), at(12, 0)),
+			second:  ended("b", at(9, 30), at(11, 0)),
+			trim:    "b",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlap := EntryOverlap{First: tt.first, Second: tt.second}
+			req, err := TrimOverlap(overlap, tt.trim)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("TrimOverlap = %+v, want an error", req)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TrimOverlap: %v", err)
+			}
+			if !req.Start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", req.Start, tt.wantStart)
+			}
+			if req.End == nil || !req.End.Equal(tt.wantEnd) {
+				t.Errorf("end = %v, want %v", req.End, tt.wantEnd)
+			}
+		})
+	}
+}
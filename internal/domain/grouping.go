@@ -0,0 +1,302 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/internal/api"
+)
+
+// GroupBy is a dimension a grouped report splits time by.
+type GroupBy int
+
+const (
+	GroupNone GroupBy = iota
+	GroupByProject
+	GroupByClient
+	GroupByTask
+	GroupByTag
+	GroupByDescription
+	GroupByWeekday
+)
+
+// Groupings lists the dimensions in the order the reports view cycles
+// through them.
+var Groupings = []GroupBy{
+	GroupByProject,
+	GroupByClient,
+	GroupByTask,
+	GroupByTag,
+	GroupByDescription,
+	GroupByWeekday,
+}
+
+func (g GroupBy) String() string {
+	switch g {
+	case GroupByProject:
+		return "project"
+	case GroupByClient:
+		return "client"
+	case GroupByTask:
+		return "task"
+	case GroupByTag:
+		return "tag"
+	case GroupByDescription:
+		return "description"
+	case GroupByWeekday:
+		return "weekday"
+	}
+	return "none"
+}
+
+// ParseGroupBy reads a dimension as written by String.
+func ParseGroupBy(name string) (GroupBy, error) {
+	for _, g := range Groupings {
+		if strings.EqualFold(name, g.String()) {
+			return g, nil
+		}
+	}
+	return GroupNone, fmt.Errorf("unknown grouping %q (use project, client, task, tag, description or weekday)", name)
+}
+
+// Group is the time booked on one value of a dimension; Children splits it by
+// the second dimension, if any.
+type Group struct {
+	Key      string
+	Name     string
+	Duration time.Duration
+	Children []Group
+}
+
+// GroupedReport splits the time from Start up to, but not including, End by
+// one or two dimensions. An entry with several tags counts toward each of
+// them when grouped by tag, so the groups may add up to more than Total.
+type GroupedReport struct {
+	Start  time.Time
+	End    time.Time
+	First  GroupBy
+	Second GroupBy
+	Total  time.Duration
+	// Running is the part of Total tracked by the running timer, which is
+	// grouped like the other entries.
+	Running time.Duration
+	Groups  []Group
+}
+
+// GetGroupedReport groups the entries of the days from first to last, both
+// included, by one dimension and then another; then may be GroupNone for a
+// flat list.
+func (s *ReportService) GetGroupedReport(ctx context.Context, first, last time.Time, filter api.ReportFilter, by, then GroupBy) (*GroupedReport, error) {
+	start := DayStart(first)
+	end := DayStart(last).AddDate(0, 0, 1)
+
+	detailed, err := s.apiClient.GetDetailedReport(ctx, start, end.Add(-time.Millisecond), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GroupedReport{Start: start, End: end, First: by, Second: then}
+	if len(detailed.Totals) > 0 {
+		report.Total = seconds(detailed.Totals[0].TotalTime)
+	}
+
+	entries := detailed.TimeEntries
+	now := time.Now()
+	running, runningStart, err := s.runningEntry(ctx, start, end, filter, now)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		entry, err := s.toReportEntry(ctx, running, runningStart, now, by, then)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		report.Running = seconds(entry.TimeInterval.Duration)
+		report.Total += report.Running
+	}
+
+	report.Groups = groupEntries(entries, by, then, GroupNone, start.Location())
+	return report, nil
+}
+
+// toReportEntry turns the running timer into an entry of the detailed
+// report, looking up only the names the groupings show.
+func (s *ReportService) toReportEntry(ctx context.Context, entry *api.TimeEntry, start, now time.Time, groupings ...GroupBy) (api.ReportTimeEntry, error) {
+	report := api.ReportTimeEntry{
+		ID:          entry.ID,
+		Description: entry.Description,
+		UserID:      entry.UserID,
+		Billable:    entry.Billable,
+		TimeInterval: api.ReportTimeInterval{
+			Start:    start,
+			Duration: int64(now.Sub(start).Seconds()),
+		},
+	}
+
+	needs := func(g GroupBy) bool {
+		return slices.Contains(groupings, g)
+	}
+
+	if entry.ProjectID != nil && (needs(GroupByProject) || needs(GroupByClient) || needs(GroupByTask)) {
+		project, err := s.apiClient.GetProjectByID(ctx, *entry.ProjectID)
+		if err != nil {
+			return report, err
+		}
+		report.ProjectID = project.ID
+		report.ProjectName = project.Name
+
+		if project.ClientID != nil && needs(GroupByClient) {
+			clients, err := s.apiClient.GetClients(ctx)
+			if err != nil {
+				return report, err
+			}
+			report.ClientID = *project.ClientID
+			for _, client := range clients {
+				if client.ID == *project.ClientID {
+					report.ClientName = client.Name
+				}
+			}
+		}
+
+		if entry.TaskID != nil && needs(GroupByTask) {
+			tasks, err := s.apiClient.GetTasksForProject(ctx, project.ID)
+			if err != nil {
+				return report, err
+			}
+			report.TaskID = *entry.TaskID
+			for _, task := range tasks {
+				if task.ID == *entry.TaskID {
+					report.TaskName = task.Name
+				}
+			}
+		}
+	}
+
+	if len(entry.TagIDs) > 0 && needs(GroupByTag) {
+		tags, err := s.apiClient.GetTags(ctx)
+		if err != nil {
+			return report, err
+		}
+		for _, tag := range tags {
+			if slices.Contains(entry.TagIDs, tag.ID) {
+				report.Tags = append(report.Tags, api.ReportTag{ID: tag.ID, Name: tag.Name})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+type groupKey struct {
+	key  string
+	name string
+}
+
+// groupEntries groups by the first dimension and each group by then; parent
+// is the dimension the entries were already grouped by, if any.
+func groupEntries(entries []api.ReportTimeEntry, by, then, parent GroupBy, loc *time.Location) []Group {
+	index := make(map[string]int)
+	var groups []Group
+	members := make(map[string][]api.ReportTimeEntry)
+
+	for _, entry := range entries {
+		duration := seconds(entry.TimeInterval.Duration)
+		for _, k := range entryKeys(&entry, by, parent, loc) {
+			i, ok := index[k.key]
+			if !ok {
+				i = len(groups)
+				index[k.key] = i
+				groups = append(groups, Group{Key: k.key, Name: k.name})
+			}
+			groups[i].Duration += duration
+			members[k.key] = append(members[k.key], entry)
+		}
+	}
+
+	if then != GroupNone {
+		for i := range groups {
+			groups[i].Children = groupEntries(members[groups[i].Key], then, GroupNone, by, loc)
+		}
+	}
+
+	sortGroups(groups, by)
+	return groups
+}
+
+// entryKeys returns the groups an entry belongs to; only tags can yield
+// several. Task names are qualified with their project unless the tasks are
+// already grouped under it.
+func entryKeys(entry *api.ReportTimeEntry, by, parent GroupBy, loc *time.Location) []groupKey {
+	switch by {
+	case GroupByProject:
+		if entry.ProjectID == "" {
+			return []groupKey{{noProjectID, "No Project"}}
+		}
+		return []groupKey{{entry.ProjectID, entry.ProjectName}}
+
+	case GroupByClient:
+		if entry.ClientID == "" {
+			return []groupKey{{NoClientID, "No Client"}}
+		}
+		return []groupKey{{entry.ClientID, entry.ClientName}}
+
+	case GroupByTask:
+		if entry.TaskID == "" {
+			return []groupKey{{noTaskID, "No Task"}}
+		}
+		if parent == GroupByProject {
+			return []groupKey{{entry.TaskID, entry.TaskName}}
+		}
+		return []groupKey{{entry.TaskID, entry.TaskName + " (" + entry.ProjectName + ")"}}
+
+	case GroupByTag:
+		if len(entry.Tags) == 0 {
+			return []groupKey{{noTagID, "No Tag"}}
+		}
+		keys := make([]groupKey, 0, len(entry.Tags))
+		for _, tag := range entry.Tags {
+			keys = append(keys, groupKey{tag.ID, tag.Name})
+		}
+		return keys
+
+	case GroupByDescription:
+		description := strings.TrimSpace(entry.Description)
+		if description == "" {
+			return []groupKey{{"", "(no description)"}}
+		}
+		return []groupKey{{strings.ToLower(description), description}}
+
+	case GroupByWeekday:
+		weekday := entry.TimeInterval.Start.In(loc).Weekday()
+		return []groupKey{{strconv.Itoa(int(weekday)), weekday.String()}}
+	}
+	return nil
+}
+
+// sortGroups puts the most time first, except for weekdays, which run from
+// Monday to Sunday.
+func sortGroups(groups []Group, by GroupBy) {
+	if by == GroupByWeekday {
+		sort.Slice(groups, func(i, j int) bool {
+			return mondayFirst(groups[i].Key) < mondayFirst(groups[j].Key)
+		})
+		return
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Duration != groups[j].Duration {
+			return groups[i].Duration > groups[j].Duration
+		}
+		return groups[i].Name < groups[j].Name
+	})
+}
+
+func mondayFirst(key string) int {
+	weekday, _ := strconv.Atoi(key)
+	return (weekday + 6) % 7
+}
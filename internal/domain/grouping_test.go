@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/api"
+)
+
+func TestGroupedReportIncludesRunningTimer(t *testing.T) {
+	now := time.Now()
+	today := DayStart(now)
+	projectID := "p1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/reports/detailed"):
+			json.NewEncoder(w).Encode(api.DetailedReport{
+				TimeEntries: []api.ReportTimeEntry{{
+					ID:           "done",
+					ProjectID:    projectID,
+					ProjectName:  "Web",
+					TimeInterval: api.ReportTimeInterval{Start: today, Duration: 3600},
+				}},
+				Totals: []api.ReportTotals{{TotalTime: 3600}},
+			})
+		case strings.HasSuffix(r.URL.Path, "/time-entries") && r.URL.Query().Get("in-progress") == "true":
+			json.NewEncoder(w).Encode([]api.TimeEntry{{
+				ID:           "running",
+				ProjectID:    &projectID,
+				TimeInterval: api.TimeInterval{Start: now.Add(-30 * time.Minute)},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/projects/"+projectID):
+			json.NewEncoder(w).Encode(api.Project{ID: projectID, Name: "Web"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("key", server.URL, api.WithReportsURL(server.URL))
+	client.SetWorkspace("ws")
+	client.SetUserID("user")
+
+	report, err := NewReportService(client).GetGroupedReport(context.Background(), today, today, api.ReportFilter{}, GroupByProject, GroupNone)
+	if err != nil {
+		t.Fatalf("GetGroupedReport: %v", err)
+	}
+
+	if report.Running <= 0 {
+		t.Fatalf("Running = %v, want the running timer's time", report.Running)
+	}
+	if report.Total != time.Hour+report.Running {
+		t.Errorf("Total = %v, want 1h plus running %v", report.Total, report.Running)
+	}
+	if len(report.Groups) != 1 || report.Groups[0].Name != "Web" || report.Groups[0].Duration != report.Total {
+		t.Errorf("groups = %+v, want the running time in Web, adding up to %v", report.Groups, report.Total)
+	}
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"main/internal/api"
+)
+
+// IdleChoice is what to do with the time a timer kept running while the
+// user was away.
+type IdleChoice int
+
+const (
+	// KeepIdle leaves the running entry as it is.
+	KeepIdle IdleChoice = iota
+	// DiscardIdle stops the running entry when the user went idle.
+	DiscardIdle
+	// SplitIdle moves the idle time into an entry of its own and keeps the
+	// timer running from the user's return.
+	SplitIdle
+)
+
+// IdlePeriod is a stretch of time the user was away from the keyboard.
+type IdlePeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (p IdlePeriod) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// IdlePeriod clips the time since start to the running entry. ok is false
+// when no timer is running or it was started after the user came back.
+func (t *TimerState) IdlePeriod(start, end time.Time) (period IdlePeriod, ok bool) {
+	if !t.IsRunning || !end.After(t.StartTime) {
+		return IdlePeriod{}, false
+	}
+	if start.Before(t.StartTime) {
+		start = t.StartTime
+	}
+	return IdlePeriod{Start: start, End: end}, true
+}
+
+// ResolveIdle applies the user's choice about an idle period of the running
+// entry and returns the entry running afterwards, nil once it is stopped.
+//
+// Splitting ends the running entry at the start of the idle period, records
+// the idle period as a separate entry with the same project, task and tags
+// but no description, and starts the timer again from the end of it.
+func (s *TimerService) ResolveIdle(ctx context.Context, choice IdleChoice, period IdlePeriod) (*api.TimeEntry, error) {
+	running := s.state.CurrentEntry
+	if running == nil {
+		return nil, errors.New("no timer running")
+	}
+	if choice == KeepIdle {
+		return running, nil
+	}
+
+	if _, err := s.stopAt(ctx, running, period.Start); err != nil {
+		return nil, err
+	}
+	s.state.Stop()
+	if choice == DiscardIdle {
+		return nil, nil
+	}
+
+	away := EntryToRequest(running)
+	away.Description = ""
+	away.Start = period.Start.UTC()
+	end := period.End.UTC()
+	away.End = &end
+	if _, err := s.create(ctx, away); err != nil {
+		return nil, err
+	}
+
+	resumed := EntryToRequest(running)
+	resumed.Start = end
+	resumed.End = nil
+	entry, err := s.startAt(ctx, resumed)
+	if err != nil {
+		return nil, err
+	}
+	s.state.Start(entry)
+	return entry, nil
+}
+
+// stopAt ends the running entry at end by updating it, so the stop can lie
+// in the past.
+func (s *TimerService) stopAt(ctx context.Context, running *api.TimeEntry, end time.Time) (*api.TimeEntry, error) {
+	end = end.UTC()
+	if s.queue.shouldQueue(running.ID, nil) {
+		return s.queue.stop(running, end)
+	}
+
+	req := EntryToRequest(running)
+	req.End = &end
+	entry, err := s.apiClient.UpdateTimeEntry(ctx, running.ID, req)
+	if s.queue.shouldQueue(running.ID, err) {
+		return s.queue.stop(running, end)
+	}
+	return entry, err
+}
+
+// startAt starts a timer from req.Start; an entry without an end is a
+// running timer to Clockify.
+func (s *TimerService) startAt(ctx context.Context, req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	if s.queue.shouldQueue("", nil) {
+		return s.queue.start(req)
+	}
+
+	entry, err := s.apiClient.CreateTimeEntry(ctx, req)
+	if s.queue.shouldQueue("", err) {
+		return s.queue.start(req)
+	}
+	return entry, err
+}
+
+func (s *TimerService) create(ctx context.Context, req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	if s.queue.shouldQueue("", nil) {
+		return s.queue.create(req)
+	}
+
+	entry, err := s.apiClient.CreateTimeEntry(ctx, req)
+	if s.queue.shouldQueue("", err) {
+		return s.queue.create(req)
+	}
+	return entry, err
+}
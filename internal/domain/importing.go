@@ -0,0 +1,411 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/importer"
+)
+
+type ImportStatus int
+
+const (
+	ImportReady ImportStatus = iota
+	// ImportDuplicate is an entry that already exists with the same start,
+	// end and description, e.g. from an earlier run of the same import.
+	ImportDuplicate
+	ImportOverlap
+	ImportInvalid
+)
+
+func (s ImportStatus) String() string {
+	switch s {
+	case ImportDuplicate:
+		return "duplicate"
+	case ImportOverlap:
+		return "overlap"
+	case ImportInvalid:
+		return "invalid"
+	}
+	return "ready"
+}
+
+type ImportOptions struct {
+	// CreateMissing creates projects, tasks and tags that do not exist yet
+	// instead of marking their entries invalid.
+	CreateMissing bool
+	// AllowOverlaps imports entries that overlap others; they are skipped
+	// otherwise.
+	AllowOverlaps bool
+}
+
+// ImportItem is a record and what the import will do with it. Names are
+// the Clockify ones when they exist, so differences in case are resolved.
+type ImportItem struct {
+	Record   importer.Record
+	Status   ImportStatus
+	Reason   string
+	Project  string
+	Task     string
+	Tags     []string
+	Billable bool
+}
+
+// ImportPlan is the dry run of an import: nothing has been created until it
+// is passed to Commit.
+type ImportPlan struct {
+	Items []ImportItem
+	// NewProjects, NewTasks and NewTags are created by Commit; only names
+	// used by ready items are listed.
+	NewProjects []string
+	NewTasks    []ImportTask
+	NewTags     []string
+
+	projectIDs map[string]string
+	taskIDs    map[string]string
+	tagIDs     map[string]string
+}
+
+// Count returns how many items have the status.
+func (p *ImportPlan) Count(status ImportStatus) int {
+	n := 0
+	for _, item := range p.Items {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+type ImportTask struct {
+	Project string
+	Task    string
+}
+
+func (t ImportTask) String() string {
+	return t.Project + " / " + t.Task
+}
+
+type ImportService struct {
+	projectService *ProjectService
+	tagService     *TagService
+	entryService   *TimeEntryService
+}
+
+func NewImportService(projects *ProjectService, tags *TagService, entries *TimeEntryService) *ImportService {
+	return &ImportService{
+		projectService: projects,
+		tagService:     tags,
+		entryService:   entries,
+	}
+}
+
+// Plan maps the records' names onto the workspace's projects, tasks and
+// tags and checks them against the entries already tracked, in start order.
+func (s *ImportService) Plan(ctx context.Context, records []importer.Record, opts ImportOptions) (*ImportPlan, error) {
+	projects, err := s.projectService.GetAllProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagService.GetAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{
+		projectIDs: make(map[string]string),
+		taskIDs:    make(map[string]string),
+		tagIDs:     make(map[string]string),
+	}
+	projectsByName := make(map[string]*api.Project)
+	for i := range projects {
+		projectsByName[strings.ToLower(projects[i].Name)] = &projects[i]
+		plan.projectIDs[strings.ToLower(projects[i].Name)] = projects[i].ID
+	}
+	tagNames := make(map[string]string)
+	for _, tag := range tags {
+		tagNames[strings.ToLower(tag.Name)] = tag.Name
+		plan.tagIDs[strings.ToLower(tag.Name)] = tag.ID
+	}
+
+	sorted := make([]importer.Record, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	taskNames := make(map[string]map[string]string)
+
+	for _, record := range sorted {
+		item := ImportItem{Record: record}
+		plan.Items = append(plan.Items, item)
+		current := &plan.Items[len(plan.Items)-1]
+
+		if reason := recordProblem(record); reason != "" {
+			current.Status, current.Reason = ImportInvalid, reason
+			continue
+		}
+
+		var missing []string
+		if record.Project != "" {
+			key := strings.ToLower(record.Project)
+			if project, ok := projectsByName[key]; ok {
+				current.Project = project.Name
+				current.Billable = project.Billable
+			} else {
+				current.Project = record.Project
+				missing = append(missing, "project "+record.Project)
+			}
+		}
+
+		if record.Task != "" {
+			if record.Project == "" {
+				current.Status, current.Reason = ImportInvalid, "task without a project"
+				continue
+			}
+			name, err := s.taskName(ctx, projectsByName, taskNames, plan, record.Project, record.Task)
+			if err != nil {
+				return nil, err
+			}
+			if name != "" {
+				current.Task = name
+			} else {
+				current.Task = record.Task
+				missing = append(missing, "task "+record.Task)
+			}
+		}
+
+		for _, tag := range record.Tags {
+			key := strings.ToLower(tag)
+			if name, ok := tagNames[key]; ok {
+				current.Tags = append(current.Tags, name)
+				continue
+			}
+			current.Tags = append(current.Tags, tag)
+			missing = append(missing, "tag "+tag)
+		}
+
+		if record.Billable != nil {
+			current.Billable = *record.Billable
+		}
+		if len(missing) > 0 && !opts.CreateMissing {
+			current.Status, current.Reason = ImportInvalid, "unknown "+strings.Join(missing, ", ")
+		}
+	}
+
+	if err := s.checkOverlaps(ctx, plan, opts.AllowOverlaps); err != nil {
+		return nil, err
+	}
+	plan.listMissing()
+	return plan, nil
+}
+
+// listMissing collects the names the ready items need created.
+func (p *ImportPlan) listMissing() {
+	seen := make(map[string]bool)
+	for _, item := range p.Items {
+		if item.Status != ImportReady {
+			continue
+		}
+		if key := strings.ToLower(item.Project); key != "" && p.projectIDs[key] == "" && !seen["p"+key] {
+			seen["p"+key] = true
+			p.NewProjects = append(p.NewProjects, item.Project)
+		}
+		if key := taskKey(item.Project, item.Task); item.Task != "" && p.taskIDs[key] == "" && !seen["t"+key] {
+			seen["t"+key] = true
+			p.NewTasks = append(p.NewTasks, ImportTask{item.Project, item.Task})
+		}
+		for _, tag := range item.Tags {
+			if key := strings.ToLower(tag); p.tagIDs[key] == "" && !seen["g"+key] {
+				seen["g"+key] = true
+				p.NewTags = append(p.NewTags, tag)
+			}
+		}
+	}
+}
+
+func recordProblem(record importer.Record) string {
+	switch {
+	case record.Problem != "":
+		return record.Problem
+	case record.End.IsZero():
+		return "still running, no end time"
+	case !record.End.After(record.Start):
+		return "ends before it starts"
+	}
+	return ""
+}
+
+// taskName returns the Clockify name of a project's task, or "" if the task
+// or the project does not exist yet.
+func (s *ImportService) taskName(ctx context.Context, projects map[string]*api.Project, names map[string]map[string]string, plan *ImportPlan, projectName, taskName string) (string, error) {
+	project, ok := projects[strings.ToLower(projectName)]
+	if !ok {
+		return "", nil
+	}
+	tasks, ok := names[project.ID]
+	if !ok {
+		list, err := s.projectService.GetTasksForProject(ctx, project.ID)
+		if err != nil {
+			return "", err
+		}
+		tasks = make(map[string]string, len(list))
+		for _, task := range list {
+			tasks[strings.ToLower(task.Name)] = task.Name
+			plan.taskIDs[taskKey(project.Name, task.Name)] = task.ID
+		}
+		names[project.ID] = tasks
+	}
+	return tasks[strings.ToLower(taskName)], nil
+}
+
+func taskKey(project, task string) string {
+	return strings.ToLower(project) + "\x00" + strings.ToLower(task)
+}
+
+// checkOverlaps marks items that duplicate or overlap a tracked entry, or an
+// earlier item of the same import.
+func (s *ImportService) checkOverlaps(ctx context.Context, plan *ImportPlan, allow bool) error {
+	var first, last time.Time
+	for _, item := range plan.Items {
+		if item.Status != ImportReady {
+			continue
+		}
+		if first.IsZero() || item.Record.Start.Before(first) {
+			first = item.Record.Start
+		}
+		if item.Record.End.After(last) {
+			last = item.Record.End
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	// Entries starting a day early may still run into the first item.
+	entries, err := s.entryService.GetEntriesForRange(ctx, first.AddDate(0, 0, -1), last)
+	if err != nil {
+		return err
+	}
+
+	type span struct {
+		start, end  time.Time
+		description string
+		imported    bool
+	}
+	var taken []span
+	for _, entry := range entries {
+		end := time.Now()
+		if entry.TimeInterval.End != nil {
+			end = *entry.TimeInterval.End
+		}
+		taken = append(taken, span{entry.TimeInterval.Start, end, entry.Description, false})
+	}
+
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		if item.Status != ImportReady {
+			continue
+		}
+		start, end := item.Record.Start, item.Record.End
+
+		for _, other := range taken {
+			if other.start.Equal(start) && other.end.Equal(end) && other.description == item.Record.Description {
+				item.Status, item.Reason = ImportDuplicate, "already tracked"
+				if other.imported {
+					item.Reason = "listed twice"
+				}
+				break
+			}
+			if other.start.Before(end) && start.Before(other.end) {
+				item.Reason = fmt.Sprintf("overlaps %s-%s", other.start.Local().Format("15:04"), other.end.Local().Format("15:04"))
+				if other.description != "" {
+					item.Reason += " " + other.description
+				}
+				if !allow {
+					item.Status = ImportOverlap
+				}
+				break
+			}
+		}
+
+		if item.Status != ImportDuplicate {
+			taken = append(taken, span{start, end, item.Record.Description, true})
+		}
+	}
+	return nil
+}
+
+// Commit creates the missing projects, tasks and tags and then the ready
+// entries, in order. It stops at the first failure and returns how many
+// entries were created; planning the same records again skips those as
+// duplicates.
+func (s *ImportService) Commit(ctx context.Context, plan *ImportPlan) (int, error) {
+	for len(plan.NewProjects) > 0 {
+		name := plan.NewProjects[0]
+		project, err := s.projectService.CreateProject(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+		plan.projectIDs[strings.ToLower(name)] = project.ID
+		plan.NewProjects = plan.NewProjects[1:]
+	}
+
+	for len(plan.NewTasks) > 0 {
+		next := plan.NewTasks[0]
+		task, err := s.projectService.CreateTask(ctx, plan.projectIDs[strings.ToLower(next.Project)], next.Task)
+		if err != nil {
+			return 0, err
+		}
+		plan.taskIDs[taskKey(next.Project, next.Task)] = task.ID
+		plan.NewTasks = plan.NewTasks[1:]
+	}
+
+	for len(plan.NewTags) > 0 {
+		tag, err := s.tagService.CreateTag(ctx, plan.NewTags[0])
+		if err != nil {
+			return 0, err
+		}
+		plan.tagIDs[strings.ToLower(plan.NewTags[0])] = tag.ID
+		plan.NewTags = plan.NewTags[1:]
+	}
+
+	created := 0
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		if item.Status != ImportReady {
+			continue
+		}
+		if _, err := s.entryService.CreateTimeEntry(ctx, plan.request(item)); err != nil {
+			return created, fmt.Errorf("line %d: %w", item.Record.Line, err)
+		}
+		// A second Commit of the same plan must not create it again.
+		item.Status, item.Reason = ImportDuplicate, "imported"
+		created++
+	}
+	return created, nil
+}
+
+func (p *ImportPlan) request(item *ImportItem) api.TimeEntryRequest {
+	end := item.Record.End.UTC()
+	req := api.TimeEntryRequest{
+		Start:       item.Record.Start.UTC(),
+		End:         &end,
+		Description: item.Record.Description,
+		Billable:    item.Billable,
+	}
+	if id, ok := p.projectIDs[strings.ToLower(item.Project)]; ok && item.Project != "" {
+		req.ProjectID = &id
+		if taskID, ok := p.taskIDs[taskKey(item.Project, item.Task)]; ok && item.Task != "" {
+			req.TaskID = &taskID
+		}
+	}
+	for _, tag := range item.Tags {
+		if id, ok := p.tagIDs[strings.ToLower(tag)]; ok {
+			req.TagIDs = append(req.TagIDs, id)
+		}
+	}
+	return req
+}
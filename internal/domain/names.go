@@ -0,0 +1,106 @@
+package domain
+
+import "main/internal/api"
+
+const (
+	noProjectID = "no-project"
+	noTaskID    = "no-task"
+	noTagID     = "no-tag"
+
+	// NoClientID groups time on projects without a client, and on no project.
+	NoClientID = "no-client"
+)
+
+// Names resolves the IDs found in time entries to display names.
+type Names struct {
+	Projects map[string]string
+	Tasks    map[string]string
+	Tags     map[string]string
+	Clients  map[string]string
+
+	// ProjectClients maps project IDs to the ID of the client they belong to.
+	ProjectClients map[string]string
+}
+
+func NewNames() *Names {
+	return &Names{
+		Projects:       make(map[string]string),
+		Tasks:          make(map[string]string),
+		Tags:           make(map[string]string),
+		Clients:        make(map[string]string),
+		ProjectClients: make(map[string]string),
+	}
+}
+
+func (n *Names) AddProjects(projects []api.Project) {
+	for _, p := range projects {
+		n.Projects[p.ID] = p.Name
+		if p.ClientID != nil && *p.ClientID != "" {
+			n.ProjectClients[p.ID] = *p.ClientID
+		}
+	}
+}
+
+func (n *Names) AddTasks(tasks []api.Task) {
+	for _, t := range tasks {
+		n.Tasks[t.ID] = t.Name
+	}
+}
+
+func (n *Names) AddTags(tags []api.Tag) {
+	for _, t := range tags {
+		n.Tags[t.ID] = t.Name
+	}
+}
+
+func (n *Names) AddClients(clients []api.ClockifyClient) {
+	for _, c := range clients {
+		n.Clients[c.ID] = c.Name
+	}
+}
+
+func (n *Names) Project(projectID string) string {
+	return lookup(n.Projects, projectID)
+}
+
+func (n *Names) Task(taskID string) string {
+	return lookup(n.Tasks, taskID)
+}
+
+func (n *Names) Tag(tagID string) string {
+	return lookup(n.Tags, tagID)
+}
+
+// Client returns the client of a project, or false if it has none.
+func (n *Names) Client(projectID string) (clientID, clientName string, ok bool) {
+	clientID, ok = n.ProjectClients[projectID]
+	if !ok {
+		return "", "", false
+	}
+	return clientID, lookup(n.Clients, clientID), true
+}
+
+// ProjectLabel returns "Client / Project", or just the project name when the
+// project has no client.
+func (n *Names) ProjectLabel(projectID string) string {
+	if _, client, ok := n.Client(projectID); ok {
+		return client + " / " + n.Project(projectID)
+	}
+	return n.Project(projectID)
+}
+
+// ProjectLabels returns ProjectLabel for every known project, keyed by ID.
+func (n *Names) ProjectLabels() map[string]string {
+	labels := make(map[string]string, len(n.Projects))
+	for projectID := range n.Projects {
+		labels[projectID] = n.ProjectLabel(projectID)
+	}
+	return labels
+}
+
+func lookup(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"time"
+
+	"main/internal/api"
+	"main/internal/journal"
+)
+
+// offlineQueue records writes in the journal when Clockify cannot be reached.
+// Once anything is queued for the workspace, later writes are queued as well
+// so that the journal replays them in the order they were made.
+type offlineQueue struct {
+	apiClient *api.Client
+	journal   *journal.Journal
+}
+
+func newOfflineQueue(client *api.Client, j *journal.Journal) *offlineQueue {
+	if j == nil {
+		return nil
+	}
+	return &offlineQueue{apiClient: client, journal: j}
+}
+
+func (q *offlineQueue) pending() []journal.Op {
+	if q == nil {
+		return nil
+	}
+	ops, _, err := q.journal.Pending(q.apiClient.GetWorkspaceID())
+	if err != nil {
+		return nil
+	}
+	return ops
+}
+
+// shouldQueue reports whether a write must go to the journal instead of, or
+// after failing against, the API.
+func (q *offlineQueue) shouldQueue(entryID string, err error) bool {
+	if q == nil {
+		return false
+	}
+	if err != nil {
+		return api.IsNetworkError(err)
+	}
+	return journal.IsLocalID(entryID) || len(q.pending()) > 0
+}
+
+func (q *offlineQueue) record(op journal.Op) error {
+	op.WorkspaceID = q.apiClient.GetWorkspaceID()
+	_, err := q.journal.Append(op)
+	return err
+}
+
+func (q *offlineQueue) start(req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	entryID := journal.NewLocalID()
+	if err := q.record(journal.Op{Kind: journal.OpStart, EntryID: entryID, Request: &req}); err != nil {
+		return nil, err
+	}
+	return entryFromRequest(entryID, req), nil
+}
+
+func (q *offlineQueue) stop(running *api.TimeEntry, end time.Time) (*api.TimeEntry, error) {
+	if running == nil {
+		return nil, q.record(journal.Op{Kind: journal.OpStop, End: &end})
+	}
+
+	op := journal.Op{Kind: journal.OpStop, EntryID: running.ID, End: &end}
+	if !journal.IsLocalID(running.ID) {
+		op.Base = running
+	}
+	if err := q.record(op); err != nil {
+		return nil, err
+	}
+
+	stopped := *running
+	stopped.TimeInterval.End = &end
+	return &stopped, nil
+}
+
+func (q *offlineQueue) create(req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	entryID := journal.NewLocalID()
+	if err := q.record(journal.Op{Kind: journal.OpCreate, EntryID: entryID, Request: &req}); err != nil {
+		return nil, err
+	}
+	return entryFromRequest(entryID, req), nil
+}
+
+func (q *offlineQueue) update(entryID string, req api.TimeEntryRequest, base *api.TimeEntry) (*api.TimeEntry, error) {
+	if err := q.record(journal.Op{Kind: journal.OpUpdate, EntryID: entryID, Request: &req, Base: base}); err != nil {
+		return nil, err
+	}
+	return entryFromRequest(entryID, req), nil
+}
+
+func (q *offlineQueue) delete(entryID string, base *api.TimeEntry) error {
+	return q.record(journal.Op{Kind: journal.OpDelete, EntryID: entryID, Base: base})
+}
+
+// runningTimer derives the timer state from queued ops. known is false when
+// no queued op affects the timer and the server has to be asked.
+func (q *offlineQueue) runningTimer() (running *api.TimeEntry, known bool) {
+	for _, op := range q.pending() {
+		switch op.Kind {
+		case journal.OpStart:
+			running, known = entryFromRequest(op.EntryID, *op.Request), true
+		case journal.OpStop:
+			running, known = nil, true
+		case journal.OpUpdate:
+			if running != nil && running.ID == op.EntryID {
+				running = entryFromRequest(op.EntryID, *op.Request)
+			}
+		case journal.OpDelete:
+			if running != nil && running.ID == op.EntryID {
+				running = nil
+			}
+		}
+	}
+	return running, known
+}
+
+func entryFromRequest(entryID string, req api.TimeEntryRequest) *api.TimeEntry {
+	return &api.TimeEntry{
+		ID:          entryID,
+		Description: req.Description,
+		ProjectID:   req.ProjectID,
+		TaskID:      req.TaskID,
+		TagIDs:      req.TagIDs,
+		Billable:    req.Billable,
+		TimeInterval: api.TimeInterval{
+			Start: req.Start,
+			End:   req.End,
+		},
+	}
+}
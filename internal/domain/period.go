@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// DayStart returns midnight of t's day in t's location.
+func DayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// WeekStart returns midnight of the Monday of t's week.
+func WeekStart(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return DayStart(t).AddDate(0, 0, -(weekday - 1))
+}
+
+// MonthStart returns midnight of the first day of t's month.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"main/internal/api"
+)
+
+// PomodoroPhase is the part of a Pomodoro cycle the timer is in.
+type PomodoroPhase int
+
+const (
+	PomodoroWork PomodoroPhase = iota
+	PomodoroShortBreak
+	PomodoroLongBreak
+)
+
+func (p PomodoroPhase) String() string {
+	switch p {
+	case PomodoroShortBreak:
+		return "Short break"
+	case PomodoroLongBreak:
+		return "Long break"
+	}
+	return "Work"
+}
+
+func (p PomodoroPhase) IsBreak() bool {
+	return p != PomodoroWork
+}
+
+// PomodoroSettings are the interval lengths of Pomodoro mode.
+type PomodoroSettings struct {
+	Work       time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+	// LongBreakEvery is the number of work intervals before a long break.
+	LongBreakEvery int
+	// StopOnBreak stops the timer during breaks; otherwise the break is
+	// tracked in the entry of the work interval before it.
+	StopOnBreak bool
+}
+
+func DefaultPomodoroSettings() PomodoroSettings {
+	return PomodoroSettings{
+		Work:           25 * time.Minute,
+		ShortBreak:     5 * time.Minute,
+		LongBreak:      15 * time.Minute,
+		LongBreakEvery: 4,
+		StopOnBreak:    true,
+	}
+}
+
+// Pomodoro counts down work intervals and breaks. Each work interval is
+// tracked as its own entry, started from Template.
+type Pomodoro struct {
+	Settings PomodoroSettings
+	Active   bool
+	Phase    PomodoroPhase
+	// PhaseStart is when the current interval began.
+	PhaseStart time.Time
+	// Completed counts the work intervals finished since Pomodoro mode was
+	// turned on.
+	Completed int
+	// Template holds the description, project, task, tags and billable flag
+	// of the entries started for work intervals.
+	Template *api.TimeEntry
+}
+
+func NewPomodoro(settings PomodoroSettings) *Pomodoro {
+	return &Pomodoro{Settings: settings}
+}
+
+// Start begins a work interval at now for the entry that is tracking it.
+func (p *Pomodoro) Start(entry *api.TimeEntry, now time.Time) {
+	template := *entry
+	p.Active = true
+	p.Phase = PomodoroWork
+	p.PhaseStart = now
+	p.Completed = 0
+	p.Template = &template
+}
+
+func (p *Pomodoro) Stop() {
+	p.Active = false
+	p.Template = nil
+}
+
+// Length returns how long the current interval lasts.
+func (p *Pomodoro) Length() time.Duration {
+	switch p.Phase {
+	case PomodoroShortBreak:
+		return p.Settings.ShortBreak
+	case PomodoroLongBreak:
+		return p.Settings.LongBreak
+	}
+	return p.Settings.Work
+}
+
+// Remaining returns the time left in the current interval, never below zero.
+func (p *Pomodoro) Remaining(now time.Time) time.Duration {
+	return max(p.PhaseStart.Add(p.Length()).Sub(now), 0)
+}
+
+// Cycle returns the number of the current work interval within the set
+// that ends with a long break, counting from 1.
+func (p *Pomodoro) Cycle() int {
+	every := max(p.Settings.LongBreakEvery, 1)
+	if p.Phase.IsBreak() {
+		return (p.Completed-1)%every + 1
+	}
+	return p.Completed%every + 1
+}
+
+// Advance moves to the next interval once the current one is over and
+// reports whether it did. If the next interval is over as well, e.g. after
+// the computer slept, it starts at now instead.
+func (p *Pomodoro) Advance(now time.Time) bool {
+	if !p.Active || p.Remaining(now) > 0 {
+		return false
+	}
+
+	end := p.PhaseStart.Add(p.Length())
+	if p.Phase.IsBreak() {
+		p.Phase = PomodoroWork
+	} else {
+		p.Completed++
+		p.Phase = PomodoroShortBreak
+		if p.Completed%max(p.Settings.LongBreakEvery, 1) == 0 {
+			p.Phase = PomodoroLongBreak
+		}
+	}
+	p.PhaseStart = end
+	if p.Remaining(now) == 0 {
+		p.PhaseStart = now
+	}
+	return true
+}
+
+// StartPomodoroInterval tracks a new work interval in an entry of its own,
+// stopping the entry of the previous interval if it still runs.
+func (s *TimerService) StartPomodoroInterval(ctx context.Context, template *api.TimeEntry) (*api.TimeEntry, error) {
+	if s.state.IsRunning {
+		if _, _, err := s.StopTimer(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.StartTimer(ctx, template.Description, template.ProjectID, template.TaskID, template.TagIDs, template.Billable)
+}
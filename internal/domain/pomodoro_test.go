@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"main/internal/api"
+)
+
+func TestPomodoroAdvance(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	settings := DefaultPomodoroSettings()
+
+	tests := []struct {
+		name      string
+		phase     PomodoroPhase
+		completed int
+		now       time.Time
+		advanced  bool
+		wantPhase PomodoroPhase
+		wantStart time.Time
+	}{
+		{
+			name:      "work interval still running",
+			phase:     PomodoroWork,
+			now:       start.Add(10 * time.Minute),
+			wantPhase: PomodoroWork,
+			wantStart: start,
+		},
+		{
+			name:      "work interval over starts a short break where it ended",
+			phase:     PomodoroWork,
+			now:       start.Add(26 * time.Minute),
+			advanced:  true,
+			wantPhase: PomodoroShortBreak,
+			wantStart: start.Add(25 * time.Minute),
+		},
+		{
+			name:      "fourth work interval is followed by a long break",
+			phase:     PomodoroWork,
+			completed: 3,
+			now:       start.Add(25 * time.Minute),
+			advanced:  true,
+			wantPhase: PomodoroLongBreak,
+			wantStart: start.Add(25 * time.Minute),
+		},
+		{
+			name:      "break over starts work",
+			phase:     PomodoroShortBreak,
+			completed: 1,
+			now:       start.Add(6 * time.Minute),
+			advanced:  true,
+			wantPhase: PomodoroWork,
+			wantStart: start.Add(5 * time.Minute),
+		},
+		{
+			name:      "after sleeping through the break the next interval starts now",
+			phase:     PomodoroWork,
+			now:       start.Add(3 * time.Hour),
+			advanced:  true,
+			wantPhase: PomodoroShortBreak,
+			wantStart: start.Add(3 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPomodoro(settings)
+			p.Start(&api.TimeEntry{ID: "entry"}, start)
+			p.Phase = tt.phase
+			p.Completed = tt.completed
+
+			if got := p.Advance(tt.now); got != tt.advanced {
+				t.Errorf("Advance = %v, want %v", got, tt.advanced)
+			}
+			if p.Phase != tt.wantPhase {
+				t.Errorf("Phase = %v, want %v", p.Phase, tt.wantPhase)
+			}
+			if !p.PhaseStart.Equal(tt.wantStart) {
+				t.Errorf("PhaseStart = %v, want %v", p.PhaseStart, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestPomodoroAdvanceInactive(t *testing.T) {
+	p := NewPomodoro(DefaultPomodoroSettings())
+	if p.Advance(time.Now()) {
+		t.Error("Advance moved an inactive Pomodoro")
+	}
+}
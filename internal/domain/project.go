@@ -1,12 +1,22 @@
 package domain
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"strings"
 
 	"main/internal/api"
 	"main/internal/cache"
 )
 
+const (
+	// A recent project outranks a slightly better fuzzy match, but not a
+	// clearly better one.
+	recentProjectBoost = 24
+	recentProjectDecay = 2
+)
+
 type ProjectService struct {
 	apiClient *api.Client
 	cache     *cache.Cache
@@ -19,22 +29,28 @@ func NewProjectService(client *api.Client, cache *cache.Cache) *ProjectService {
 	}
 }
 
-func (s *ProjectService) GetAllProjects() ([]api.Project, error) {
+func (s *ProjectService) GetAllProjects(ctx context.Context) ([]api.Project, error) {
 	if projects, ok := s.cache.GetProjects(); ok {
 		return projects, nil
 	}
 
-	projects, err := s.apiClient.GetProjects()
-	if err != nil {
-		return nil, err
+	projects, err := s.ReloadProjects(ctx)
+	if api.IsNetworkError(err) {
+		// Offline, expired cached data is better than none.
+		if stale := s.cache.Snapshot().Projects; len(stale) > 0 {
+			return stale, nil
+		}
 	}
+	return projects, err
+}
 
-	s.cache.SetProjects(projects)
-	return projects, nil
+// ReloadProjects fetches projects from the API even if the cache is fresh.
+func (s *ProjectService) ReloadProjects(ctx context.Context) ([]api.Project, error) {
+	return reload(ctx, s.apiClient, s.apiClient.GetProjects, s.cache.SetProjects)
 }
 
-func (s *ProjectService) GetProjectByID(id string) (*api.Project, error) {
-	projects, err := s.GetAllProjects()
+func (s *ProjectService) GetProjectByID(ctx context.Context, id string) (*api.Project, error) {
+	projects, err := s.GetAllProjects(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -45,25 +61,36 @@ func (s *ProjectService) GetProjectByID(id string) (*api.Project, error) {
 		}
 	}
 
-	return s.apiClient.GetProjectByID(id)
+	return s.apiClient.GetProjectByID(ctx, id)
 }
 
-func (s *ProjectService) GetTasksForProject(projectID string) ([]api.Task, error) {
+func (s *ProjectService) GetTasksForProject(ctx context.Context, projectID string) ([]api.Task, error) {
 	if tasks, ok := s.cache.GetTasks(projectID); ok {
 		return tasks, nil
 	}
 
-	tasks, err := s.apiClient.GetTasksForProject(projectID)
-	if err != nil {
-		return nil, err
+	tasks, err := s.ReloadTasksForProject(ctx, projectID)
+	if api.IsNetworkError(err) {
+		if stale, ok := s.cache.Snapshot().Tasks[projectID]; ok {
+			return stale, nil
+		}
 	}
+	return tasks, err
+}
 
-	s.cache.SetTasks(projectID, tasks)
-	return tasks, nil
+func (s *ProjectService) ReloadTasksForProject(ctx context.Context, projectID string) ([]api.Task, error) {
+	fetch := func(ctx context.Context) ([]api.Task, error) {
+		return s.apiClient.GetTasksForProject(ctx, projectID)
+	}
+	store := func(tasks []api.Task) {
+		s.cache.SetTasks(projectID, tasks)
+	}
+	return reload(ctx, s.apiClient, fetch, store)
 }
 
-func (s *ProjectService) SearchProjects(query string) ([]api.Project, error) {
-	projects, err := s.GetAllProjects()
+// SearchProjects returns the projects fuzzy-matching query, best match first.
+func (s *ProjectService) SearchProjects(ctx context.Context, query string) ([]api.Project, error) {
+	projects, err := s.GetAllProjects(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -72,19 +99,94 @@ func (s *ProjectService) SearchProjects(query string) ([]api.Project, error) {
 		return projects, nil
 	}
 
-	query = strings.ToLower(query)
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+
 	var filtered []api.Project
-	for _, p := range projects {
-		if strings.Contains(strings.ToLower(p.Name), query) {
-			filtered = append(filtered, p)
-		}
+	for _, match := range FuzzyFilter(query, names, nil) {
+		filtered = append(filtered, projects[match.Index])
 	}
 
 	return filtered, nil
 }
 
-func (s *ProjectService) RefreshCache() error {
+// RecentProjectBoost returns per-project score bonuses for FuzzyFilter that
+// rank recently used projects higher; recentIDs is ordered most recent first.
+func RecentProjectBoost(projects []api.Project, recentIDs []string) []int {
+	rank := make(map[string]int, len(recentIDs))
+	for i, id := range recentIDs {
+		if _, ok := rank[id]; !ok {
+			rank[id] = i
+		}
+	}
+
+	boost := make([]int, len(projects))
+	for i, p := range projects {
+		if r, ok := rank[p.ID]; ok {
+			boost[i] = max(recentProjectBoost-r*recentProjectDecay, recentProjectDecay)
+		}
+	}
+	return boost
+}
+
+func (s *ProjectService) FindProject(ctx context.Context, nameOrID string) (*api.Project, error) {
+	projects, err := s.GetAllProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range projects {
+		if projects[i].ID == nameOrID || strings.EqualFold(projects[i].Name, nameOrID) {
+			return &projects[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("project %q not found", nameOrID)
+}
+
+func (s *ProjectService) FindTask(ctx context.Context, projectID, nameOrID string) (*api.Task, error) {
+	tasks, err := s.GetTasksForProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		if tasks[i].ID == nameOrID || strings.EqualFold(tasks[i].Name, nameOrID) {
+			return &tasks[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("task %q not found", nameOrID)
+}
+
+// CreateProject creates a project with Clockify's defaults and adds it to
+// the cached projects.
+func (s *ProjectService) CreateProject(ctx context.Context, name string) (*api.Project, error) {
+	project, err := s.apiClient.CreateProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if projects, ok := s.cache.GetProjects(); ok {
+		s.cache.SetProjects(append(slices.Clone(projects), *project))
+	}
+	return project, nil
+}
+
+func (s *ProjectService) CreateTask(ctx context.Context, projectID, name string) (*api.Task, error) {
+	task, err := s.apiClient.CreateTask(ctx, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	if tasks, ok := s.cache.GetTasks(projectID); ok {
+		s.cache.SetTasks(projectID, append(slices.Clone(tasks), *task))
+	}
+	return task, nil
+}
+
+func (s *ProjectService) RefreshCache(ctx context.Context) error {
 	s.cache.Clear()
-	_, err := s.GetAllProjects()
+	_, err := s.GetAllProjects(ctx)
 	return err
 }
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"main/internal/api"
@@ -13,12 +14,45 @@ type ReportService struct {
 type DailySummary struct {
 	Date          time.Time
 	TotalDuration time.Duration
+	// Running is the part of TotalDuration tracked by the running timer,
+	// which the breakdowns leave out.
+	Running time.Duration
+	Billing
+	ByProject map[string]*ProjectSummary
+	ByClient  map[string]*ClientSummary
+}
+
+// Billing splits the summarized time into billable and non-billable and
+// totals what the billable part earned, per currency.
+type Billing struct {
+	BillableDuration    time.Duration
+	NonBillableDuration time.Duration
+	Earnings            Earnings
+}
+
+func billingFromTotals(totals api.ReportTotals) Billing {
+	billable := seconds(totals.TotalBillableTime)
+	return Billing{
+		BillableDuration:    billable,
+		NonBillableDuration: seconds(totals.TotalTime) - billable,
+		Earnings:            earningsFromTotals(totals),
+	}
+}
+
+// ClientSummary groups the projects of one client; ByProject shares its
+// entries with the summary's own ByProject.
+type ClientSummary struct {
+	ClientID      string
+	ClientName    string
+	TotalDuration time.Duration
 	ByProject     map[string]*ProjectSummary
 }
 
 type ProjectSummary struct {
 	ProjectID     string
 	ProjectName   string
+	ClientID      string
+	ClientName    string
 	TotalDuration time.Duration
 	ByTask        map[string]*TaskSummary
 }
@@ -33,8 +67,59 @@ type WeeklySummary struct {
 	StartDate     time.Time
 	EndDate       time.Time
 	TotalDuration time.Duration
-	ByDay         map[string]time.Duration
-	ByProject     map[string]*ProjectSummary
+	Running       time.Duration
+	Billing
+	ByDay     map[string]time.Duration
+	ByProject map[string]*ProjectSummary
+	ByClient  map[string]*ClientSummary
+}
+
+// RangeSummary summarizes any span of whole days from Start up to, but not
+// including, End. ByDay and ByWeek are keyed by DayKeyLayout dates, weeks by
+// their Monday.
+type RangeSummary struct {
+	Start         time.Time
+	End           time.Time
+	TotalDuration time.Duration
+	Running       time.Duration
+	Billing
+	ByDay     map[string]time.Duration
+	ByWeek    map[string]time.Duration
+	ByProject map[string]*ProjectSummary
+	ByClient  map[string]*ClientSummary
+}
+
+// DayKeyLayout formats the keys of ByDay and ByWeek.
+const DayKeyLayout = "2006-01-02"
+
+// WeekTotal is the time tracked in one week of a RangeSummary; First and Last
+// are clipped to the summary's span.
+type WeekTotal struct {
+	First    time.Time
+	Last     time.Time
+	Duration time.Duration
+}
+
+// Weeks lists every week touched by the summary in order, including empty
+// ones.
+func (s *RangeSummary) Weeks() []WeekTotal {
+	var weeks []WeekTotal
+	for week := WeekStart(s.Start); week.Before(s.End); week = week.AddDate(0, 0, 7) {
+		first := week
+		if first.Before(s.Start) {
+			first = s.Start
+		}
+		last := week.AddDate(0, 0, 6)
+		if end := s.End.AddDate(0, 0, -1); last.After(end) {
+			last = end
+		}
+		weeks = append(weeks, WeekTotal{
+			First:    first,
+			Last:     last,
+			Duration: s.ByWeek[week.Format(DayKeyLayout)],
+		})
+	}
+	return weeks
 }
 
 func NewReportService(client *api.Client) *ReportService {
@@ -43,148 +128,289 @@ func NewReportService(client *api.Client) *ReportService {
 	}
 }
 
-func (s *ReportService) GetDailySummary(date time.Time, projectMap, taskMap map[string]string) (*DailySummary, error) {
-	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	end := start.Add(24 * time.Hour)
+// CurrentUserID identifies the user whose own time the reports show unless
+// filtered otherwise.
+func (s *ReportService) CurrentUserID() string {
+	return s.apiClient.GetUserID()
+}
 
-	entries, err := s.apiClient.GetTimeEntries(start, end)
+func (s *ReportService) GetDailySummary(ctx context.Context, date time.Time, filter api.ReportFilter) (*DailySummary, error) {
+	start := DayStart(date)
+	summary, err := s.summarize(ctx, start, start.AddDate(0, 0, 1), filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.aggregateDailySummary(date, entries, projectMap, taskMap), nil
+	return &DailySummary{
+		Date:          date,
+		TotalDuration: summary.TotalDuration,
+		Running:       summary.Running,
+		Billing:       summary.Billing,
+		ByProject:     summary.ByProject,
+		ByClient:      summary.ByClient,
+	}, nil
 }
 
-func (s *ReportService) GetWeeklySummary(weekStart time.Time, projectMap, taskMap map[string]string) (*WeeklySummary, error) {
-	start := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
-	end := start.AddDate(0, 0, 7)
-
-	entries, err := s.apiClient.GetTimeEntries(start, end)
+func (s *ReportService) GetWeeklySummary(ctx context.Context, weekStart time.Time, filter api.ReportFilter) (*WeeklySummary, error) {
+	start := DayStart(weekStart)
+	summary, err := s.summarize(ctx, start, start.AddDate(0, 0, 7), filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.aggregateWeeklySummary(start, end, entries, projectMap, taskMap), nil
+	return &WeeklySummary{
+		StartDate:     summary.Start,
+		EndDate:       summary.End,
+		TotalDuration: summary.TotalDuration,
+		Running:       summary.Running,
+		Billing:       summary.Billing,
+		ByDay:         summary.ByDay,
+		ByProject:     summary.ByProject,
+		ByClient:      summary.ByClient,
+	}, nil
 }
 
-func (s *ReportService) aggregateDailySummary(date time.Time, entries []api.TimeEntry, projectMap, taskMap map[string]string) *DailySummary {
-	summary := &DailySummary{
-		Date:      date,
-		ByProject: make(map[string]*ProjectSummary),
+// GetMonthlySummary summarizes the calendar month containing month.
+func (s *ReportService) GetMonthlySummary(ctx context.Context, month time.Time, filter api.ReportFilter) (*RangeSummary, error) {
+	start := MonthStart(month)
+	return s.summarize(ctx, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetRangeSummary summarizes the days from first to last, both included.
+func (s *ReportService) GetRangeSummary(ctx context.Context, first, last time.Time, filter api.ReportFilter) (*RangeSummary, error) {
+	return s.summarize(ctx, DayStart(first), DayStart(last).AddDate(0, 0, 1), filter)
+}
+
+// GetDailyTotals returns the time tracked on each day from start up to, but
+// not including, end, keyed by DayKeyLayout dates.
+func (s *ReportService) GetDailyTotals(ctx context.Context, start, end time.Time, filter api.ReportFilter) (map[string]time.Duration, error) {
+	byDate, err := s.apiClient.GetSummaryReport(ctx, start, end.Add(-time.Millisecond), []string{api.GroupDate}, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		duration := s.calculateDuration(&entry)
-		summary.TotalDuration += duration
+	byDay := make(map[string]time.Duration, len(byDate.GroupOne))
+	for _, group := range byDate.GroupOne {
+		byDay[group.ID] += seconds(group.Duration)
+	}
+	return byDay, nil
+}
 
-		projectID := "no-project"
-		projectName := "No Project"
-		if entry.ProjectID != nil {
-			projectID = *entry.ProjectID
-			if name, ok := projectMap[projectID]; ok {
-				projectName = name
-			} else {
-				projectName = projectID
-			}
-		}
+// summarize asks the server for two summary reports: one grouped by client,
+// project and task, and one grouped by day. The server books time on the day
+// it started in the time zone sent along with the request.
+func (s *ReportService) summarize(ctx context.Context, start, end time.Time, filter api.ReportFilter) (*RangeSummary, error) {
+	// The report range is inclusive of its last instant.
+	last := end.Add(-time.Millisecond)
 
-		if _, ok := summary.ByProject[projectID]; !ok {
-			summary.ByProject[projectID] = &ProjectSummary{
-				ProjectID:   projectID,
-				ProjectName: projectName,
-				ByTask:      make(map[string]*TaskSummary),
-			}
-		}
+	byProject, err := s.apiClient.GetSummaryReport(ctx, start, last, []string{api.GroupClient, api.GroupProject, api.GroupTask}, filter)
+	if err != nil {
+		return nil, err
+	}
+	byDate, err := s.apiClient.GetSummaryReport(ctx, start, last, []string{api.GroupDate}, filter)
+	if err != nil {
+		return nil, err
+	}
 
-		summary.ByProject[projectID].TotalDuration += duration
+	summary := &RangeSummary{
+		Start:     start,
+		End:       end,
+		Billing:   Billing{Earnings: make(Earnings)},
+		ByDay:     make(map[string]time.Duration),
+		ByWeek:    make(map[string]time.Duration),
+		ByProject: make(map[string]*ProjectSummary),
+		ByClient:  make(map[string]*ClientSummary),
+	}
+	if len(byProject.Totals) > 0 {
+		summary.TotalDuration = seconds(byProject.Totals[0].TotalTime)
+		summary.Billing = billingFromTotals(byProject.Totals[0])
+	}
 
-		taskID := "no-task"
-		taskName := "No Task"
-		if entry.TaskID != nil {
-			taskID = *entry.TaskID
-			if name, ok := taskMap[taskID]; ok {
-				taskName = name
-			} else {
-				taskName = taskID
-			}
-		}
+	for _, group := range byProject.GroupOne {
+		addClientGroup(summary, group)
+	}
 
-		if _, ok := summary.ByProject[projectID].ByTask[taskID]; !ok {
-			summary.ByProject[projectID].ByTask[taskID] = &TaskSummary{
-				TaskID:   taskID,
-				TaskName: taskName,
-			}
+	for _, group := range byDate.GroupOne {
+		day, err := time.ParseInLocation(DayKeyLayout, group.ID, start.Location())
+		if err != nil {
+			continue
 		}
+		duration := seconds(group.Duration)
+		summary.ByDay[group.ID] += duration
+		summary.ByWeek[WeekStart(day).Format(DayKeyLayout)] += duration
+	}
 
-		summary.ByProject[projectID].ByTask[taskID].Duration += duration
+	if err := s.addRunning(ctx, summary, filter, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return summary
+	return summary, nil
 }
 
-func (s *ReportService) aggregateWeeklySummary(start, end time.Time, entries []api.TimeEntry, projectMap, taskMap map[string]string) *WeeklySummary {
-	summary := &WeeklySummary{
-		StartDate: start,
-		EndDate:   end,
-		ByDay:     make(map[string]time.Duration),
-		ByProject: make(map[string]*ProjectSummary),
+// addRunning adds the time of the user's running timer to the totals, as
+// the reports API leaves out entries still in progress. The breakdowns by
+// client, project and task are left alone; Running tells how much of the
+// total they miss.
+func (s *ReportService) addRunning(ctx context.Context, summary *RangeSummary, filter api.ReportFilter, now time.Time) error {
+	running, start, err := s.runningEntry(ctx, summary.Start, summary.End, filter, now)
+	if err != nil || running == nil {
+		return err
 	}
 
-	for _, entry := range entries {
-		duration := s.calculateDuration(&entry)
-		summary.TotalDuration += duration
+	elapsed := now.Sub(start)
+	summary.TotalDuration += elapsed
+	summary.Running = elapsed
+	if running.Billable {
+		summary.BillableDuration += elapsed
+	} else {
+		summary.NonBillableDuration += elapsed
+	}
+	summary.ByDay[start.Format(DayKeyLayout)] += elapsed
+	summary.ByWeek[WeekStart(start).Format(DayKeyLayout)] += elapsed
+	return nil
+}
 
-		dayKey := entry.TimeInterval.Start.Format("2006-01-02")
-		summary.ByDay[dayKey] += duration
+// runningEntry returns the user's running timer when the filter lets it
+// through and it runs between start and end, together with when it started
+// within them. It returns nil otherwise.
+func (s *ReportService) runningEntry(ctx context.Context, start, end time.Time, filter api.ReportFilter, now time.Time) (*api.TimeEntry, time.Time, error) {
+	if !start.Before(now) || !now.Before(end) {
+		return nil, time.Time{}, nil
+	}
 
-		projectID := "no-project"
-		projectName := "No Project"
-		if entry.ProjectID != nil {
-			projectID = *entry.ProjectID
-			if name, ok := projectMap[projectID]; ok {
-				projectName = name
-			} else {
-				projectName = projectID
-			}
-		}
+	running, err := s.apiClient.GetCurrentTimer(ctx)
+	if err != nil || running == nil {
+		return nil, time.Time{}, err
+	}
+	matches, err := s.matches(ctx, running, filter)
+	if err != nil || !matches {
+		return nil, time.Time{}, err
+	}
 
-		if _, ok := summary.ByProject[projectID]; !ok {
-			summary.ByProject[projectID] = &ProjectSummary{
-				ProjectID:   projectID,
-				ProjectName: projectName,
-				ByTask:      make(map[string]*TaskSummary),
-			}
+	return running, latest(running.TimeInterval.Start.In(start.Location()), start), nil
+}
+
+// matches reports whether the report filter lets the entry through.
+func (s *ReportService) matches(ctx context.Context, entry *api.TimeEntry, filter api.ReportFilter) (bool, error) {
+	if len(filter.UserIDs) > 0 && !contains(filter.UserIDs, s.CurrentUserID()) {
+		return false, nil
+	}
+	if filter.Billable != nil && *filter.Billable != entry.Billable {
+		return false, nil
+	}
+	if len(filter.ProjectIDs) > 0 && (entry.ProjectID == nil || !contains(filter.ProjectIDs, *entry.ProjectID)) {
+		return false, nil
+	}
+	if len(filter.TaskIDs) > 0 && (entry.TaskID == nil || !contains(filter.TaskIDs, *entry.TaskID)) {
+		return false, nil
+	}
+	if len(filter.TagIDs) > 0 && !containsAny(entry.TagIDs, filter.TagIDs) {
+		return false, nil
+	}
+	if len(filter.ClientIDs) > 0 {
+		if entry.ProjectID == nil {
+			return false, nil
 		}
+		project, err := s.apiClient.GetProjectByID(ctx, *entry.ProjectID)
+		if err != nil {
+			return false, err
+		}
+		if project.ClientID == nil || !contains(filter.ClientIDs, *project.ClientID) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
 
-		summary.ByProject[projectID].TotalDuration += duration
+func contains(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
 
-		taskID := "no-task"
-		taskName := "No Task"
-		if entry.TaskID != nil {
-			taskID = *entry.TaskID
-			if name, ok := taskMap[taskID]; ok {
-				taskName = name
-			} else {
-				taskName = taskID
-			}
+func containsAny(ids, wanted []string) bool {
+	for _, id := range ids {
+		if contains(wanted, id) {
+			return true
 		}
+	}
+	return false
+}
 
-		if _, ok := summary.ByProject[projectID].ByTask[taskID]; !ok {
-			summary.ByProject[projectID].ByTask[taskID] = &TaskSummary{
-				TaskID:   taskID,
-				TaskName: taskName,
+// addClientGroup books a client row of the summary report, with its projects
+// and their tasks. Rows without an ID hold the time without a client, project
+// or task.
+func addClientGroup(summary *RangeSummary, group api.SummaryGroup) {
+	client := &ClientSummary{
+		ClientID:      orDefault(group.ID, NoClientID),
+		ClientName:    "No Client",
+		TotalDuration: seconds(group.Duration),
+		ByProject:     make(map[string]*ProjectSummary),
+	}
+	if group.ID != "" {
+		client.ClientName = group.Name
+	}
+	summary.ByClient[client.ClientID] = client
+
+	for _, projectGroup := range group.Children {
+		project := &ProjectSummary{
+			ProjectID:     orDefault(projectGroup.ID, noProjectID),
+			ProjectName:   "No Project",
+			ClientID:      client.ClientID,
+			ClientName:    client.ClientName,
+			TotalDuration: seconds(projectGroup.Duration),
+			ByTask:        make(map[string]*TaskSummary),
+		}
+		if projectGroup.ID != "" {
+			project.ProjectName = projectGroup.Name
+		}
+		client.ByProject[project.ProjectID] = project
+		summary.ByProject[project.ProjectID] = project
+
+		for _, taskGroup := range projectGroup.Children {
+			task := &TaskSummary{
+				TaskID:   orDefault(taskGroup.ID, noTaskID),
+				TaskName: "No Task",
+				Duration: seconds(taskGroup.Duration),
 			}
+			if taskGroup.ID != "" {
+				task.TaskName = taskGroup.Name
+			}
+			project.ByTask[task.TaskID] = task
 		}
+	}
+}
 
-		summary.ByProject[projectID].ByTask[taskID].Duration += duration
+func orDefault(id, fallback string) string {
+	if id == "" {
+		return fallback
 	}
+	return id
+}
 
-	return summary
+func seconds(n int64) time.Duration {
+	return time.Duration(n) * time.Second
 }
 
-func (s *ReportService) calculateDuration(entry *api.TimeEntry) time.Duration {
-	if entry.TimeInterval.End != nil {
-		return entry.TimeInterval.End.Sub(entry.TimeInterval.Start)
+// FormatTotal formats a report total, pointing out the part of it tracked
+// by the running timer.
+func FormatTotal(total, running time.Duration) string {
+	if running == 0 {
+		return FormatDuration(total)
+	}
+	return FormatDuration(total) + " (incl. " + FormatDuration(running.Round(time.Second)) + " running)"
+}
+
+// HasClients reports whether any of the summarized time belongs to a client,
+// i.e. whether grouping by client adds anything.
+func HasClients(byClient map[string]*ClientSummary) bool {
+	for clientID := range byClient {
+		if clientID != NoClientID {
+			return true
+		}
 	}
-	return time.Since(entry.TimeInterval.Start)
+	return false
 }
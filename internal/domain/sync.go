@@ -0,0 +1,306 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"main/internal/api"
+	"main/internal/journal"
+)
+
+type SyncConflict struct {
+	Op     journal.Op
+	Reason string
+}
+
+type SyncResult struct {
+	Applied   int
+	Conflicts []SyncConflict
+	// Remaining is the number of ops still queued because Clockify became
+	// unreachable again during the replay.
+	Remaining int
+}
+
+// SyncService replays the offline journal against Clockify.
+type SyncService struct {
+	apiClient *api.Client
+	journal   *journal.Journal
+	mu        sync.Mutex
+}
+
+func NewSyncService(client *api.Client, j *journal.Journal) *SyncService {
+	return &SyncService{
+		apiClient: client,
+		journal:   j,
+	}
+}
+
+func (s *SyncService) PendingCount() int {
+	if s.journal == nil {
+		return 0
+	}
+	ops, _, err := s.journal.Pending(s.apiClient.GetWorkspaceID())
+	if err != nil {
+		return 0
+	}
+	return len(ops)
+}
+
+// replay holds the state of one Replay run.
+type replay struct {
+	serverIDs map[string]string
+	// expected is the server version of entries this run already changed, so
+	// later ops on them are not mistaken for conflicts.
+	expected map[string]*api.TimeEntry
+	handled  map[string]bool
+	// folded lists ops merged into the op being applied, to be acked with it.
+	folded []string
+}
+
+// Replay applies queued ops in order. Ops whose target changed on the server
+// since they were recorded are dropped and reported as conflicts; a network
+// error stops the replay and leaves the remaining ops queued.
+func (s *SyncService) Replay(ctx context.Context) (*SyncResult, error) {
+	result := &SyncResult{}
+	if s.journal == nil {
+		return result, nil
+	}
+
+	// A replay already in progress will pick up everything pending.
+	if !s.mu.TryLock() {
+		return result, nil
+	}
+	defer s.mu.Unlock()
+
+	ops, serverIDs, err := s.journal.Pending(s.apiClient.GetWorkspaceID())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &replay{
+		serverIDs: serverIDs,
+		expected:  make(map[string]*api.TimeEntry),
+		handled:   make(map[string]bool),
+	}
+
+	for i, op := range ops {
+		if r.handled[op.ID] {
+			continue
+		}
+
+		serverID, conflict, err := s.apply(ctx, r, op, ops[i+1:])
+		if api.IsNetworkError(err) || ctx.Err() != nil {
+			for _, rest := range ops[i:] {
+				if !r.handled[rest.ID] {
+					result.Remaining++
+				}
+			}
+			return result, nil
+		}
+		if err != nil {
+			conflict = err.Error()
+		}
+
+		if conflict != "" {
+			result.Conflicts = append(result.Conflicts, SyncConflict{Op: op, Reason: conflict})
+		} else {
+			result.Applied++
+		}
+
+		if err := s.journal.Ack(op.ID, serverID); err != nil {
+			return result, err
+		}
+		for _, opID := range r.folded {
+			if err := s.journal.Ack(opID, ""); err != nil {
+				return result, err
+			}
+		}
+		r.folded = nil
+	}
+
+	return result, nil
+}
+
+func (s *SyncService) apply(ctx context.Context, r *replay, op journal.Op, rest []journal.Op) (string, string, error) {
+	switch op.Kind {
+	case journal.OpStart, journal.OpCreate:
+		return s.applyCreate(ctx, r, op, rest)
+	}
+
+	entryID := op.EntryID
+	if journal.IsLocalID(entryID) {
+		serverID, ok := r.serverIDs[entryID]
+		if !ok {
+			return "", "it depends on an offline entry that could not be synced", nil
+		}
+		entryID = serverID
+	}
+
+	base := op.Base
+	if expected, ok := r.expected[entryID]; ok {
+		base = expected
+	}
+
+	switch op.Kind {
+	case journal.OpStop:
+		current, err := s.apiClient.GetCurrentTimer(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		if current == nil || (entryID != "" && current.ID != entryID) {
+			return "", fmt.Sprintf("the timer was already stopped or replaced on the server; the offline stop at %s was not applied",
+				op.End.Local().Format("15:04")), nil
+		}
+		if current.TimeInterval.Start.After(*op.End) {
+			return "", fmt.Sprintf("timer %s was started after the offline stop at %s and was left running",
+				describeEntry(current), op.End.Local().Format("15:04")), nil
+		}
+		entry, err := s.apiClient.StopTimerAt(ctx, *op.End)
+		if err != nil {
+			return "", "", err
+		}
+		r.expected[entryID] = entry
+		return "", "", nil
+
+	case journal.OpUpdate:
+		if base != nil {
+			server, err := s.apiClient.GetTimeEntry(ctx, entryID)
+			if api.IsNotFound(err) {
+				return "", fmt.Sprintf("entry %s was deleted on the server; the offline edit was not applied", describeEntry(base)), nil
+			}
+			if err != nil {
+				return "", "", err
+			}
+			if !sameEntry(base, server) {
+				return "", fmt.Sprintf("entry %s was changed on the server; the offline edit was not applied", describeEntry(server)), nil
+			}
+		}
+		entry, err := s.apiClient.UpdateTimeEntry(ctx, entryID, *op.Request)
+		if err != nil {
+			return "", "", err
+		}
+		r.expected[entryID] = entry
+		return "", "", nil
+
+	case journal.OpDelete:
+		if base != nil {
+			server, err := s.apiClient.GetTimeEntry(ctx, entryID)
+			if api.IsNotFound(err) {
+				return "", "", nil
+			}
+			if err != nil {
+				return "", "", err
+			}
+			if !sameEntry(base, server) {
+				return "", fmt.Sprintf("entry %s was changed on the server; the offline delete was not applied", describeEntry(server)), nil
+			}
+		}
+		if err := s.apiClient.DeleteTimeEntry(ctx, entryID); err != nil && !api.IsNotFound(err) {
+			return "", "", err
+		}
+		delete(r.expected, entryID)
+		return "", "", nil
+	}
+
+	return "", fmt.Sprintf("unknown journal operation %q", op.Kind), nil
+}
+
+// applyCreate creates an entry that was started or logged offline. Later
+// edits, the stop or the delete of the same entry are folded into a single
+// request so the server never sees intermediate states.
+func (s *SyncService) applyCreate(ctx context.Context, r *replay, op journal.Op, rest []journal.Op) (string, string, error) {
+	req := *op.Request
+	var folded []string
+	deleted := false
+	for _, later := range rest {
+		if later.EntryID != op.EntryID || deleted {
+			continue
+		}
+		switch later.Kind {
+		case journal.OpUpdate:
+			end := req.End
+			req = *later.Request
+			if req.End == nil {
+				req.End = end
+			}
+		case journal.OpStop:
+			req.End = later.End
+		case journal.OpDelete:
+			deleted = true
+		}
+		folded = append(folded, later.ID)
+	}
+
+	markFolded := func() {
+		for _, opID := range folded {
+			r.handled[opID] = true
+		}
+		r.folded = folded
+	}
+
+	if deleted {
+		markFolded()
+		return "", "", nil
+	}
+
+	if req.End == nil {
+		current, err := s.apiClient.GetCurrentTimer(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		if current != nil {
+			// The offline entry is dropped as a whole, including its later edits.
+			markFolded()
+			return "", fmt.Sprintf("timer %s was already running on the server; the offline timer started at %s was not created",
+				describeEntry(current), req.Start.Local().Format("15:04")), nil
+		}
+	}
+
+	entry, err := s.apiClient.CreateTimeEntry(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	markFolded()
+	r.serverIDs[op.EntryID] = entry.ID
+	r.expected[entry.ID] = entry
+	return entry.ID, "", nil
+}
+
+func describeEntry(entry *api.TimeEntry) string {
+	if entry.Description == "" {
+		return fmt.Sprintf("from %s", entry.TimeInterval.Start.Local().Format("Jan 2 15:04"))
+	}
+	return fmt.Sprintf("%q", entry.Description)
+}
+
+func sameEntry(a, b *api.TimeEntry) bool {
+	return a.Description == b.Description &&
+		sameID(a.ProjectID, b.ProjectID) &&
+		sameID(a.TaskID, b.TaskID) &&
+		slices.Equal(sortedCopy(a.TagIDs), sortedCopy(b.TagIDs)) &&
+		a.TimeInterval.Start.Equal(b.TimeInterval.Start) &&
+		sameTime(a.TimeInterval.End, b.TimeInterval.End)
+}
+
+func sameID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -1,6 +1,11 @@
 package domain
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
 	"main/internal/api"
 	"main/internal/cache"
 )
@@ -17,16 +22,46 @@ func NewTagService(client *api.Client, cache *cache.Cache) *TagService {
 	}
 }
 
-func (s *TagService) GetAllTags() ([]api.Tag, error) {
+func (s *TagService) GetAllTags(ctx context.Context) ([]api.Tag, error) {
 	if tags, ok := s.cache.GetTags(); ok {
 		return tags, nil
 	}
 
-	tags, err := s.apiClient.GetTags()
+	tags, err := s.ReloadTags(ctx)
+	if api.IsNetworkError(err) {
+		if stale := s.cache.Snapshot().Tags; len(stale) > 0 {
+			return stale, nil
+		}
+	}
+	return tags, err
+}
+
+func (s *TagService) ReloadTags(ctx context.Context) ([]api.Tag, error) {
+	return reload(ctx, s.apiClient, s.apiClient.GetTags, s.cache.SetTags)
+}
+
+func (s *TagService) FindTag(ctx context.Context, nameOrID string) (*api.Tag, error) {
+	tags, err := s.GetAllTags(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cache.SetTags(tags)
-	return tags, nil
+	for i := range tags {
+		if tags[i].ID == nameOrID || strings.EqualFold(tags[i].Name, nameOrID) {
+			return &tags[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("tag %q not found", nameOrID)
+}
+
+func (s *TagService) CreateTag(ctx context.Context, name string) (*api.Tag, error) {
+	tag, err := s.apiClient.CreateTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if tags, ok := s.cache.GetTags(); ok {
+		s.cache.SetTags(append(slices.Clone(tags), *tag))
+	}
+	return tag, nil
 }
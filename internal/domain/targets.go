@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"main/internal/api"
+)
+
+// HourTargets are the hours expected to be tracked. The zero value expects
+// nothing and turns targets off.
+type HourTargets struct {
+	// ByWeekday is the target of each day, indexed by time.Weekday.
+	ByWeekday [7]time.Duration
+	// Weekly is the goal of a full week when it differs from the sum of the
+	// daily targets; zero uses the sum.
+	Weekly time.Duration
+	// Holidays holds the days, as DayKeyLayout keys, that expect nothing.
+	Holidays map[string]bool
+	// BalanceSince is the first day counted in the overtime balance; zero
+	// counts from the start of the current month.
+	BalanceSince time.Time
+}
+
+// NewHourTargets expects daily on Monday to Friday, then applies the
+// per-weekday overrides. With only a weekly target the daily one is a fifth
+// of it.
+func NewHourTargets(daily, weekly time.Duration, overrides map[time.Weekday]time.Duration, holidays []time.Time, balanceSince time.Time) *HourTargets {
+	if daily == 0 && weekly > 0 {
+		daily = weekly / 5
+	}
+
+	targets := &HourTargets{
+		Weekly:       weekly,
+		Holidays:     make(map[string]bool, len(holidays)),
+		BalanceSince: balanceSince,
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		targets.ByWeekday[day] = daily
+	}
+	for day, target := range overrides {
+		targets.ByWeekday[day] = target
+	}
+	for _, holiday := range holidays {
+		targets.Holidays[holiday.Format(DayKeyLayout)] = true
+	}
+	return targets
+}
+
+// IsSet reports whether any time is expected at all.
+func (t *HourTargets) IsSet() bool {
+	if t == nil {
+		return false
+	}
+	for _, target := range t.ByWeekday {
+		if target > 0 {
+			return true
+		}
+	}
+	return t.Weekly > 0
+}
+
+// ForDay returns the time expected on day.
+func (t *HourTargets) ForDay(day time.Time) time.Duration {
+	if t == nil || t.Holidays[day.Format(DayKeyLayout)] {
+		return 0
+	}
+	return t.ByWeekday[day.Weekday()]
+}
+
+// ForRange returns the time expected on the days from start up to, but not
+// including, end.
+func (t *HourTargets) ForRange(start, end time.Time) time.Duration {
+	var total time.Duration
+	for day := DayStart(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		total += t.ForDay(day)
+	}
+	return total
+}
+
+// ForWeek returns the goal of the week starting on weekStart. A weekly
+// target is reduced by the daily targets of the holidays in the week.
+func (t *HourTargets) ForWeek(weekStart time.Time) time.Duration {
+	weekStart = DayStart(weekStart)
+	end := weekStart.AddDate(0, 0, 7)
+	if t == nil || t.Weekly == 0 {
+		return t.ForRange(weekStart, end)
+	}
+
+	weekly := t.Weekly
+	for day := weekStart; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if t.Holidays[day.Format(DayKeyLayout)] {
+			weekly -= t.ByWeekday[day.Weekday()]
+		}
+	}
+	return max(weekly, 0)
+}
+
+func (t *HourTargets) balanceStart(now time.Time) time.Time {
+	if t.BalanceSince.IsZero() {
+		return MonthStart(now)
+	}
+	return DayStart(t.BalanceSince)
+}
+
+// TargetProgress is the time tracked against the targets of a day and its
+// week. Day and Week count finished entries only; the running timer is added
+// by Running.
+type TargetProgress struct {
+	Date       time.Time
+	Day        time.Duration
+	Week       time.Duration
+	DayTarget  time.Duration
+	WeekTarget time.Duration
+	// Balance is the time tracked minus the time expected on the days from
+	// BalanceSince up to, but not including, Date.
+	Balance      time.Duration
+	BalanceSince time.Time
+}
+
+// Running returns the time tracked today and this week, including the part
+// of the running timer since the start of each.
+func (p *TargetProgress) Running(state *TimerState, now time.Time) (day, week time.Duration) {
+	day, week = p.Day, p.Week
+	if !state.IsRunning {
+		return day, week
+	}
+	dayStart := DayStart(p.Date)
+	weekStart := WeekStart(p.Date)
+	day += now.Sub(latest(state.StartTime, dayStart))
+	week += now.Sub(latest(state.StartTime, weekStart))
+	return day, week
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// IsStale reports whether the progress was loaded for a day other than
+// now's.
+func (p *TargetProgress) IsStale(now time.Time) bool {
+	return !DayStart(p.Date).Equal(DayStart(now))
+}
+
+// FormatBalance formats an overtime balance with its sign.
+func FormatBalance(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(-d)
+	}
+	return "+" + FormatDuration(d)
+}
+
+type TargetService struct {
+	entries *TimeEntryService
+	reports *ReportService
+	targets *HourTargets
+}
+
+func NewTargetService(entries *TimeEntryService, reports *ReportService, targets *HourTargets) *TargetService {
+	return &TargetService{
+		entries: entries,
+		reports: reports,
+		targets: targets,
+	}
+}
+
+func (s *TargetService) Targets() *HourTargets {
+	return s.targets
+}
+
+// GetProgress loads the time tracked today and this week from the entries,
+// and the overtime balance before today from the reports API.
+func (s *TargetService) GetProgress(ctx context.Context, now time.Time) (*TargetProgress, error) {
+	today := DayStart(now)
+	weekStart := WeekStart(now)
+
+	entries, err := s.entries.GetEntriesForRange(ctx, weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &TargetProgress{
+		Date:         today,
+		DayTarget:    s.targets.ForDay(today),
+		WeekTarget:   s.targets.ForWeek(weekStart),
+		BalanceSince: s.targets.balanceStart(now),
+	}
+	for _, entry := range entries {
+		if entry.TimeInterval.End == nil {
+			continue
+		}
+		duration := entry.TimeInterval.End.Sub(entry.TimeInterval.Start)
+		progress.Week += duration
+		if DayStart(entry.TimeInterval.Start.In(today.Location())).Equal(today) {
+			progress.Day += duration
+		}
+	}
+
+	if progress.BalanceSince.Before(today) {
+		filter := api.ReportFilter{UserIDs: []string{s.reports.CurrentUserID()}}
+		byDay, err := s.reports.GetDailyTotals(ctx, progress.BalanceSince, today, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, duration := range byDay {
+			progress.Balance += duration
+		}
+		progress.Balance -= s.targets.ForRange(progress.BalanceSince, today)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHourTargetsForWeek(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	wednesday := monday.AddDate(0, 0, 2)
+	saturday := monday.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name      string
+		daily     time.Duration
+		weekly    time.Duration
+		overrides map[time.Weekday]time.Duration
+		holidays  []time.Time
+		want      time.Duration
+	}{
+		{
+			name:  "daily targets add up",
+			daily: 8 * time.Hour,
+			want:  40 * time.Hour,
+		},
+		{
+			name:     "holiday expects nothing",
+			daily:    8 * time.Hour,
+			holidays: []time.Time{wednesday},
+			want:     32 * time.Hour,
+		},
+		{
+			name:     "weekly target is reduced by the holiday's daily target",
+			weekly:   38 * time.Hour,
+			holidays: []time.Time{wednesday},
+			want:     38*time.Hour - 38*time.Hour/5,
+		},
+		{
+			name:      "holiday on a day without a target changes nothing",
+			daily:     8 * time.Hour,
+			weekly:    36 * time.Hour,
+			overrides: map[time.Weekday]time.Duration{time.Friday: 4 * time.Hour},
+			holidays:  []time.Time{saturday},
+			want:      36 * time.Hour,
+		},
+		{
+			name:      "weekly target never goes below zero",
+			daily:     8 * time.Hour,
+			weekly:    10 * time.Hour,
+			overrides: map[time.Weekday]time.Duration{time.Wednesday: 12 * time.Hour},
+			holidays:  []time.Time{wednesday},
+			want:      0,
+		},
+		{
+			name:     "holiday outside the week is ignored",
+			daily:    8 * time.Hour,
+			holidays: []time.Time{monday.AddDate(0, 0, 7)},
+			want:     40 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := NewHourTargets(tt.daily, tt.weekly, tt.overrides, tt.holidays, time.Time{})
+			if got := targets.ForWeek(monday); got != tt.want {
+				t.Errorf("ForWeek = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourTargetsNil(t *testing.T) {
+	var targets *HourTargets
+	if targets.IsSet() {
+		t.Error("IsSet = true for nil targets")
+	}
+	if got := targets.ForWeek(time.Now()); got != 0 {
+		t.Errorf("ForWeek = %v for nil targets, want 0", got)
+	}
+}
@@ -1,35 +1,72 @@
 package domain
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"main/internal/api"
+	"main/internal/journal"
 )
 
 type TimeEntryService struct {
 	apiClient *api.Client
+	queue     *offlineQueue
+
+	// seen holds the last server version of each fetched entry, the base
+	// for detecting conflicts when queued edits are replayed.
+	seen      map[string]api.TimeEntry
+	seenMutex sync.Mutex
 }
 
-func NewTimeEntryService(client *api.Client) *TimeEntryService {
+func NewTimeEntryService(client *api.Client, j *journal.Journal) *TimeEntryService {
 	return &TimeEntryService{
 		apiClient: client,
+		queue:     newOfflineQueue(client, j),
+		seen:      make(map[string]api.TimeEntry),
 	}
 }
 
-func (s *TimeEntryService) GetEntriesForToday() ([]api.TimeEntry, error) {
+func (s *TimeEntryService) remember(entries []api.TimeEntry, err error) ([]api.TimeEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	s.seenMutex.Lock()
+	defer s.seenMutex.Unlock()
+	for _, entry := range entries {
+		s.seen[entry.ID] = entry
+	}
+	return entries, nil
+}
+
+func (s *TimeEntryService) serverVersion(entryID string) *api.TimeEntry {
+	s.seenMutex.Lock()
+	defer s.seenMutex.Unlock()
+	if entry, ok := s.seen[entryID]; ok {
+		return &entry
+	}
+	return nil
+}
+
+func (s *TimeEntryService) GetEntriesForToday(ctx context.Context) ([]api.TimeEntry, error) {
 	now := time.Now()
 	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	end := start.Add(24 * time.Hour)
-	return s.apiClient.GetTimeEntries(start, end)
+	return s.remember(s.apiClient.GetTimeEntries(ctx, start, end))
 }
 
-func (s *TimeEntryService) GetEntriesForDate(date time.Time) ([]api.TimeEntry, error) {
+func (s *TimeEntryService) GetEntriesForDate(ctx context.Context, date time.Time) ([]api.TimeEntry, error) {
 	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	end := start.Add(24 * time.Hour)
-	return s.apiClient.GetTimeEntries(start, end)
+	return s.remember(s.apiClient.GetTimeEntries(ctx, start, end))
 }
 
-func (s *TimeEntryService) GetEntriesForWeek() ([]api.TimeEntry, error) {
+func (s *TimeEntryService) GetEntriesForWeek(ctx context.Context) ([]api.TimeEntry, error) {
 	now := time.Now()
 	weekday := int(now.Weekday())
 	if weekday == 0 {
@@ -38,11 +75,11 @@ func (s *TimeEntryService) GetEntriesForWeek() ([]api.TimeEntry, error) {
 	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).
 		AddDate(0, 0, -(weekday - 1))
 	end := start.AddDate(0, 0, 7)
-	return s.apiClient.GetTimeEntries(start, end)
+	return s.remember(s.apiClient.GetTimeEntries(ctx, start, end))
 }
 
-func (s *TimeEntryService) GetEntriesForRange(start, end time.Time) ([]api.TimeEntry, error) {
-	return s.apiClient.GetTimeEntries(start, end)
+func (s *TimeEntryService) GetEntriesForRange(ctx context.Context, start, end time.Time) ([]api.TimeEntry, error) {
+	return s.remember(s.apiClient.GetTimeEntries(ctx, start, end))
 }
 
 func (s *TimeEntryService) GetDurationForEntry(entry *api.TimeEntry) time.Duration {
@@ -52,6 +89,129 @@ func (s *TimeEntryService) GetDurationForEntry(entry *api.TimeEntry) time.Durati
 	return time.Since(entry.TimeInterval.Start)
 }
 
-func (s *TimeEntryService) GetEntriesByDescriptionContains(description string) ([]api.TimeEntry, error) {
-	return s.apiClient.GetTimeEntriesWithDescriptionContaining(description)
+func (s *TimeEntryService) GetEntriesByDescriptionContains(ctx context.Context, description string) ([]api.TimeEntry, error) {
+	return s.apiClient.GetTimeEntriesWithDescriptionContaining(ctx, description)
+}
+
+func (s *TimeEntryService) UpdateTimeEntry(ctx context.Context, entryID string, req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	if s.queue.shouldQueue(entryID, nil) {
+		return s.queue.update(entryID, req, s.serverVersion(entryID))
+	}
+
+	entry, err := s.apiClient.UpdateTimeEntry(ctx, entryID, req)
+	if s.queue.shouldQueue(entryID, err) {
+		return s.queue.update(entryID, req, s.serverVersion(entryID))
+	}
+	return entry, err
+}
+
+func (s *TimeEntryService) CreateTimeEntry(ctx context.Context, req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	if s.queue.shouldQueue("", nil) {
+		return s.queue.create(req)
+	}
+
+	entry, err := s.apiClient.CreateTimeEntry(ctx, req)
+	if s.queue.shouldQueue("", err) {
+		return s.queue.create(req)
+	}
+	return entry, err
+}
+
+func (s *TimeEntryService) DeleteTimeEntry(ctx context.Context, entryID string) error {
+	if s.queue.shouldQueue(entryID, nil) {
+		return s.queue.delete(entryID, s.serverVersion(entryID))
+	}
+
+	err := s.apiClient.DeleteTimeEntry(ctx, entryID)
+	if s.queue.shouldQueue(entryID, err) {
+		return s.queue.delete(entryID, s.serverVersion(entryID))
+	}
+	return err
+}
+
+func EntryToRequest(entry *api.TimeEntry) api.TimeEntryRequest {
+	return api.TimeEntryRequest{
+		Start:       entry.TimeInterval.Start,
+		End:         entry.TimeInterval.End,
+		Description: entry.Description,
+		ProjectID:   entry.ProjectID,
+		TaskID:      entry.TaskID,
+		TagIDs:      entry.TagIDs,
+		Billable:    entry.Billable,
+	}
+}
+
+// ParseClockTime parses an "HH:MM" value as a wall-clock time on the given day.
+func ParseClockTime(day time.Time, value string) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+// ParseEntryDuration accepts Go durations ("1h30m"), clock notation ("1:30")
+// and plain minutes ("90").
+func ParseEntryDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if hours, minutes, ok := strings.Cut(value, ":"); ok {
+		h, errH := strconv.Atoi(hours)
+		m, errM := strconv.Atoi(minutes)
+		if errH == nil && errM == nil && h >= 0 && m >= 0 && m < 60 {
+			return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+		}
+	} else if minutes, err := strconv.Atoi(value); err == nil && minutes >= 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	} else if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	return 0, fmt.Errorf("invalid duration %q, expected e.g. 1h30m, 1:30 or 90", value)
+}
+
+func (s *TimeEntryService) GetMostRecentEntry(ctx context.Context, lookback time.Duration) (*api.TimeEntry, error) {
+	now := time.Now()
+	entries, err := s.apiClient.GetTimeEntries(ctx, now.Add(-lookback), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *api.TimeEntry
+	for i := range entries {
+		if entries[i].TimeInterval.End == nil {
+			continue
+		}
+		if latest == nil || entries[i].TimeInterval.Start.After(latest.TimeInterval.Start) {
+			latest = &entries[i]
+		}
+	}
+
+	return latest, nil
+}
+
+// GetRecentProjectIDs returns the projects used within lookback, most recently
+// used first.
+func (s *TimeEntryService) GetRecentProjectIDs(ctx context.Context, lookback time.Duration) ([]string, error) {
+	now := time.Now()
+	entries, err := s.apiClient.GetTimeEntries(ctx, now.Add(-lookback), now)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TimeInterval.Start.After(entries[j].TimeInterval.Start)
+	})
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.ProjectID == nil || seen[*entry.ProjectID] {
+			continue
+		}
+		seen[*entry.ProjectID] = true
+		ids = append(ids, *entry.ProjectID)
+	}
+
+	return ids, nil
 }
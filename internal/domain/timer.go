@@ -1,10 +1,12 @@
 package domain
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"main/internal/api"
+	"main/internal/journal"
 )
 
 type TimerState struct {
@@ -15,6 +17,7 @@ type TimerState struct {
 	ProjectID    *string
 	TaskID       *string
 	TagIDs       []string
+	Billable     bool
 }
 
 func NewTimerState() *TimerState {
@@ -31,6 +34,7 @@ func (t *TimerState) Start(entry *api.TimeEntry) {
 	t.ProjectID = entry.ProjectID
 	t.TaskID = entry.TaskID
 	t.TagIDs = entry.TagIDs
+	t.Billable = entry.Billable
 }
 
 func (t *TimerState) Stop() {
@@ -41,6 +45,7 @@ func (t *TimerState) Stop() {
 	t.ProjectID = nil
 	t.TaskID = nil
 	t.TagIDs = nil
+	t.Billable = false
 }
 
 func (t *TimerState) GetElapsedDuration() time.Duration {
@@ -82,21 +87,35 @@ func FormatDuration(d time.Duration) string {
 type TimerService struct {
 	apiClient *api.Client
 	state     *TimerState
+	queue     *offlineQueue
 }
 
-func NewTimerService(client *api.Client, state *TimerState) *TimerService {
+// NewTimerService creates the timer service. With a non-nil journal, writes
+// that fail because Clockify is unreachable are queued and applied locally.
+func NewTimerService(client *api.Client, state *TimerState, j *journal.Journal) *TimerService {
 	return &TimerService{
 		apiClient: client,
 		state:     state,
+		queue:     newOfflineQueue(client, j),
 	}
 }
 
-func (s *TimerService) GetCurrentTimer() (*api.TimeEntry, error) {
-	return s.apiClient.GetCurrentTimer()
+func (s *TimerService) GetCurrentTimer(ctx context.Context) (*api.TimeEntry, error) {
+	if entry, known := s.queue.runningTimer(); known {
+		return entry, nil
+	}
+	return s.apiClient.GetCurrentTimer(ctx)
 }
 
-func (s *TimerService) StartTimer(description string, projectID, taskID *string, tagIDs []string) (*api.TimeEntry, error) {
-	entry, err := s.apiClient.StartTimer(description, projectID, taskID, tagIDs)
+func (s *TimerService) StartTimer(ctx context.Context, description string, projectID, taskID *string, tagIDs []string, billable bool) (*api.TimeEntry, error) {
+	if s.queue.shouldQueue("", nil) {
+		return s.startOffline(description, projectID, taskID, tagIDs, billable)
+	}
+
+	entry, err := s.apiClient.StartTimer(ctx, description, projectID, taskID, tagIDs, billable)
+	if s.queue.shouldQueue("", err) {
+		return s.startOffline(description, projectID, taskID, tagIDs, billable)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -104,8 +123,31 @@ func (s *TimerService) StartTimer(description string, projectID, taskID *string,
 	return entry, nil
 }
 
-func (s *TimerService) StopTimer() (*api.TimeEntry, bool, error) {
-	currentEntry, err := s.apiClient.GetCurrentTimer()
+func (s *TimerService) startOffline(description string, projectID, taskID *string, tagIDs []string, billable bool) (*api.TimeEntry, error) {
+	entry, err := s.queue.start(api.TimeEntryRequest{
+		Start:       time.Now().UTC(),
+		Description: description,
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		TagIDs:      tagIDs,
+		Billable:    billable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.state.Start(entry)
+	return entry, nil
+}
+
+func (s *TimerService) StopTimer(ctx context.Context) (*api.TimeEntry, bool, error) {
+	if s.queue.shouldQueue("", nil) {
+		return s.stopOffline()
+	}
+
+	currentEntry, err := s.apiClient.GetCurrentTimer(ctx)
+	if s.queue.shouldQueue("", err) {
+		return s.stopOffline()
+	}
 	if err != nil {
 		return nil, false, err
 	}
@@ -114,7 +156,10 @@ func (s *TimerService) StopTimer() (*api.TimeEntry, bool, error) {
 		return nil, true, nil
 	}
 
-	entry, err := s.apiClient.StopTimer()
+	entry, err := s.apiClient.StopTimer(ctx)
+	if s.queue.shouldQueue("", err) {
+		return s.stopOffline()
+	}
 	if err != nil {
 		return nil, false, err
 	}
@@ -123,8 +168,39 @@ func (s *TimerService) StopTimer() (*api.TimeEntry, bool, error) {
 	return entry, false, nil
 }
 
-func (s *TimerService) UpdateTimeEntry(entryID string, req api.TimeEntryRequest) (*api.TimeEntry, error) {
-	entry, err := s.apiClient.UpdateTimeEntry(entryID, req)
+// stopOffline queues a stop. When the running entry is not known, e.g. in a
+// fresh CLI process, whatever timer is running on the server is stopped on
+// replay and no entry is returned.
+func (s *TimerService) stopOffline() (*api.TimeEntry, bool, error) {
+	running := s.state.CurrentEntry
+	known := running != nil
+	if !known {
+		running, known = s.queue.runningTimer()
+	}
+	if known && running == nil {
+		return nil, true, nil
+	}
+
+	entry, err := s.queue.stop(running, time.Now().UTC())
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.state.Stop()
+	return entry, false, nil
+}
+
+func (s *TimerService) UpdateTimeEntry(ctx context.Context, entryID string, req api.TimeEntryRequest) (*api.TimeEntry, error) {
+	var entry *api.TimeEntry
+	var err error
+	if s.queue.shouldQueue(entryID, nil) {
+		entry, err = s.queue.update(entryID, req, s.serverVersion(entryID))
+	} else {
+		entry, err = s.apiClient.UpdateTimeEntry(ctx, entryID, req)
+		if s.queue.shouldQueue(entryID, err) {
+			entry, err = s.queue.update(entryID, req, s.serverVersion(entryID))
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +215,16 @@ func (s *TimerService) UpdateTimeEntry(entryID string, req api.TimeEntryRequest)
 	return entry, nil
 }
 
+// serverVersion returns the running entry as last seen on the server, used as
+// the base of a queued update.
+func (s *TimerService) serverVersion(entryID string) *api.TimeEntry {
+	if s.state.CurrentEntry == nil || s.state.CurrentEntry.ID != entryID || journal.IsLocalID(entryID) {
+		return nil
+	}
+	base := *s.state.CurrentEntry
+	return &base
+}
+
 func (s *TimerService) GetState() *TimerState {
 	return s.state
 }
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"main/internal/api"
+	"main/internal/cache"
+)
+
+type WorkspaceService struct {
+	apiClient *api.Client
+	cache     *cache.Cache
+}
+
+func NewWorkspaceService(client *api.Client, cache *cache.Cache) *WorkspaceService {
+	return &WorkspaceService{
+		apiClient: client,
+		cache:     cache,
+	}
+}
+
+func (s *WorkspaceService) GetWorkspaces(ctx context.Context) ([]api.Workspace, error) {
+	return s.apiClient.GetWorkspaces(ctx)
+}
+
+// GetUsers lists the members of the current workspace, whose time shows up
+// in reports filtered by user.
+func (s *WorkspaceService) GetUsers(ctx context.Context) ([]api.User, error) {
+	return s.apiClient.GetUsers(ctx)
+}
+
+func (s *WorkspaceService) FindUser(ctx context.Context, nameOrEmailOrID string) (*api.User, error) {
+	users, err := s.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if users[i].ID == nameOrEmailOrID || strings.EqualFold(users[i].Name, nameOrEmailOrID) || strings.EqualFold(users[i].Email, nameOrEmailOrID) {
+			return &users[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("user %q not found", nameOrEmailOrID)
+}
+
+func (s *WorkspaceService) CurrentWorkspaceID() string {
+	return s.apiClient.GetWorkspaceID()
+}
+
+// SwitchWorkspace points the client at another workspace and replaces the
+// cached projects, tasks and tags with that workspace's disk cache, if any.
+func (s *WorkspaceService) SwitchWorkspace(workspaceID string) {
+	s.cache.Save()
+	s.apiClient.SetWorkspace(workspaceID)
+	s.cache.Clear()
+
+	if !s.cache.IsPersistent() {
+		return
+	}
+	path, err := cache.DiskPath(workspaceID)
+	if err != nil {
+		return
+	}
+	s.cache.SetPath(path)
+	s.cache.Load()
+}
+
+// reload fetches a list from the API and caches it with store. When the
+// workspace was switched while the request was in flight, the list belongs
+// to the previous workspace and is returned without replacing the cache.
+func reload[T any](ctx context.Context, client *api.Client, fetch func(context.Context) ([]T, error), store func([]T)) ([]T, error) {
+	workspaceID := client.GetWorkspaceID()
+	items, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.GetWorkspaceID() == workspaceID {
+		store(items)
+	}
+	return items, nil
+}
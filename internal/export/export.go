@@ -0,0 +1,213 @@
+// Package export writes time entries and reports to CSV, JSON or Markdown.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+type Format int
+
+const (
+	CSV Format = iota
+	JSON
+	Markdown
+)
+
+// Formats lists the formats in the order the export dialog cycles through
+// them.
+var Formats = []Format{CSV, JSON, Markdown}
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case Markdown:
+		return "markdown"
+	}
+	return "csv"
+}
+
+func (f Format) Extension() string {
+	switch f {
+	case JSON:
+		return ".json"
+	case Markdown:
+		return ".md"
+	}
+	return ".csv"
+}
+
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "csv":
+		return CSV, nil
+	case "json":
+		return JSON, nil
+	case "md", "markdown":
+		return Markdown, nil
+	}
+	return CSV, fmt.Errorf("unknown export format %q (use csv, json or md)", name)
+}
+
+// Column names a table column; Key is its field name in JSON.
+type Column struct {
+	Header string
+	Key    string
+}
+
+// Table is the exported data. Cells are strings, float64 hours or bools,
+// which JSON keeps as such and CSV and Markdown format as text. Notes are
+// summary lines, such as totals, that only Markdown and JSON carry.
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    [][]any
+	Notes   []string
+}
+
+func Write(w io.Writer, format Format, table Table) error {
+	switch format {
+	case JSON:
+		return writeJSON(w, table)
+	case Markdown:
+		return writeMarkdown(w, table)
+	}
+	return writeCSV(w, table)
+}
+
+// WriteFile writes the table to path. An existing file is only replaced
+// with overwrite; otherwise the error matches os.ErrExist.
+func WriteFile(path string, format Format, table Table, overwrite bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o666)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s: file exists: %w", path, os.ErrExist)
+	}
+	if err != nil {
+		return err
+	}
+	if err := Write(f, format, table); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// FileName suggests a file name such as "clockify-report-2025-01-06.csv".
+func FileName(kind string, first, last time.Time, format Format) string {
+	name := "clockify-" + kind + "-" + first.Format("2006-01-02")
+	if !last.Equal(first) {
+		name += "_" + last.Format("2006-01-02")
+	}
+	return name + format.Extension()
+}
+
+func writeCSV(w io.Writer, table Table) error {
+	writer := csv.NewWriter(w)
+
+	headers := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		headers[i] = column.Header
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = formatCell(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSON(w io.Writer, table Table) error {
+	rows := make([]map[string]any, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		object := make(map[string]any, len(row))
+		for i, cell := range row {
+			object[table.Columns[i].Key] = cell
+		}
+		rows = append(rows, object)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Title string           `json:"title"`
+		Notes []string         `json:"notes,omitempty"`
+		Rows  []map[string]any `json:"rows"`
+	}{table.Title, table.Notes, rows})
+}
+
+func writeMarkdown(w io.Writer, table Table) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", table.Title)
+
+	headers := make([]string, len(table.Columns))
+	separators := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		headers[i] = escapeMarkdown(column.Header)
+		separators[i] = "---"
+		if column.Key == "hours" {
+			separators[i] = "---:"
+		}
+	}
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(separators, " | "))
+
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = escapeMarkdown(formatCell(cell))
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	if len(table.Notes) > 0 {
+		b.WriteString("\n")
+		for _, note := range table.Notes {
+			fmt.Fprintf(&b, "%s  \n", note)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(cell)
+}
+
+// escapeMarkdown keeps cell text from breaking the table.
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/domain"
+)
+
+var (
+	durationColumn = Column{"Duration", "duration"}
+	hoursColumn    = Column{"Hours", "hours"}
+)
+
+// Entries lists time entries one per row, with names resolved; a running
+// entry is exported up to now and without an end.
+func Entries(title string, entries []api.TimeEntry, names *domain.Names) Table {
+	table := Table{
+		Title: title,
+		Columns: []Column{
+			{"Date", "date"},
+			{"Start", "start"},
+			{"End", "end"},
+			durationColumn,
+			hoursColumn,
+			{"Description", "description"},
+			{"Client", "client"},
+			{"Project", "project"},
+			{"Task", "task"},
+			{"Tags", "tags"},
+			{"Billable", "billable"},
+		},
+	}
+
+	var total time.Duration
+	for _, entry := range entries {
+		start := entry.TimeInterval.Start.Local()
+		end := time.Now()
+		endCell := ""
+		if entry.TimeInterval.End != nil {
+			end = entry.TimeInterval.End.Local()
+			endCell = end.Format("15:04")
+		}
+		duration := end.Sub(start)
+		total += duration
+
+		var client, project, task string
+		if entry.ProjectID != nil {
+			project = names.Project(*entry.ProjectID)
+			if _, name, ok := names.Client(*entry.ProjectID); ok {
+				client = name
+			}
+		}
+		if entry.TaskID != nil {
+			task = names.Task(*entry.TaskID)
+		}
+		tags := make([]string, 0, len(entry.TagIDs))
+		for _, tagID := range entry.TagIDs {
+			tags = append(tags, names.Tag(tagID))
+		}
+
+		table.Rows = append(table.Rows, []any{
+			start.Format("2006-01-02"),
+			start.Format("15:04"),
+			endCell,
+			clock(duration),
+			hours(duration),
+			entry.Description,
+			client,
+			project,
+			task,
+			strings.Join(tags, ", "),
+			entry.Billable,
+		})
+	}
+
+	table.Notes = append(table.Notes, "Total: "+domain.FormatDuration(total))
+	return table
+}
+
+func DailyReport(report *domain.DailySummary) Table {
+	table := breakdown(report.Date.Format("Monday, January 2, 2006"), report.ByClient)
+	table.Notes = totalNotes(report.TotalDuration, report.Billing)
+	return table
+}
+
+func WeeklyReport(report *domain.WeeklySummary) Table {
+	last := report.EndDate.AddDate(0, 0, -1)
+	table := breakdown(fmt.Sprintf("Week of %s - %s", report.StartDate.Format("Jan 2"), last.Format("Jan 2, 2006")), report.ByClient)
+	table.Notes = append(dayNotes(report.StartDate, report.EndDate, report.ByDay),
+		totalNotes(report.TotalDuration, report.Billing)...)
+	return table
+}
+
+// RangeReport exports a monthly or custom-range report under title.
+func RangeReport(title string, report *domain.RangeSummary) Table {
+	table := breakdown(title, report.ByClient)
+	table.Notes = append(dayNotes(report.Start, report.End, report.ByDay),
+		totalNotes(report.TotalDuration, report.Billing)...)
+	return table
+}
+
+// GroupedReport exports one row per group, or per subgroup when the report
+// has two levels.
+func GroupedReport(title string, report *domain.GroupedReport) Table {
+	first := capitalize(report.First.String())
+	table := Table{Title: title, Columns: []Column{{first, report.First.String()}}}
+	if report.Second != domain.GroupNone {
+		table.Columns = append(table.Columns, Column{capitalize(report.Second.String()), report.Second.String()})
+	}
+	table.Columns = append(table.Columns, durationColumn, hoursColumn)
+
+	for _, group := range report.Groups {
+		if report.Second == domain.GroupNone {
+			table.Rows = append(table.Rows, []any{group.Name, clock(group.Duration), hours(group.Duration)})
+			continue
+		}
+		for _, child := range group.Children {
+			table.Rows = append(table.Rows, []any{group.Name, child.Name, clock(child.Duration), hours(child.Duration)})
+		}
+	}
+
+	table.Notes = []string{"Total: " + domain.FormatDuration(report.Total)}
+	return table
+}
+
+// breakdown lists one row per task, ordered like the reports view: clients,
+// projects and tasks with the most time first.
+func breakdown(title string, byClient map[string]*domain.ClientSummary) Table {
+	table := Table{
+		Title: title,
+		Columns: []Column{
+			{"Client", "client"},
+			{"Project", "project"},
+			{"Task", "task"},
+			durationColumn,
+			hoursColumn,
+		},
+	}
+
+	clients := make([]*domain.ClientSummary, 0, len(byClient))
+	for _, client := range byClient {
+		clients = append(clients, client)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].TotalDuration > clients[j].TotalDuration })
+
+	for _, client := range clients {
+		projects := make([]*domain.ProjectSummary, 0, len(client.ByProject))
+		for _, project := range client.ByProject {
+			projects = append(projects, project)
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].TotalDuration > projects[j].TotalDuration })
+
+		clientName := client.ClientName
+		if client.ClientID == domain.NoClientID {
+			clientName = ""
+		}
+		for _, project := range projects {
+			tasks := make([]*domain.TaskSummary, 0, len(project.ByTask))
+			for _, task := range project.ByTask {
+				tasks = append(tasks, task)
+			}
+			sort.Slice(tasks, func(i, j int) bool { return tasks[i].Duration > tasks[j].Duration })
+
+			for _, task := range tasks {
+				table.Rows = append(table.Rows, []any{
+					clientName, project.ProjectName, task.TaskName, clock(task.Duration), hours(task.Duration),
+				})
+			}
+		}
+	}
+	return table
+}
+
+func dayNotes(start, end time.Time, byDay map[string]time.Duration) []string {
+	var notes []string
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if duration := byDay[day.Format(domain.DayKeyLayout)]; duration > 0 {
+			notes = append(notes, fmt.Sprintf("%s: %s", day.Format("Mon Jan 2"), domain.FormatDuration(duration)))
+		}
+	}
+	return notes
+}
+
+func totalNotes(total time.Duration, billing domain.Billing) []string {
+	notes := []string{"Total: " + domain.FormatDuration(total)}
+	if billing.BillableDuration > 0 {
+		notes = append(notes, fmt.Sprintf("Billable: %s, non-billable: %s",
+			domain.FormatDuration(billing.BillableDuration), domain.FormatDuration(billing.NonBillableDuration)))
+	}
+	if len(billing.Earnings) > 0 {
+		notes = append(notes, "Earned: "+billing.Earnings.String())
+	}
+	return notes
+}
+
+// clock formats a duration as h:mm:ss, which spreadsheets read as a time.
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// hours is the duration in decimal hours, rounded to the hundredth.
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,161 @@
+// Package idle measures how long the user has been away from the keyboard.
+package idle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source is where the idle time comes from.
+type Source int
+
+const (
+	// Keyboard measures the time since the last key pressed in the TUI, the
+	// fallback when the desktop cannot be asked.
+	Keyboard Source = iota
+	X11
+	Mutter
+)
+
+func (s Source) String() string {
+	switch s {
+	case X11:
+		return "X11"
+	case Mutter:
+		return "GNOME"
+	}
+	return "keyboard"
+}
+
+// Detector reports the idle time of the desktop session when it can, and
+// the time since the last Touch otherwise.
+type Detector struct {
+	mu        sync.Mutex
+	lastInput time.Time
+	source    Source
+	probed    bool
+}
+
+func NewDetector() *Detector {
+	return &Detector{lastInput: time.Now()}
+}
+
+// Touch records input in the TUI.
+func (d *Detector) Touch() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastInput = time.Now()
+}
+
+// Idle returns how long the user has been idle. A desktop source that stops
+// working is given up for the keyboard for the rest of the session.
+//
+// The first call probes the desktop, which can take seconds. The lock is not
+// held meanwhile so that Touch never waits for it; calls made during the
+// probe use the keyboard.
+func (d *Detector) Idle(ctx context.Context) time.Duration {
+	d.mu.Lock()
+	shouldProbe := !d.probed
+	d.probed = true
+	d.mu.Unlock()
+
+	if shouldProbe {
+		source := probe(ctx)
+		d.mu.Lock()
+		d.source = source
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	source := d.source
+	sinceInput := time.Since(d.lastInput)
+	d.mu.Unlock()
+
+	if source == Keyboard {
+		return sinceInput
+	}
+	idle, err := query(ctx, source)
+	if err != nil {
+		d.mu.Lock()
+		d.source = Keyboard
+		d.mu.Unlock()
+		return sinceInput
+	}
+	// Keys typed into the TUI count even if the desktop missed them, e.g.
+	// over SSH.
+	return min(idle, sinceInput)
+}
+
+// Source returns where the idle time comes from; it is Keyboard until the
+// first call to Idle.
+func (d *Detector) Source() Source {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.source
+}
+
+// probe picks the first desktop source that answers. Under Wayland, X11 is
+// not asked: the DISPLAY of XWayland only sees input to X clients, so typing
+// in native Wayland windows would look like being away.
+func probe(ctx context.Context) Source {
+	var candidates []Source
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append(candidates, Mutter)
+	} else if os.Getenv("DISPLAY") != "" {
+		candidates = append(candidates, X11)
+	}
+	for _, source := range candidates {
+		if _, err := query(ctx, source); err == nil {
+			return source
+		}
+	}
+	return Keyboard
+}
+
+const queryTimeout = 2 * time.Second
+
+var mutterIdleTime = regexp.MustCompile(`uint64 (\d+)`)
+
+func query(ctx context.Context, source Source) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	switch source {
+	case X11:
+		out, err := exec.CommandContext(ctx, "xprintidle").Output()
+		if err != nil {
+			return 0, err
+		}
+		return milliseconds(strings.TrimSpace(string(out)))
+
+	case Mutter:
+		out, err := exec.CommandContext(ctx, "gdbus", "call", "--session",
+			"--dest", "org.gnome.Mutter.IdleMonitor",
+			"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+			"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime").Output()
+		if err != nil {
+			return 0, err
+		}
+		match := mutterIdleTime.FindSubmatch(out)
+		if match == nil {
+			return 0, errors.New("unexpected reply from the GNOME idle monitor")
+		}
+		return milliseconds(string(match[1]))
+	}
+	return 0, errors.New("no desktop idle source")
+}
+
+func milliseconds(value string) (time.Duration, error) {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields a CSV column can hold.
+const (
+	fieldDate = iota
+	fieldStart
+	fieldEndDate
+	fieldEnd
+	fieldDuration
+	fieldDescription
+	fieldProject
+	fieldTask
+	fieldTags
+	fieldBillable
+)
+
+// csvColumns maps lowercased headers to fields. It reads the CSV this app
+// exports, and most hand-made timesheets.
+var csvColumns = map[string]int{
+	"date":        fieldDate,
+	"day":         fieldDate,
+	"start":       fieldStart,
+	"start time":  fieldStart,
+	"end":         fieldEnd,
+	"end time":    fieldEnd,
+	"stop":        fieldEnd,
+	"duration":    fieldDuration,
+	"description": fieldDescription,
+	"project":     fieldProject,
+	"task":        fieldTask,
+	"tags":        fieldTags,
+	"tag":         fieldTags,
+	"billable":    fieldBillable,
+}
+
+// togglColumns maps the headers of Toggl Track's detailed CSV export.
+var togglColumns = map[string]int{
+	"start date":  fieldDate,
+	"start time":  fieldStart,
+	"end date":    fieldEndDate,
+	"end time":    fieldEnd,
+	"duration":    fieldDuration,
+	"description": fieldDescription,
+	"project":     fieldProject,
+	"task":        fieldTask,
+	"tags":        fieldTags,
+	"billable":    fieldBillable,
+}
+
+var (
+	dateTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+	}
+	clockLayouts = []string{"15:04:05", "15:04", "3:04:05 PM", "3:04 PM"}
+)
+
+func readCSV(r io.Reader, columns map[string]int) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[int]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := columns[name]; ok {
+			if _, seen := index[field]; !seen {
+				index[field] = i
+			}
+		}
+	}
+	if _, ok := index[fieldStart]; !ok {
+		return nil, errors.New("no start column in the header")
+	}
+	_, hasEnd := index[fieldEnd]
+	_, hasDuration := index[fieldDuration]
+	if !hasEnd && !hasDuration {
+		return nil, errors.New("no end or duration column in the header")
+	}
+
+	var records []Record
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		cell := func(field int) string {
+			if i, ok := index[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		records = append(records, csvRecord(line, cell))
+	}
+	return records, nil
+}
+
+func csvRecord(line int, cell func(int) string) Record {
+	record := Record{
+		Line:        line,
+		Description: cell(fieldDescription),
+		Project:     cell(fieldProject),
+		Task:        cell(fieldTask),
+		Tags:        splitTags(cell(fieldTags)),
+	}
+
+	billable, err := parseBool(cell(fieldBillable))
+	if err != nil {
+		record.Problem = err.Error()
+		return record
+	}
+	record.Billable = billable
+
+	start, err := parseTime(cell(fieldDate), cell(fieldStart), time.Time{})
+	if err != nil {
+		record.Problem = "start: " + err.Error()
+		return record
+	}
+	record.Start = start
+
+	if value := cell(fieldEnd); value != "" {
+		endDate := cell(fieldEndDate)
+		if endDate == "" {
+			endDate = start.Format("2006-01-02")
+		}
+		end, err := parseTime(endDate, value, start)
+		if err != nil {
+			record.Problem = "end: " + err.Error()
+			return record
+		}
+		record.End = end
+		return record
+	}
+
+	if cell(fieldDuration) == "" {
+		record.Problem = "no end time or duration"
+		return record
+	}
+	duration, err := parseDuration(cell(fieldDuration))
+	if err != nil {
+		record.Problem = "duration: " + err.Error()
+		return record
+	}
+	record.End = start.Add(duration)
+	return record
+}
+
+// parseTime reads a date and time, or a full timestamp in value. A time of
+// day earlier than after is taken to be on the next day, for entries that
+// cross midnight.
+func parseTime(date, value string, after time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("missing")
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if date == "" {
+		return time.Time{}, fmt.Errorf("%q has no date and there is no date column", value)
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", date)
+	}
+	for _, layout := range clockLayouts {
+		clock, err := time.Parse(layout, strings.ToUpper(value))
+		if err != nil {
+			continue
+		}
+		t := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.Local)
+		if !after.IsZero() && t.Before(after) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// parseDuration reads h:mm:ss, h:mm or Go durations such as 1h30m.
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, errors.New("missing")
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q (use h:mm:ss or 1h30m)", value)
+	}
+	var d time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q (use h:mm:ss or 1h30m)", value)
+		}
+		d += time.Duration(n) * units[i]
+	}
+	return d, nil
+}
+
+// parseBool returns nil for an empty cell, leaving the choice to the
+// project's default.
+func parseBool(value string) (*bool, error) {
+	var b bool
+	switch strings.ToLower(value) {
+	case "":
+		return nil, nil
+	case "yes", "y", "true", "1":
+		b = true
+	case "no", "n", "false", "0":
+	default:
+		return nil, fmt.Errorf("billable: %q is neither yes nor no", value)
+	}
+	return &b, nil
+}
+
+// splitTags splits a tags cell on commas or semicolons.
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
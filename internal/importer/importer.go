@@ -0,0 +1,112 @@
+// Package importer reads time entries exported by other trackers: plain CSV,
+// Toggl's CSV export and Timewarrior's JSON export.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Format int
+
+const (
+	// Auto picks the format from the file name and contents.
+	Auto Format = iota
+	CSV
+	Toggl
+	Timewarrior
+)
+
+// Formats lists the formats in the order the import dialog cycles through
+// them.
+var Formats = []Format{Auto, CSV, Toggl, Timewarrior}
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "csv"
+	case Toggl:
+		return "toggl"
+	case Timewarrior:
+		return "timewarrior"
+	}
+	return "auto"
+}
+
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return Auto, nil
+	case "csv":
+		return CSV, nil
+	case "toggl":
+		return Toggl, nil
+	case "timewarrior", "timew":
+		return Timewarrior, nil
+	}
+	return Auto, fmt.Errorf("unknown import format %q (use csv, toggl or timewarrior)", name)
+}
+
+// Record is one time entry as read from the source. Names are as written
+// there; mapping them to Clockify IDs is up to the caller. Problem is set
+// when the row could not be fully understood, and End is zero for an entry
+// that was still running when exported.
+type Record struct {
+	Line        int
+	Start       time.Time
+	End         time.Time
+	Description string
+	Project     string
+	Task        string
+	Tags        []string
+	Billable    *bool
+	Problem     string
+}
+
+// ReadFile reads the records of the file at path.
+func ReadFile(path string, format Format) ([]Record, Format, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, format, err
+	}
+	if format == Auto {
+		format = Detect(path, data)
+	}
+	records, err := Read(bytes.NewReader(data), format)
+	return records, format, err
+}
+
+// Detect guesses the format: JSON is Timewarrior's, and CSV with Toggl's
+// "Start date" column is Toggl's.
+func Detect(path string, data []byte) Format {
+	trimmed := bytes.TrimLeft(data, "\ufeff \t\r\n")
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(trimmed, []byte("[")) {
+		return Timewarrior
+	}
+	header, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if bytes.Contains(bytes.ToLower(header), []byte("start date")) {
+		return Toggl
+	}
+	return CSV
+}
+
+func Read(r io.Reader, format Format) ([]Record, error) {
+	switch format {
+	case Timewarrior:
+		return readTimewarrior(r)
+	case Toggl:
+		return readCSV(r, togglColumns)
+	case CSV:
+		return readCSV(r, csvColumns)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(data), Detect("", data))
+}
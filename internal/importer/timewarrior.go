@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const timewarriorLayout = "20060102T150405Z"
+
+type timewarriorInterval struct {
+	ID         int      `json:"id"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
+}
+
+// readTimewarrior reads the output of "timew export". Timewarrior has only
+// tags, so the first tag becomes the project and the others stay tags; the
+// annotation is the description.
+func readTimewarrior(r io.Reader) ([]Record, error) {
+	var intervals []timewarriorInterval
+	if err := json.NewDecoder(r).Decode(&intervals); err != nil {
+		return nil, fmt.Errorf("not a Timewarrior export: %w", err)
+	}
+
+	records := make([]Record, 0, len(intervals))
+	for i, interval := range intervals {
+		record := Record{Line: i + 1, Description: interval.Annotation}
+		if interval.ID > 0 {
+			record.Line = interval.ID
+		}
+		if len(interval.Tags) > 0 {
+			record.Project = interval.Tags[0]
+			record.Tags = interval.Tags[1:]
+		}
+
+		start, err := time.Parse(timewarriorLayout, interval.Start)
+		if err != nil {
+			record.Problem = fmt.Sprintf("start: invalid time %q", interval.Start)
+			records = append(records, record)
+			continue
+		}
+		record.Start = start.Local()
+
+		if interval.End != "" {
+			end, err := time.Parse(timewarriorLayout, interval.End)
+			if err != nil {
+				record.Problem = fmt.Sprintf("end: invalid time %q", interval.End)
+			} else {
+				record.End = end.Local()
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,243 @@
+package journal
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"main/internal/api"
+)
+
+type OpKind string
+
+const (
+	OpStart  OpKind = "start"
+	OpStop   OpKind = "stop"
+	OpCreate OpKind = "create"
+	OpUpdate OpKind = "update"
+	OpDelete OpKind = "delete"
+)
+
+const localIDPrefix = "local-"
+
+// Op is a write that could not be sent to Clockify yet.
+type Op struct {
+	ID          string    `json:"id"`
+	Kind        OpKind    `json:"kind"`
+	WorkspaceID string    `json:"workspaceId"`
+	RecordedAt  time.Time `json:"recordedAt"`
+	// EntryID is the entry the op applies to. Start and create ops carry the
+	// local ID that later ops use until the entry exists on the server.
+	EntryID string                `json:"entryId"`
+	Request *api.TimeEntryRequest `json:"request,omitempty"`
+	End     *time.Time            `json:"end,omitempty"`
+	// Base is the server's version of the entry when the change was made, used
+	// to detect edits made elsewhere in the meantime.
+	Base *api.TimeEntry `json:"base,omitempty"`
+}
+
+// Ack marks an op as done. EntryID is the server ID assigned to the op's
+// local entry, if it created one.
+type Ack struct {
+	OpID    string `json:"opId"`
+	EntryID string `json:"entryId,omitempty"`
+}
+
+type record struct {
+	Op  *Op  `json:"op,omitempty"`
+	Ack *Ack `json:"ack,omitempty"`
+}
+
+// Journal is an append-only JSON Lines file of queued ops and their acks. The
+// file is re-read on every query so several processes can share it, and it
+// is truncated once nothing is pending. Every access holds an advisory lock
+// on a file next to it, so an op appended by another process between the
+// read and the truncate of a compaction is not lost.
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Path returns $XDG_STATE_HOME/clockify-tui/journal.jsonl, falling back to
+// ~/.local/state when XDG_STATE_HOME is not set.
+func Path() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate state directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "clockify-tui", "journal.jsonl"), nil
+}
+
+func Open(path string) *Journal {
+	return &Journal{path: path}
+}
+
+func NewLocalID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return localIDPrefix + hex.EncodeToString(b)
+}
+
+func IsLocalID(id string) bool {
+	return strings.HasPrefix(id, localIDPrefix)
+}
+
+func (j *Journal) Append(op Op) (Op, error) {
+	if op.ID == "" {
+		op.ID = NewLocalID()
+	}
+	if op.RecordedAt.IsZero() {
+		op.RecordedAt = time.Now()
+	}
+
+	unlock, err := j.lock()
+	if err != nil {
+		return op, err
+	}
+	defer unlock()
+	return op, j.write(record{Op: &op})
+}
+
+// Ack marks opID as applied and compacts the journal when nothing else is
+// pending.
+func (j *Journal) Ack(opID, serverEntryID string) error {
+	unlock, err := j.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := j.write(record{Ack: &Ack{OpID: opID, EntryID: serverEntryID}}); err != nil {
+		return err
+	}
+
+	pending, _, err := j.read("")
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return os.Truncate(j.path, 0)
+	}
+	return nil
+}
+
+// Pending returns the unacknowledged ops for a workspace in the order they
+// were recorded, plus the server IDs already assigned to local entry IDs.
+func (j *Journal) Pending(workspaceID string) ([]Op, map[string]string, error) {
+	unlock, err := j.lock()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+	return j.read(workspaceID)
+}
+
+// lock takes the journal's mutex and the advisory lock other processes
+// take, and returns the function releasing both.
+func (j *Journal) lock() (func(), error) {
+	j.mu.Lock()
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		j.mu.Unlock()
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	f, err := os.OpenFile(j.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		j.mu.Unlock()
+		return nil, fmt.Errorf("failed to open journal lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		j.mu.Unlock()
+		return nil, fmt.Errorf("failed to lock journal: %w", err)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+		j.mu.Unlock()
+	}, nil
+}
+
+func (j *Journal) write(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal record: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return f.Sync()
+}
+
+// read parses the journal; an empty workspaceID returns ops of every
+// workspace. Lines that cannot be parsed, such as a write torn by a crash,
+// are skipped.
+func (j *Journal) read(workspaceID string) ([]Op, map[string]string, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var ops []Op
+	acks := make(map[string]Ack)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		switch {
+		case rec.Op != nil:
+			ops = append(ops, *rec.Op)
+		case rec.Ack != nil:
+			acks[rec.Ack.OpID] = *rec.Ack
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var pending []Op
+	serverIDs := make(map[string]string)
+	for _, op := range ops {
+		if workspaceID != "" && op.WorkspaceID != workspaceID {
+			continue
+		}
+		ack, done := acks[op.ID]
+		if !done {
+			pending = append(pending, op)
+			continue
+		}
+		if ack.EntryID != "" && IsLocalID(op.EntryID) {
+			serverIDs[op.EntryID] = ack.EntryID
+		}
+	}
+
+	return pending, serverIDs, nil
+}
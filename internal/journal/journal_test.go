@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func openTemp(t *testing.T) *Journal {
+	t.Helper()
+	return Open(filepath.Join(t.TempDir(), "state", "journal.jsonl"))
+}
+
+func mustAppend(t *testing.T, j *Journal, op Op) Op {
+	t.Helper()
+	op, err := j.Append(op)
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return op
+}
+
+func opIDs(ops []Op) []string {
+	ids := make([]string, len(ops))
+	for i, op := range ops {
+		ids[i] = op.ID
+	}
+	return ids
+}
+
+func TestPendingReplaysOpsInOrder(t *testing.T) {
+	j := openTemp(t)
+
+	start := mustAppend(t, j, Op{Kind: OpStart, WorkspaceID: "ws", EntryID: NewLocalID()})
+	stop := mustAppend(t, j, Op{Kind: OpStop, WorkspaceID: "ws", EntryID: start.EntryID})
+	other := mustAppend(t, j, Op{Kind: OpDelete, WorkspaceID: "other", EntryID: "server-1"})
+
+	if start.ID == "" || start.RecordedAt.IsZero() {
+		t.Fatalf("Append did not fill in ID and RecordedAt: %+v", start)
+	}
+
+	pending, serverIDs, err := j.Pending("ws")
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if got, want := opIDs(pending), []string{start.ID, stop.ID}; !slices.Equal(got, want) {
+		t.Errorf("pending = %v, want %v", got, want)
+	}
+	if len(serverIDs) != 0 {
+		t.Errorf("serverIDs = %v, want none", serverIDs)
+	}
+
+	pending, _, err = j.Pending("other")
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if got, want := opIDs(pending), []string{other.ID}; !slices.Equal(got, want) {
+		t.Errorf("pending of other workspace = %v, want %v", got, want)
+	}
+}
+
+func TestAckMapsLocalIDToServerID(t *testing.T) {
+	j := openTemp(t)
+
+	start := mustAppend(t, j, Op{Kind: OpStart, WorkspaceID: "ws", EntryID: NewLocalID()})
+	stop := mustAppend(t, j, Op{Kind: OpStop, WorkspaceID: "ws", EntryID: start.EntryID})
+
+	if err := j.Ack(start.ID, "server-1"); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+
+	pending, serverIDs, err := j.Pending("ws")
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if got, want := opIDs(pending), []string{stop.ID}; !slices.Equal(got, want) {
+		t.Errorf("pending = %v, want %v", got, want)
+	}
+	if got := serverIDs[start.EntryID]; got != "server-1" {
+		t.Errorf("serverIDs[%s] = %q, want %q", start.EntryID, got, "server-1")
+	}
+}
+
+func TestAckCompactsWhenNothingIsPending(t *testing.T) {
+	j := openTemp(t)
+
+	first := mustAppend(t, j, Op{Kind: OpCreate, WorkspaceID: "ws", EntryID: NewLocalID()})
+	second := mustAppend(t, j, Op{Kind: OpDelete, WorkspaceID: "other", EntryID: "server-1"})
+
+	if err := j.Ack(first.ID, "server-2"); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if info, err := os.Stat(j.path); err != nil || info.Size() == 0 {
+		t.Fatalf("journal compacted while an op of another workspace is pending (err %v)", err)
+	}
+
+	if err := j.Ack(second.ID, ""); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	info, err := os.Stat(j.path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("journal size = %d after the last ack, want 0", info.Size())
+	}
+}
+
+func TestPendingSkipsTornLines(t *testing.T) {
+	j := openTemp(t)
+
+	op := mustAppend(t, j, Op{Kind: OpUpdate, WorkspaceID: "ws", EntryID: "server-1"})
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	f.WriteString(`{"op":{"id":"local-torn","kind":"st` + "\n")
+	f.Close()
+
+	pending, _, err := j.Pending("ws")
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if got, want := opIDs(pending), []string{op.ID}; !slices.Equal(got, want) {
+		t.Errorf("pending = %v, want %v", got, want)
+	}
+}
+
+func TestPendingWithoutJournal(t *testing.T) {
+	j := openTemp(t)
+
+	pending, serverIDs, err := j.Pending("ws")
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 0 || serverIDs == nil {
+		t.Errorf("Pending = %v, %v; want no ops and an empty map", pending, serverIDs)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package journal
+
+import "os"
+
+// Without flock only the in-process mutex guards the journal, so it must not
+// be shared by several processes.
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
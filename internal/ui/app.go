@@ -1,12 +1,19 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"main/internal/api"
 	"main/internal/cache"
 	"main/internal/domain"
+	"main/internal/export"
+	"main/internal/idle"
+	"main/internal/importer"
+	"main/internal/journal"
 	"main/internal/ui/components"
 	"main/internal/ui/theme"
 	"main/internal/ui/views"
@@ -16,21 +23,39 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	undoWindow   = 5 * time.Second
+	syncInterval = 30 * time.Second
+
+	recentProjectsLookback = 14 * 24 * time.Hour
+
+	idleCheckInterval = 10 * time.Second
+)
+
 type App struct {
-	timerService   *domain.TimerService
-	entryService   *domain.TimeEntryService
-	reportService  *domain.ReportService
-	projectService *domain.ProjectService
-	tagService     *domain.TagService
+	timerService     *domain.TimerService
+	entryService     *domain.TimeEntryService
+	reportService    *domain.ReportService
+	projectService   *domain.ProjectService
+	tagService       *domain.TagService
+	clientService    *domain.ClientService
+	syncService      *domain.SyncService
+	workspaceService *domain.WorkspaceService
+	importService    *domain.ImportService
+	cache            *cache.Cache
 
 	currentView ViewType
 	width       int
 	height      int
 
-	timerView   *views.TimerView
-	entriesView *views.EntriesView
-	reportsView *views.ReportsView
-	statusBar   *components.StatusBarComponent
+	timerView      *views.TimerView
+	entriesView    *views.EntriesView
+	reportsView    *views.ReportsView
+	workspacesView *views.WorkspacesView
+	exportView     *views.ExportView
+	importView     *views.ImportView
+	idleView       *views.IdleView
+	statusBar      *components.StatusBarComponent
 
 	projects    []api.Project
 	entries     []api.TimeEntry
@@ -38,43 +63,142 @@ type App struct {
 	projectsMap map[string]string
 	tasksMap    map[string]string
 	tagsMap     map[string]string
+	clientsMap  map[string]string
+	workspaces  []api.Workspace
+
+	// recentProjects lists project IDs most recently used first, to rank
+	// them higher in the selectors.
+	recentProjects []string
+
+	undoEntry *api.TimeEntry
+
+	// workingHours bound the gaps reported between entries in the day view.
+	workingHours domain.WorkingHours
+
+	targetService *domain.TargetService
+	hourTargets   *domain.HourTargets
+	// targetProgress is the last loaded progress, nil until it loads or
+	// when no targets are set.
+	targetProgress *domain.TargetProgress
+
+	pomodoro *domain.Pomodoro
+	// pomodoroPending turns Pomodoro mode on once the timer being picked
+	// in the selector starts.
+	pomodoroPending bool
+
+	// idleDetector measures how long the user has been away; awaySince is
+	// when the current absence began, zero while the user is active.
+	idleDetector *idle.Detector
+	idleTimeout  time.Duration
+	awaySince    time.Time
 
 	showHelp  bool
 	isLoading bool
 	err       error
 
-	keys KeyMap
+	keys     KeyMap
+	requests *requestTracker
+}
+
+// AppOption configures optional behavior of the App.
+type AppOption func(*App)
+
+// WithIdleTimeout makes the App ask what to do with the time a timer kept
+// running while the user was away for longer than timeout. Zero disables
+// idle detection.
+func WithIdleTimeout(timeout time.Duration) AppOption {
+	return func(a *App) {
+		a.idleTimeout = timeout
+	}
+}
+
+// WithHourTargets shows progress toward the expected hours and the overtime
+// balance in the timer and reports.
+func WithHourTargets(targets *domain.HourTargets) AppOption {
+	return func(a *App) {
+		a.hourTargets = targets
+	}
+}
+
+// WithWorkingHours sets the part of the day in which untracked time is
+// reported as a gap in the Entries view.
+func WithWorkingHours(hours domain.WorkingHours) AppOption {
+	return func(a *App) {
+		a.workingHours = hours
+	}
+}
+
+// WithPomodoro sets the interval lengths of Pomodoro mode.
+func WithPomodoro(settings domain.PomodoroSettings) AppOption {
+	return func(a *App) {
+		a.pomodoro.Settings = settings
+	}
 }
 
-func NewApp(client *api.Client) *App {
-	cacheInstance := cache.NewCache(5 * time.Minute)
+func NewApp(client *api.Client, cacheInstance *cache.Cache, j *journal.Journal, opts ...AppOption) *App {
 	timerState := domain.NewTimerState()
-	timerService := domain.NewTimerService(client, timerState)
-
-	return &App{
-		timerService:   timerService,
-		entryService:   domain.NewTimeEntryService(client),
-		reportService:  domain.NewReportService(client),
-		projectService: domain.NewProjectService(client, cacheInstance),
-		tagService:     domain.NewTagService(client, cacheInstance),
-		currentView:    TimerView,
-		timerView:      views.NewTimerView(timerState),
-		entriesView:    views.NewEntriesView(),
-		reportsView:    views.NewReportsView(),
-		statusBar:      components.NewStatusBar(),
-		projectsMap:    make(map[string]string),
-		tasksMap:       make(map[string]string),
-		tagsMap:        make(map[string]string),
-		keys:           DefaultKeyMap(),
+	timerService := domain.NewTimerService(client, timerState, j)
+	entryService := domain.NewTimeEntryService(client, j)
+	projectService := domain.NewProjectService(client, cacheInstance)
+	tagService := domain.NewTagService(client, cacheInstance)
+	pomodoro := domain.NewPomodoro(domain.DefaultPomodoroSettings())
+
+	app := &App{
+		timerService:     timerService,
+		entryService:     entryService,
+		syncService:      domain.NewSyncService(client, j),
+		reportService:    domain.NewReportService(client),
+		projectService:   projectService,
+		tagService:       tagService,
+		clientService:    domain.NewClientService(client, cacheInstance),
+		workspaceService: domain.NewWorkspaceService(client, cacheInstance),
+		importService:    domain.NewImportService(projectService, tagService, entryService),
+		cache:            cacheInstance,
+		currentView:      TimerView,
+		timerView:        views.NewTimerView(timerState, pomodoro),
+		entriesView:      views.NewEntriesView(timerState),
+		reportsView:      views.NewReportsView(),
+		workspacesView:   views.NewWorkspacesView(),
+		exportView:       views.NewExportView(),
+		importView:       views.NewImportView(),
+		idleView:         views.NewIdleView(),
+		statusBar:        components.NewStatusBar(),
+		projectsMap:      make(map[string]string),
+		tasksMap:         make(map[string]string),
+		tagsMap:          make(map[string]string),
+		clientsMap:       make(map[string]string),
+		pomodoro:         pomodoro,
+		workingHours:     domain.DefaultWorkingHours(),
+		idleDetector:     idle.NewDetector(),
+		keys:             DefaultKeyMap(),
+		requests:         newRequestTracker(),
 	}
+	for _, opt := range opts {
+		opt(app)
+	}
+	app.targetService = domain.NewTargetService(entryService, app.reportService, app.hourTargets)
+	app.reportsView.SetTargets(app.hourTargets)
+	app.entriesView.SetWorkingHours(app.workingHours)
+	return app
 }
 
 func (m App) Init() tea.Cmd {
+	m.statusBar.SetPendingSync(m.syncService.PendingCount())
 	return tea.Batch(
 		tickCmd(),
+		syncTickCmd(),
+		m.idleTickCmd(),
+		m.syncJournal(),
 		m.loadCurrentTimer,
-		m.loadProjects,
-		m.loadTags,
+		m.loadWorkspaces,
+		m.loadRecentProjects,
+		m.loadTargetProgress(),
+		// Show whatever the disk cache holds before the background refresh
+		// replaces it.
+		tea.Sequence(
+			m.loadCachedData(),
+			tea.Batch(m.loadProjects, m.loadTags, m.loadClients),
+		),
 	)
 }
 
@@ -85,15 +209,49 @@ func (m App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
 	case TickMsg:
-		return m, tickCmd()
+		return m.handleTick(time.Time(msg))
+	case SyncTickMsg:
+		return m, tea.Batch(m.syncJournal(), syncTickCmd())
+	case SyncCompletedMsg:
+		return m.handleSyncCompleted(msg)
+	case PomodoroIntervalMsg:
+		return m.handlePomodoroInterval(msg)
+	case TargetProgressLoadedMsg:
+		if msg.WorkspaceID == m.workspaceService.CurrentWorkspaceID() {
+			m.targetProgress = msg.Progress
+			m.timerView.SetTargetProgress(msg.Progress)
+			m.reportsView.SetTargetProgress(msg.Progress)
+		}
+		return m, nil
+	case IdleTickMsg:
+		return m, tea.Batch(m.checkIdle(), m.idleTickCmd())
+	case IdleCheckedMsg:
+		return m.handleIdleChecked(msg)
+	case IdleResolvedMsg:
+		return m.handleIdleResolved(msg)
 	case TimerStartedMsg, TimerStoppedMsg, TimerAlreadyStoppedMsg, TimerDescriptionUpdatedMsg:
 		return m.handleTimerMsg(msg)
-	case ProjectsLoadedMsg, TasksLoadedMsg, TagsLoadedMsg, TimeEntriesLoadedMsg:
+	case ProjectsLoadedMsg, TasksLoadedMsg, TagsLoadedMsg, ClientsLoadedMsg, UsersLoadedMsg, TimeEntriesLoadedMsg:
 		return m.handleDataLoadedMsg(msg)
-	case DailyReportLoadedMsg, WeeklyReportLoadedMsg:
+	case DailyReportLoadedMsg, WeeklyReportLoadedMsg, RangeReportLoadedMsg, GroupedReportLoadedMsg:
 		return m.handleReportMsg(msg)
 	case DescriptionSuggestionsLoadedMsg:
 		return m.handleDescriptionSuggestionsMsg(msg)
+	case RecentProjectsLoadedMsg:
+		if msg.WorkspaceID == m.workspaceService.CurrentWorkspaceID() {
+			m.setRecentProjects(msg.ProjectIDs)
+		}
+		return m, nil
+	case WorkspacesLoadedMsg:
+		m.workspaces = msg.Workspaces
+		m.workspacesView.SetWorkspaces(msg.Workspaces, m.workspaceService.CurrentWorkspaceID())
+		return m, nil
+	case TimeEntryCreatedMsg, TimeEntryUpdatedMsg, TimeEntryDeletedMsg, TimeEntryRestoredMsg, UndoExpiredMsg:
+		return m.handleEntryMsg(msg)
+	case ExportedMsg:
+		return m.handleExported(msg)
+	case ImportPlannedMsg, ImportCommittedMsg:
+		return m.handleImportMsg(msg)
 	case ErrorMsg:
 		return m.handleErrorMsg(msg)
 	}
@@ -108,14 +266,39 @@ func (m App) handleWindowSizeMsg(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.timerView.SetSize(m.width, m.height)
 	m.entriesView.SetSize(m.width, m.height)
 	m.reportsView.SetSize(m.width, m.height)
+	m.workspacesView.SetSize(m.width, m.height)
+	m.exportView.SetSize(m.width, m.height)
+	m.importView.SetSize(m.width, m.height)
+	m.idleView.SetSize(m.width, m.height)
 	return m, nil
 }
 
 func (m App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.idleDetector.Touch()
+	if m.idleView.IsShowing() {
+		return m.handleIdleKeys(msg)
+	}
+	if !m.awaySince.IsZero() && m.promptIdle(time.Now()) {
+		// The key only tells that the user is back; don't act on it.
+		return m, nil
+	}
+
 	if m.showHelp {
 		return m.handleHelpKeys(msg)
 	}
 
+	if m.workspacesView.IsShowing() {
+		return m.handleWorkspaceKeys(msg)
+	}
+
+	if m.exportView.IsShowing() {
+		return m.handleExportKeys(msg)
+	}
+
+	if m.importView.IsShowing() {
+		return m.handleImportKeys(msg)
+	}
+
 	if m.currentView == TimerView {
 		if m.timerView.IsShowingSelector() {
 			return m.handleSelectorKeys(msg)
@@ -125,6 +308,25 @@ func (m App) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.currentView == EntriesView {
+		if m.entriesView.IsConfirmingDelete() {
+			return m.handleDeleteConfirmKeys(msg)
+		}
+		if m.entriesView.IsShowingSelector() {
+			return m.handleEntrySelectorKeys(msg)
+		}
+		if m.entriesView.IsShowingForm() {
+			return m.handleEntryFormKeys(msg)
+		}
+	}
+
+	if m.currentView == ReportsView && m.reportsView.IsShowingRangePicker() {
+		return m.handleRangePickerKeys(msg)
+	}
+	if m.currentView == ReportsView && m.reportsView.IsShowingFilter() {
+		return m.handleReportFilterKeys(msg)
+	}
+
 	return m.handleGlobalKeys(msg)
 }
 
@@ -143,6 +345,7 @@ func (m App) handleGlobalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Quit):
+		m.requests.cancelAll()
 		return m, tea.Quit
 
 	case key.Matches(msg, m.keys.SwitchToTimer):
@@ -154,6 +357,10 @@ func (m App) handleGlobalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.SwitchToReports):
 		return m.handleSwitchToReports()
 
+	case key.Matches(msg, m.keys.SwitchWorkspace):
+		m.workspacesView.Show()
+		return m, m.loadWorkspaces
+
 	case key.Matches(msg, m.keys.Refresh):
 		return m, m.refresh()
 
@@ -172,38 +379,381 @@ func (m App) handleGlobalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.ToggleView):
 		return m.handleToggleView()
 
+	case key.Matches(msg, m.keys.CustomRange):
+		if m.currentView == ReportsView {
+			m.reportsView.ShowRangePicker()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Group):
+		if m.currentView == ReportsView {
+			m.reportsView.CycleGrouping()
+			return m, m.loadGroupedReport()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Subgroup):
+		if m.currentView == ReportsView {
+			m.reportsView.CycleSubgrouping()
+			return m, m.loadGroupedReport()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.ReportFilter):
+		if m.currentView == ReportsView {
+			m.reportsView.ShowFilter()
+			return m, m.loadUsers
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Export):
+		return m.handleExport()
+
+	case key.Matches(msg, m.keys.Import):
+		if m.currentView == EntriesView {
+			m.importView.Show()
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.StartTimer):
 		return m.handleStartTimer()
 
 	case key.Matches(msg, m.keys.StopTimer):
 		return m.handleStopTimer()
 
+	case key.Matches(msg, m.keys.Pomodoro):
+		return m.handlePomodoro()
+
 	case key.Matches(msg, m.keys.SelectProject):
 		return m.handleSelectProject()
 
 	case key.Matches(msg, m.keys.EditDescription):
 		return m.handleEditDescription()
+
+	case key.Matches(msg, m.keys.EditEntry):
+		return m.handleEditEntry()
+
+	case key.Matches(msg, m.keys.NewEntry):
+		return m.handleNewEntry()
+
+	case key.Matches(msg, m.keys.DeleteEntry):
+		return m.handleDeleteEntry()
+
+	case key.Matches(msg, m.keys.TrimOverlap):
+		return m.handleTrimOverlap()
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.currentView == EntriesView && m.entriesView.GetSelectedGap() != nil {
+			return m.handleNewEntry()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Undo):
+		return m.handleUndo()
 	}
 
 	return m, nil
 }
 
 func (m App) handleSwitchToTimer() (tea.Model, tea.Cmd) {
+	m.leaveView()
 	m.currentView = TimerView
 	m.statusBar.SetInfo("Switched to Timer view")
-	return m, nil
+	return m, m.loadTargetProgress()
 }
 
 func (m App) handleSwitchToEntries() (tea.Model, tea.Cmd) {
+	m.leaveView()
 	m.currentView = EntriesView
 	m.statusBar.SetInfo("Switched to Entries view")
 	return m, m.loadEntries()
 }
 
 func (m App) handleSwitchToReports() (tea.Model, tea.Cmd) {
+	m.leaveView()
 	m.currentView = ReportsView
 	m.statusBar.SetInfo("Switched to Reports view")
-	return m, m.loadReports()
+	return m, tea.Batch(m.loadReports(), m.loadTargetProgress())
+}
+
+func (m App) handleWorkspaceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := m.workspacesView.GetPicker()
+
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Quit):
+		m.workspacesView.Hide()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		picker.MoveUp()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		picker.MoveDown()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.workspacesView.Hide()
+		selected := picker.GetSelectedWorkspace()
+		if selected == nil || selected.ID == m.workspaceService.CurrentWorkspaceID() {
+			return m, nil
+		}
+		return m.switchWorkspace(*selected)
+	}
+
+	return m, nil
+}
+
+// handleExport opens the export dialog for the entries or report being
+// shown.
+func (m App) handleExport() (tea.Model, tea.Cmd) {
+	switch m.currentView {
+	case EntriesView:
+		first := domain.DayStart(m.entriesView.GetSelectedDate())
+		last := first
+		if m.entriesView.GetViewMode() == components.ViewThisWeek {
+			first = domain.WeekStart(time.Now())
+			last = first.AddDate(0, 0, 6)
+		}
+		m.exportView.Show("Entries", "entries", first, last)
+
+	case ReportsView:
+		if _, ok := m.reportsView.ExportTable(); !ok {
+			m.statusBar.SetInfo("Report still loading - try again in a moment")
+			return m, nil
+		}
+		first, last := m.reportsView.GetPeriod()
+		m.exportView.Show("Report", "report", first, last)
+	}
+	return m, nil
+}
+
+func (m App) handleExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	dialog := m.exportView.GetDialog()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.exportView.Hide()
+		return m, nil
+	case tea.KeyEnter:
+		return m, m.exportTable(dialog.Path(), dialog.Format(), dialog.IsConfirmingOverwrite())
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		dialog.ToggleField()
+		return m, nil
+	case tea.KeyLeft:
+		dialog.CycleFormat(-1)
+		return m, nil
+	case tea.KeyRight:
+		dialog.CycleFormat(1)
+		return m, nil
+	case tea.KeyBackspace:
+		if dialog.IsPathFocused() {
+			dialog.DeleteChar()
+		}
+		return m, nil
+	case tea.KeyRunes, tea.KeySpace:
+		if dialog.IsPathFocused() {
+			for _, r := range msg.Runes {
+				dialog.AddChar(r)
+			}
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// exportTable writes what the current view shows, as it is on screen when
+// the export is confirmed.
+func (m *App) exportTable(path string, format export.Format, overwrite bool) tea.Cmd {
+	var table export.Table
+	switch m.currentView {
+	case EntriesView:
+		title := "Time entries for " + m.entriesView.GetSelectedDate().Format("Monday, January 2, 2006")
+		if m.entriesView.GetViewMode() == components.ViewThisWeek {
+			title = "Time entries for this week"
+		}
+		table = export.Entries(title, m.entries, m.names())
+	case ReportsView:
+		table, _ = m.reportsView.ExportTable()
+		if summary := m.reportsView.GetFilter().Summary(); summary != "" {
+			table.Notes = append(table.Notes, "Filter: "+summary)
+		}
+	}
+
+	return func() tea.Msg {
+		if path == "" {
+			return ExportedMsg{Err: errors.New("enter a file name")}
+		}
+		err := export.WriteFile(path, format, table, overwrite)
+		return ExportedMsg{Path: path, Rows: len(table.Rows), Err: err}
+	}
+}
+
+func (m App) handleExported(msg ExportedMsg) (tea.Model, tea.Cmd) {
+	if errors.Is(msg.Err, os.ErrExist) {
+		m.exportView.GetDialog().RequestOverwrite()
+		return m, nil
+	}
+	if msg.Err != nil {
+		m.exportView.GetDialog().SetError(msg.Err)
+		return m, nil
+	}
+	m.exportView.Hide()
+	m.statusBar.SetSuccess(fmt.Sprintf("Exported %d rows to %s", msg.Rows, msg.Path))
+	return m, nil
+}
+
+// handleImportKeys drives the import screen: the setup form, then the
+// preview, where enter imports the ready entries.
+func (m App) handleImportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	dialog := m.importView.GetImporter()
+	if dialog.IsBusy() {
+		return m, nil
+	}
+
+	if dialog.IsPreviewing() {
+		switch msg.Type {
+		case tea.KeyEsc:
+			dialog.Back()
+		case tea.KeyUp:
+			dialog.MoveUp()
+		case tea.KeyDown:
+			dialog.MoveDown()
+		case tea.KeyEnter:
+			if plan := dialog.GetPlan(); plan.Count(domain.ImportReady) > 0 {
+				dialog.SetBusy("Importing...")
+				return m, m.commitImport(plan)
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.importView.Hide()
+	case tea.KeyEnter:
+		if dialog.Path() == "" {
+			dialog.SetError(errors.New("enter the file to import"))
+			return m, nil
+		}
+		dialog.SetBusy("Reading file...")
+		return m, m.planImport(dialog.Path(), dialog.Format(), dialog.Options())
+	case tea.KeyUp, tea.KeyShiftTab:
+		dialog.MoveUp()
+	case tea.KeyDown, tea.KeyTab:
+		dialog.MoveDown()
+	case tea.KeyLeft:
+		dialog.Cycle(-1)
+	case tea.KeyRight:
+		dialog.Cycle(1)
+	case tea.KeyBackspace:
+		if dialog.IsPathFocused() {
+			dialog.DeleteChar()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		if !dialog.IsPathFocused() {
+			dialog.Cycle(1)
+			return m, nil
+		}
+		for _, r := range msg.Runes {
+			dialog.AddChar(r)
+		}
+	}
+	return m, nil
+}
+
+func (m App) handleImportMsg(msg any) (tea.Model, tea.Cmd) {
+	dialog := m.importView.GetImporter()
+
+	switch msg := msg.(type) {
+	case ImportPlannedMsg:
+		if msg.Err != nil {
+			dialog.SetError(msg.Err)
+			return m, nil
+		}
+		dialog.SetPlan(msg.Plan, msg.Format)
+
+	case ImportCommittedMsg:
+		if msg.Err != nil {
+			dialog.SetError(fmt.Errorf("imported %d entries, then failed: %w", msg.Created, msg.Err))
+			return m, tea.Batch(m.loadEntries(), m.loadProjects, m.loadTags)
+		}
+		m.importView.Hide()
+		m.setWriteSuccess(fmt.Sprintf("Imported time entries: %d", msg.Created))
+		return m, tea.Batch(m.loadEntries(), m.loadProjects, m.loadTags)
+	}
+	return m, nil
+}
+
+// switchWorkspace drops everything loaded for the previous workspace and
+// reloads projects, tags, the timer and the current view for the new one.
+func (m App) switchWorkspace(workspace api.Workspace) (tea.Model, tea.Cmd) {
+	m.requests.cancelKind(requestEntries, requestReports, requestSuggestions, requestTasks)
+	m.workspaceService.SwitchWorkspace(workspace.ID)
+	m.workspacesView.SetWorkspaces(m.workspaces, workspace.ID)
+
+	m.timerService.GetState().Stop()
+	m.timerView.GetTimerComponent().ClearEditState()
+	m.pomodoro.Stop()
+	m.pomodoroPending = false
+	m.awaySince = time.Time{}
+	m.idleView.Hide()
+	m.undoEntry = nil
+	m.statusBar.ClearUndo()
+
+	m.projects = nil
+	m.entries = nil
+	m.tags = nil
+	m.projectsMap = make(map[string]string)
+	m.tasksMap = make(map[string]string)
+	m.tagsMap = make(map[string]string)
+	m.clientsMap = make(map[string]string)
+	m.timerView.GetProjectSelector().SetClients(m.clientsMap)
+	m.entriesView.GetProjectSelector().SetClients(m.clientsMap)
+	m.timerView.SetProjects(nil)
+	m.timerView.SetProjectMap(m.projectsMap)
+	m.timerView.SetTagMap(m.tagsMap)
+	m.timerView.GetProjectSelector().SetTags(nil)
+	m.entriesView.SetEntries(nil)
+	m.entriesView.SetProjectList(nil)
+	m.entriesView.SetProjects(m.projectsMap)
+	m.entriesView.SetTasks(m.tasksMap)
+	m.entriesView.SetTags(m.tagsMap)
+
+	m.statusBar.SetPendingSync(m.syncService.PendingCount())
+	m.statusBar.SetInfo(fmt.Sprintf("Switched to workspace %s", workspace.Name))
+
+	m.setRecentProjects(nil)
+
+	cmds := []tea.Cmd{
+		m.syncJournal(),
+		m.loadCurrentTimer,
+		m.loadRecentProjects,
+		m.loadTargetProgress(),
+		tea.Sequence(
+			m.loadCachedData(),
+			tea.Batch(m.loadProjects, m.loadTags, m.loadClients),
+		),
+	}
+	switch m.currentView {
+	case EntriesView:
+		cmds = append(cmds, m.loadEntries())
+	case ReportsView:
+		cmds = append(cmds, m.loadReports())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// leaveView cancels loads that only the current view is waiting on.
+func (m *App) leaveView() {
+	switch m.currentView {
+	case TimerView:
+		m.requests.cancelKind(requestSuggestions, requestTasks)
+	case EntriesView:
+		m.requests.cancelKind(requestEntries, requestTasks)
+	case ReportsView:
+		m.requests.cancelKind(requestReports, requestGroupedReport)
+	}
 }
 
 func (m App) handleLeftKey() (tea.Model, tea.Cmd) {
@@ -231,18 +781,122 @@ func (m App) handleRightKey() (tea.Model, tea.Cmd) {
 }
 
 func (m App) handleUpKey() (tea.Model, tea.Cmd) {
-	if m.currentView == EntriesView {
+	switch m.currentView {
+	case EntriesView:
 		m.entriesView.MoveUp()
-		return m, nil
+	case ReportsView:
+		m.reportsView.ScrollUp()
 	}
 	return m, nil
 }
 
 func (m App) handleDownKey() (tea.Model, tea.Cmd) {
-	if m.currentView == EntriesView {
+	switch m.currentView {
+	case EntriesView:
 		m.entriesView.MoveDown()
+	case ReportsView:
+		m.reportsView.ScrollDown()
+	}
+	return m, nil
+}
+
+func (m App) handleRangePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := m.reportsView.GetRangePicker()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.reportsView.HideRangePicker()
+		return m, nil
+
+	case tea.KeyEnter:
+		first, last, err := picker.Build()
+		if err != nil {
+			picker.SetError(err)
+			return m, nil
+		}
+		m.reportsView.HideRangePicker()
+		m.reportsView.SetRange(first, last)
+		return m, m.loadReports()
+
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		picker.ToggleField()
+		return m, nil
+
+	case tea.KeyBackspace:
+		picker.DeleteChar()
+		return m, nil
+
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if !picker.ApplyPreset(r) {
+				picker.AddChar(r)
+			}
+		}
 		return m, nil
 	}
+
+	return m, nil
+}
+
+func (m App) handleReportFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filter := m.reportsView.GetFilter()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		filter.Cancel()
+		m.reportsView.HideFilter()
+		return m, nil
+
+	case tea.KeyEnter:
+		m.reportsView.HideFilter()
+		if !filter.Changed() {
+			return m, nil
+		}
+		return m, m.loadReports()
+
+	case tea.KeyUp, tea.KeyShiftTab:
+		filter.MoveUp()
+		return m, nil
+
+	case tea.KeyDown, tea.KeyTab:
+		filter.MoveDown()
+		return m, nil
+
+	case tea.KeyLeft:
+		return m.cycleReportFilter(-1)
+
+	case tea.KeyRight:
+		return m.cycleReportFilter(1)
+
+	case tea.KeyBackspace:
+		return m.editReportFilter(filter.DeleteChar)
+
+	case tea.KeyRunes, tea.KeySpace:
+		return m.editReportFilter(func() {
+			for _, r := range msg.Runes {
+				filter.AddChar(r)
+			}
+		})
+	}
+
+	return m, nil
+}
+
+func (m App) cycleReportFilter(direction int) (tea.Model, tea.Cmd) {
+	return m.editReportFilter(func() {
+		m.reportsView.GetFilter().Cycle(direction)
+	})
+}
+
+// editReportFilter applies an edit to the report filter and, when it picks
+// another project, loads that project's tasks for the task row.
+func (m App) editReportFilter(edit func()) (tea.Model, tea.Cmd) {
+	filter := m.reportsView.GetFilter()
+	projectID := filter.SelectedProjectID()
+	edit()
+	if selected := filter.SelectedProjectID(); selected != projectID && selected != "" {
+		return m, m.loadTasksForProject(selected)
+	}
 	return m, nil
 }
 
@@ -259,6 +913,7 @@ func (m App) handleToggleView() (tea.Model, tea.Cmd) {
 }
 
 func (m App) handleStartTimer() (tea.Model, tea.Cmd) {
+	m.pomodoroPending = false
 	if m.currentView == TimerView && !m.timerService.GetState().IsRunning {
 		m.timerView.ShowProjectSelector()
 		return m, nil
@@ -271,12 +926,93 @@ func (m App) handleStartTimer() (tea.Model, tea.Cmd) {
 
 func (m App) handleStopTimer() (tea.Model, tea.Cmd) {
 	if m.currentView == TimerView && m.timerService.GetState().IsRunning {
+		m.pomodoro.Stop()
 		return m, m.stopTimer
 	}
 	return m, nil
 }
 
+// handlePomodoro turns Pomodoro mode off, or on for the running timer. With
+// no timer running, it starts with the timer picked in the selector.
+func (m App) handlePomodoro() (tea.Model, tea.Cmd) {
+	if m.currentView != TimerView {
+		return m, nil
+	}
+
+	state := m.timerService.GetState()
+	switch {
+	case m.pomodoro.Active:
+		m.pomodoro.Stop()
+		m.statusBar.SetInfo("Pomodoro mode off")
+	case state.IsRunning:
+		m.startPomodoro(state.CurrentEntry)
+	default:
+		m.pomodoroPending = true
+		m.timerView.ShowProjectSelector()
+	}
+	return m, nil
+}
+
+func (m *App) startPomodoro(entry *api.TimeEntry) {
+	m.pomodoroPending = false
+	m.pomodoro.Start(entry, time.Now())
+	m.statusBar.SetInfo(fmt.Sprintf("Pomodoro mode on - work for %s", domain.FormatDuration(m.pomodoro.Settings.Work)))
+}
+
+// handleTick moves Pomodoro mode to the next interval when the current one
+// is over, ringing the terminal bell.
+func (m App) handleTick(now time.Time) (tea.Model, tea.Cmd) {
+	cmds := []tea.Cmd{tickCmd()}
+	m.entriesView.Tick(now)
+	if m.targetProgress != nil && m.targetProgress.IsStale(now) {
+		// A new day started; today's progress begins at zero.
+		m.targetProgress = nil
+		cmds = append(cmds, m.loadTargetProgress())
+	}
+	if !m.pomodoro.Advance(now) {
+		return m, tea.Batch(cmds...)
+	}
+
+	cmds = append(cmds, bellCmd)
+	if m.pomodoro.Phase == domain.PomodoroWork {
+		m.statusBar.SetInfo("Break over - starting the next work interval")
+		cmds = append(cmds, m.startPomodoroInterval(m.pomodoro.Template))
+		return m, tea.Batch(cmds...)
+	}
+
+	breakInfo := fmt.Sprintf("%s - %s", m.pomodoro.Phase, domain.FormatDuration(m.pomodoro.Length()))
+	if m.pomodoro.Settings.StopOnBreak && m.timerService.GetState().IsRunning {
+		m.statusBar.SetInfo(breakInfo + ", timer stopped")
+		cmds = append(cmds, m.stopTimerForBreak(m.pomodoro.Phase))
+	} else {
+		m.statusBar.SetInfo(breakInfo)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m App) handlePomodoroInterval(msg PomodoroIntervalMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		if errors.Is(msg.Err, context.Canceled) {
+			return m, nil
+		}
+		m.pomodoro.Stop()
+		m.statusBar.SetMessage("Error: "+describeError(msg.Err)+" - Pomodoro mode off", components.StatusError)
+		return m, m.loadCurrentTimer
+	}
+
+	if msg.Phase == domain.PomodoroWork {
+		m.timerService.GetState().Start(msg.Entry)
+		m.setWriteSuccess(fmt.Sprintf("Pomodoro %d started", m.pomodoro.Cycle()))
+		return m, nil
+	}
+
+	m.timerService.GetState().Stop()
+	m.timerView.GetTimerComponent().ClearEditState()
+	return m, m.loadTargetProgress()
+}
+
 func (m App) handleSelectProject() (tea.Model, tea.Cmd) {
+	m.pomodoroPending = false
 	if m.currentView == TimerView {
 		m.timerView.ShowProjectSelector()
 		return m, nil
@@ -296,29 +1032,189 @@ func (m App) handleEditDescription() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m App) handleEditEntry() (tea.Model, tea.Cmd) {
+	if m.currentView != EntriesView {
+		return m, nil
+	}
+
+	selectedEntry := m.entriesView.GetSelectedEntry()
+	if selectedEntry == nil {
+		m.statusBar.SetError(fmt.Errorf("no entry selected"))
+		return m, nil
+	}
+
+	m.entriesView.ShowEditForm(selectedEntry)
+	return m, nil
+}
+
+func (m App) handleNewEntry() (tea.Model, tea.Cmd) {
+	if m.currentView != EntriesView {
+		return m, nil
+	}
+
+	if gap := m.entriesView.GetSelectedGap(); gap != nil {
+		m.entriesView.ShowGapForm(gap)
+		return m, nil
+	}
+	m.entriesView.ShowNewForm(m.entriesView.GetSelectedDate())
+	return m, nil
+}
+
+// handleTrimOverlap shortens the selected entry so it no longer overlaps the
+// first entry it overlaps with.
+func (m App) handleTrimOverlap() (tea.Model, tea.Cmd) {
+	if m.currentView != EntriesView {
+		return m, nil
+	}
+
+	selectedEntry := m.entriesView.GetSelectedEntry()
+	if selectedEntry == nil {
+		m.statusBar.SetError(fmt.Errorf("no entry selected"))
+		return m, nil
+	}
+	overlaps := m.entriesView.GetAnalysis().OverlapsOf(selectedEntry.ID)
+	if len(overlaps) == 0 {
+		m.statusBar.SetInfo("The selected entry doesn't overlap another")
+		return m, nil
+	}
+
+	req, err := domain.TrimOverlap(overlaps[0], selectedEntry.ID)
+	if err != nil {
+		m.statusBar.SetError(err)
+		return m, nil
+	}
+	m.statusBar.SetInfo("Trimming entry...")
+	return m, m.updateTimeEntry(selectedEntry.ID, req)
+}
+
+func (m App) handleDeleteEntry() (tea.Model, tea.Cmd) {
+	if m.currentView != EntriesView {
+		return m, nil
+	}
+
+	if m.entriesView.GetSelectedEntry() == nil {
+		m.statusBar.SetError(fmt.Errorf("no entry selected"))
+		return m, nil
+	}
+
+	m.entriesView.RequestDeleteConfirmation()
+	return m, nil
+}
+
+func (m App) handleDeleteConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.entriesView.CancelDeleteConfirmation()
+		selectedEntry := m.entriesView.GetSelectedEntry()
+		if selectedEntry == nil {
+			return m, nil
+		}
+		m.statusBar.SetInfo("Deleting entry...")
+		return m, m.deleteTimeEntry(*selectedEntry)
+
+	case "n", "N", "esc":
+		m.entriesView.CancelDeleteConfirmation()
+		m.statusBar.SetInfo("Delete cancelled")
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m App) handleUndo() (tea.Model, tea.Cmd) {
+	if m.undoEntry == nil {
+		m.statusBar.SetInfo("Nothing to undo")
+		return m, nil
+	}
+
+	snapshot := *m.undoEntry
+	m.undoEntry = nil
+	m.statusBar.ClearUndo()
+	m.statusBar.SetInfo("Restoring entry...")
+	return m, m.restoreTimeEntry(snapshot)
+}
+
 func (m App) handleTimerMsg(msg any) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case TimerStartedMsg:
 		m.timerService.GetState().Start(msg.Entry)
-		m.statusBar.SetSuccess("Timer started")
+		m.awaySince = time.Time{}
+		m.useProject(msg.Entry.ProjectID)
+		m.setWriteSuccess("Timer started")
+		if m.pomodoroPending {
+			m.startPomodoro(msg.Entry)
+		}
 		return m, nil
 
 	case TimerStoppedMsg:
 		m.timerService.GetState().Stop()
 		m.timerView.GetTimerComponent().ClearEditState()
-		m.statusBar.SetSuccess("Timer stopped")
-		return m, nil
+		m.setWriteSuccess("Timer stopped")
+		return m, m.loadTargetProgress()
 
 	case TimerAlreadyStoppedMsg:
 		m.timerService.GetState().Stop()
 		m.timerView.GetTimerComponent().ClearEditState()
+		m.pomodoro.Stop()
 		m.statusBar.SetError(fmt.Errorf("timer was already stopped by other instance"))
 		return m, nil
 
 	case TimerDescriptionUpdatedMsg:
 		m.timerService.GetState().Description = msg.Entry.Description
 		m.timerService.GetState().TagIDs = msg.Entry.TagIDs
-		m.statusBar.SetSuccess("Description and tags updated")
+		if m.pomodoro.Active {
+			// Later work intervals continue with the new description.
+			m.pomodoro.Template.Description = msg.Entry.Description
+			m.pomodoro.Template.TagIDs = msg.Entry.TagIDs
+		}
+		m.setWriteSuccess("Description and tags updated")
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m App) handleEntryMsg(msg any) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TimeEntryCreatedMsg:
+		m.entriesView.HideForm()
+		m.useProject(msg.Entry.ProjectID)
+		m.setWriteSuccess("Entry created")
+		return m, tea.Batch(m.loadEntries(), m.loadTargetProgress())
+
+	case TimeEntryUpdatedMsg:
+		m.entriesView.HideForm()
+		state := m.timerService.GetState()
+		if state.CurrentEntry != nil && state.CurrentEntry.ID == msg.Entry.ID {
+			state.UpdateFromEntry(msg.Entry)
+		}
+		m.setWriteSuccess("Entry updated")
+		return m, tea.Batch(m.loadEntries(), m.loadTargetProgress())
+
+	case TimeEntryDeletedMsg:
+		state := m.timerService.GetState()
+		if state.CurrentEntry != nil && state.CurrentEntry.ID == msg.Entry.ID {
+			state.Stop()
+			m.timerView.GetTimerComponent().ClearEditState()
+		}
+		snapshot := msg.Entry
+		m.undoEntry = &snapshot
+		m.setWriteSuccess("Entry deleted")
+		m.statusBar.SetUndoDeadline(time.Now().Add(undoWindow))
+		return m, tea.Batch(m.loadEntries(), m.loadTargetProgress(), undoExpiryCmd(msg.Entry.ID))
+
+	case TimeEntryRestoredMsg:
+		if msg.Entry.TimeInterval.End == nil {
+			m.timerService.GetState().Start(msg.Entry)
+		}
+		m.setWriteSuccess("Entry restored")
+		return m, tea.Batch(m.loadEntries(), m.loadTargetProgress())
+
+	case UndoExpiredMsg:
+		if m.undoEntry != nil && m.undoEntry.ID == msg.EntryID {
+			m.undoEntry = nil
+			m.statusBar.ClearUndo()
+		}
 		return m, nil
 	}
 
@@ -333,9 +1229,22 @@ func (m App) handleDataLoadedMsg(msg any) (tea.Model, tea.Cmd) {
 		return m.handleTasksLoaded(msg)
 	case TagsLoadedMsg:
 		return m.handleTagsLoaded(msg)
+	case ClientsLoadedMsg:
+		return m.handleClientsLoaded(msg)
+	case UsersLoadedMsg:
+		if msg.WorkspaceID == m.workspaceService.CurrentWorkspaceID() {
+			m.reportsView.GetFilter().SetUsers(msg.Users, m.reportService.CurrentUserID())
+		}
+		return m, nil
 	case TimeEntriesLoadedMsg:
+		if !m.requests.isCurrent(requestEntries, msg.Seq) {
+			return m, nil
+		}
 		m.entries = msg.Entries
 		m.entriesView.SetEntries(msg.Entries)
+		if m.entriesView.GetViewMode() == components.ViewToday {
+			m.entriesView.SetAnalysis(m.entryService.AnalyzeDay(msg.Entries, m.entriesView.GetSelectedDate(), m.workingHours, time.Now()))
+		}
 		return m, nil
 	}
 
@@ -343,28 +1252,77 @@ func (m App) handleDataLoadedMsg(msg any) (tea.Model, tea.Cmd) {
 }
 
 func (m App) handleProjectsLoaded(msg ProjectsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.WorkspaceID != m.workspaceService.CurrentWorkspaceID() {
+		return m, nil
+	}
 	m.projects = msg.Projects
 	m.timerView.SetProjects(msg.Projects)
+	m.entriesView.SetProjectList(msg.Projects)
+	m.reportsView.GetFilter().SetProjects(msg.Projects)
 	projectMap := make(map[string]string)
 	for _, p := range msg.Projects {
 		projectMap[p.ID] = p.Name
 	}
 	m.projectsMap = projectMap
 	m.timerView.SetProjectMap(projectMap)
-	m.entriesView.SetProjects(projectMap)
+	m.entriesView.SetProjects(m.names().ProjectLabels())
+	for _, tasks := range msg.Tasks {
+		for _, task := range tasks {
+			m.tasksMap[task.ID] = task.Name
+		}
+	}
+	m.entriesView.SetTasks(m.tasksMap)
+	return m, nil
+}
+
+func (m App) handleClientsLoaded(msg ClientsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.WorkspaceID != m.workspaceService.CurrentWorkspaceID() {
+		return m, nil
+	}
+	clientMap := make(map[string]string)
+	for _, client := range msg.Clients {
+		clientMap[client.ID] = client.Name
+	}
+	m.clientsMap = clientMap
+	m.timerView.GetProjectSelector().SetClients(clientMap)
+	m.entriesView.GetProjectSelector().SetClients(clientMap)
+	m.reportsView.GetFilter().SetClients(clientMap)
+	m.entriesView.SetProjects(m.names().ProjectLabels())
 	return m, nil
 }
 
+// names collects the loaded names for labels.
+func (m App) names() *domain.Names {
+	names := domain.NewNames()
+	names.AddProjects(m.projects)
+	names.Tasks = m.tasksMap
+	names.Tags = m.tagsMap
+	names.Clients = m.clientsMap
+	return names
+}
+
 func (m App) handleTasksLoaded(msg TasksLoadedMsg) (tea.Model, tea.Cmd) {
 	for _, task := range msg.Tasks {
 		m.tasksMap[task.ID] = task.Name
 	}
-	m.timerView.GetProjectSelector().SetTasks(msg.Tasks)
 	m.entriesView.SetTasks(m.tasksMap)
+	if !m.requests.isCurrent(requestTasks, msg.Seq) {
+		return m, nil
+	}
+	if m.currentView == ReportsView && m.reportsView.IsShowingFilter() {
+		m.reportsView.GetFilter().SetTasks(msg.ProjectID, msg.Tasks)
+	} else if m.currentView == EntriesView && m.entriesView.IsShowingSelector() {
+		m.entriesView.GetProjectSelector().SetTasks(msg.Tasks)
+	} else {
+		m.timerView.GetProjectSelector().SetTasks(msg.Tasks)
+	}
 	return m, nil
 }
 
 func (m App) handleTagsLoaded(msg TagsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.WorkspaceID != m.workspaceService.CurrentWorkspaceID() {
+		return m, nil
+	}
 	m.tags = msg.Tags
 	tagMap := make(map[string]string)
 	for _, tag := range msg.Tags {
@@ -374,36 +1332,255 @@ func (m App) handleTagsLoaded(msg TagsLoadedMsg) (tea.Model, tea.Cmd) {
 	m.timerView.GetProjectSelector().SetTags(msg.Tags)
 	m.timerView.SetTagMap(tagMap)
 	m.entriesView.SetTags(tagMap)
-	m.reportsView.SetTags(tagMap)
+	m.reportsView.GetFilter().SetTags(msg.Tags)
 	return m, nil
 }
 
 func (m App) handleReportMsg(msg any) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case DailyReportLoadedMsg:
+		if !m.requests.isCurrent(requestReports, msg.Seq) {
+			return m, nil
+		}
 		if report, ok := msg.Report.(*domain.DailySummary); ok {
 			m.reportsView.SetDailyReport(report)
 		}
 		return m, nil
 
 	case WeeklyReportLoadedMsg:
+		if !m.requests.isCurrent(requestReports, msg.Seq) {
+			return m, nil
+		}
 		if report, ok := msg.Report.(*domain.WeeklySummary); ok {
 			m.reportsView.SetWeeklyReport(report)
 		}
 		return m, nil
+
+	case RangeReportLoadedMsg:
+		if !m.requests.isCurrent(requestReports, msg.Seq) {
+			return m, nil
+		}
+		m.reportsView.SetRangeReport(msg.Report)
+		return m, nil
+
+	case GroupedReportLoadedMsg:
+		if !m.requests.isCurrent(requestGroupedReport, msg.Seq) {
+			return m, nil
+		}
+		m.reportsView.SetGroupedReport(msg.Report)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m App) handleDescriptionSuggestionsMsg(msg DescriptionSuggestionsLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.requests.isCurrent(requestSuggestions, msg.Seq) {
+		return m, nil
 	}
+	m.timerView.GetProjectSelector().SetSuggestions(msg.Suggestions)
+	return m, nil
+}
 
+// setRecentProjects hands the recently used projects to both project
+// selectors.
+func (m *App) setRecentProjects(projectIDs []string) {
+	m.recentProjects = projectIDs
+	m.timerView.GetProjectSelector().SetRecentProjects(projectIDs)
+	m.entriesView.GetProjectSelector().SetRecentProjects(projectIDs)
+}
+
+// useProject moves a project to the front of the recently used list.
+func (m *App) useProject(projectID *string) {
+	if projectID == nil || *projectID == "" {
+		return
+	}
+	recent := []string{*projectID}
+	for _, id := range m.recentProjects {
+		if id != *projectID {
+			recent = append(recent, id)
+		}
+	}
+	m.setRecentProjects(recent)
+}
+
+// setWriteSuccess reports a completed write, noting when it was only queued
+// in the offline journal.
+func (m *App) setWriteSuccess(msg string) {
+	pending := m.syncService.PendingCount()
+	m.statusBar.SetPendingSync(pending)
+	if pending > 0 {
+		m.statusBar.SetInfo(msg + " offline - will sync when Clockify is reachable")
+		return
+	}
+	m.statusBar.SetSuccess(msg)
+}
+
+func (m App) handleSyncCompleted(msg SyncCompletedMsg) (tea.Model, tea.Cmd) {
+	result := msg.Result
+	m.statusBar.SetPendingSync(m.syncService.PendingCount())
+	if result.Applied == 0 && len(result.Conflicts) == 0 {
+		return m, nil
+	}
+
+	switch {
+	case len(result.Conflicts) == 1:
+		m.statusBar.SetError(fmt.Errorf("synced %d offline change(s); conflict: %s", result.Applied, result.Conflicts[0].Reason))
+	case len(result.Conflicts) > 1:
+		m.statusBar.SetError(fmt.Errorf("synced %d offline change(s); %d conflicts, first: %s",
+			result.Applied, len(result.Conflicts), result.Conflicts[0].Reason))
+	default:
+		m.statusBar.SetSuccess(fmt.Sprintf("Synced %d offline change(s)", result.Applied))
+	}
+
+	// Local IDs were replaced by server ones, so reload what is on screen.
+	cmds := []tea.Cmd{m.loadCurrentTimer, m.loadTargetProgress()}
+	if m.currentView == EntriesView {
+		cmds = append(cmds, m.loadEntries())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleIdleChecked notes when the user went away, and asks about the time
+// once they are back.
+func (m App) handleIdleChecked(msg IdleCheckedMsg) (tea.Model, tea.Cmd) {
+	if !m.timerService.GetState().IsRunning {
+		m.awaySince = time.Time{}
+		return m, nil
+	}
+
+	lastInput := msg.At.Add(-msg.Idle)
+	switch {
+	case m.awaySince.IsZero() && msg.Idle >= m.idleTimeout:
+		m.awaySince = lastInput
+	case !m.awaySince.IsZero() && msg.Idle < m.idleTimeout:
+		m.promptIdle(lastInput)
+	}
+	return m, nil
+}
+
+// promptIdle ends the current absence at end and asks what to do with the
+// part of it the timer was running, if that was long enough to ask.
+func (m *App) promptIdle(end time.Time) bool {
+	state := m.timerService.GetState()
+	period, ok := state.IdlePeriod(m.awaySince, end)
+	m.awaySince = time.Time{}
+	if !ok || period.Duration() < m.idleTimeout {
+		return false
+	}
+	m.idleView.Show(period, state.Description)
+	return true
+}
+
+func (m App) handleIdleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.idleView.GetPrompt()
+	if prompt.IsBusy() {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.applyIdleChoice(domain.KeepIdle)
+	case tea.KeyUp:
+		prompt.MoveUp()
+		return m, nil
+	case tea.KeyDown:
+		prompt.MoveDown()
+		return m, nil
+	case tea.KeyEnter:
+		return m.applyIdleChoice(prompt.GetChoice())
+	}
+
+	if choice, ok := prompt.ChoiceForKey(msg.String()); ok {
+		return m.applyIdleChoice(choice)
+	}
+	return m, nil
+}
+
+func (m App) applyIdleChoice(choice domain.IdleChoice) (tea.Model, tea.Cmd) {
+	prompt := m.idleView.GetPrompt()
+	if choice == domain.KeepIdle {
+		m.idleView.Hide()
+		m.statusBar.SetInfo("Idle time kept")
+		return m, nil
+	}
+
+	prompt.SetBusy()
+	return m, m.resolveIdle(choice, prompt.GetPeriod())
+}
+
+func (m App) handleIdleResolved(msg IdleResolvedMsg) (tea.Model, tea.Cmd) {
+	m.idleView.Hide()
+	if msg.Err != nil {
+		if errors.Is(msg.Err, context.Canceled) {
+			return m, nil
+		}
+		m.statusBar.SetMessage("Error: "+describeError(msg.Err), components.StatusError)
+		// Part of the change may have gone through; show the timer as it is.
+		return m, m.loadCurrentTimer
+	}
+
+	switch msg.Choice {
+	case domain.DiscardIdle:
+		m.timerService.GetState().Stop()
+		m.pomodoro.Stop()
+		m.timerView.GetTimerComponent().ClearEditState()
+		m.setWriteSuccess("Idle time discarded, timer stopped at " + msg.Period.Start.Local().Format("15:04"))
+	case domain.SplitIdle:
+		m.timerService.GetState().Start(msg.Entry)
+		m.setWriteSuccess("Idle time split into a separate entry")
+	}
+
+	if m.currentView == EntriesView {
+		return m, tea.Batch(m.loadEntries(), m.loadTargetProgress())
+	}
+	return m, m.loadTargetProgress()
+}
+
+func (m App) handleErrorMsg(msg ErrorMsg) (tea.Model, tea.Cmd) {
+	if errors.Is(msg.Err, context.Canceled) {
+		return m, nil
+	}
+	m.statusBar.SetMessage("Error: "+describeError(msg.Err), components.StatusError)
 	return m, nil
 }
 
-func (m App) handleDescriptionSuggestionsMsg(msg DescriptionSuggestionsLoadedMsg) (tea.Model, tea.Cmd) {
-	m.timerView.GetProjectSelector().SetSuggestions(msg.Suggestions)
-	return m, nil
+// describeError turns API failures into short, actionable status messages.
+// Writes queued in the offline journal succeed and are reported by
+// setWriteSuccess, so a network error here means nothing was saved.
+func describeError(err error) string {
+	if api.IsNetworkError(err) {
+		return "Cannot reach Clockify - check your connection and press r to retry"
+	}
+
+	apiErr, ok := api.AsAPIError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	switch {
+	case api.IsUnauthorized(err):
+		return "API key rejected - check CLOCKIFY_API_KEY or the api_key in your profile"
+	case api.IsForbidden(err):
+		return "Permission denied by Clockify" + apiErrorDetail(apiErr)
+	case api.IsNotFound(err):
+		return "Not found - it may have been changed elsewhere, press r to refresh" + apiErrorDetail(apiErr)
+	case api.IsRateLimited(err):
+		return "Rate limited by Clockify - wait a moment and try again"
+	case api.IsValidation(err):
+		return "Clockify rejected the request" + apiErrorDetail(apiErr)
+	case api.IsServerError(err):
+		return fmt.Sprintf("Clockify is unavailable (status %d) - try again later", apiErr.StatusCode)
+	}
+
+	return err.Error()
 }
 
-func (m App) handleErrorMsg(msg ErrorMsg) (tea.Model, tea.Cmd) {
-	m.statusBar.SetError(msg.Err)
-	return m, nil
+func apiErrorDetail(apiErr *api.APIError) string {
+	if apiErr.Message == "" {
+		return ""
+	}
+	return ": " + apiErr.Message
 }
 
 func (m App) handleDescriptionEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -494,7 +1671,7 @@ func (m App) handleTagSelectionEnter(selector *components.ProjectSelectorCompone
 	if projectID != nil {
 		selector.Reset()
 		m.timerView.HideProjectSelector()
-		return m, m.startTimerWithTags(projectID, taskID, *description, tagIDs)
+		return m, m.startTimerWithTags(projectID, taskID, *description, tagIDs, domain.ProjectBillable(m.projects, projectID))
 	}
 
 	return m, nil
@@ -577,7 +1754,40 @@ func (m App) handleDescriptionInputKeys(msg tea.KeyMsg, selector *components.Pro
 	return m, nil
 }
 
+// handleFilterKeys sends typing to the fuzzy filter of the project and task
+// lists, so letters no longer double as navigation keys there.
+func handleFilterKeys(msg tea.KeyMsg, selector *components.ProjectSelectorComponent) bool {
+	if mode := selector.GetMode(); mode != components.SelectingProject && mode != components.SelectingTask {
+		return false
+	}
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			selector.AddFilterChar(r)
+		}
+		return true
+
+	case tea.KeySpace:
+		selector.AddFilterChar(' ')
+		return true
+
+	case tea.KeyBackspace:
+		selector.DeleteFilterChar()
+		return true
+
+	case tea.KeyEsc:
+		return selector.ClearFilter()
+	}
+
+	return false
+}
+
 func (m App) handleProjectSelectionKeys(msg tea.KeyMsg, selector *components.ProjectSelectorComponent) (tea.Model, tea.Cmd) {
+	if handleFilterKeys(msg, selector) {
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Up):
 		selector.MoveUp()
@@ -604,6 +1814,125 @@ func (m App) handleProjectSelectionKeys(msg tea.KeyMsg, selector *components.Pro
 	return m, nil
 }
 
+func (m App) handleEntryFormKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := m.entriesView.GetEntryForm()
+
+	switch msg.Type {
+	case tea.KeyCtrlS:
+		return m.saveEntryForm()
+
+	case tea.KeyEsc:
+		m.entriesView.HideForm()
+		return m, nil
+
+	case tea.KeyTab, tea.KeyDown:
+		form.NextField()
+		return m, nil
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		form.PrevField()
+		return m, nil
+
+	case tea.KeyEnter:
+		switch form.GetFocusedField() {
+		case components.FieldProject:
+			m.entriesView.ShowProjectSelector()
+		case components.FieldTags:
+			m.entriesView.ShowTagSelector(m.tags)
+		case components.FieldBillable:
+			form.ToggleBillable()
+		default:
+			form.NextField()
+		}
+		return m, nil
+
+	case tea.KeyBackspace:
+		form.DeleteChar()
+		return m, nil
+
+	case tea.KeySpace:
+		if form.GetFocusedField() == components.FieldBillable {
+			form.ToggleBillable()
+			return m, nil
+		}
+		form.AddChar(' ')
+		return m, nil
+
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			form.AddChar(r)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m App) handleEntrySelectorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	selector := m.entriesView.GetProjectSelector()
+	form := m.entriesView.GetEntryForm()
+
+	if handleFilterKeys(msg, selector) {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		selector.MoveUp()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		selector.MoveDown()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Space):
+		selector.ToggleCurrentTag()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		switch selector.GetMode() {
+		case components.SelectingTags:
+			form.SetTagIDs(selector.GetSelectedTagIDs())
+			m.entriesView.HideSelector()
+		case components.SelectingTask:
+			projectID := selector.GetSelectedProjectID()
+			form.SetProjectSelection(projectID, selector.GetSelectedTaskID())
+			form.SetBillable(domain.ProjectBillable(m.projects, projectID))
+			m.entriesView.HideSelector()
+		default:
+			if projectID := selector.GetSelectedProjectID(); projectID != nil {
+				return m, m.loadTasksForProject(*projectID)
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Back):
+		if selector.GetMode() == components.SelectingTask {
+			selector.Back()
+		} else {
+			m.entriesView.HideSelector()
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m App) saveEntryForm() (tea.Model, tea.Cmd) {
+	form := m.entriesView.GetEntryForm()
+	req, err := form.Build()
+	if err != nil {
+		form.SetError(err)
+		return m, nil
+	}
+
+	m.statusBar.SetInfo("Saving entry...")
+	if form.IsNew() {
+		return m, m.createTimeEntry(req)
+	}
+	return m, m.updateTimeEntry(form.GetEntryID(), req)
+}
+
 func (m App) View() string {
 	if m.width == 0 {
 		return "Loading..."
@@ -618,12 +1947,20 @@ func (m App) View() string {
 	tabs := m.renderTabs()
 	content += tabs + "\n\n"
 
-	switch m.currentView {
-	case TimerView:
+	switch {
+	case m.idleView.IsShowing():
+		content += m.idleView.View()
+	case m.workspacesView.IsShowing():
+		content += m.workspacesView.View()
+	case m.exportView.IsShowing():
+		content += m.exportView.View()
+	case m.importView.IsShowing():
+		content += m.importView.View()
+	case m.currentView == TimerView:
 		content += m.renderTimerView()
-	case EntriesView:
+	case m.currentView == EntriesView:
 		content += m.renderEntriesView()
-	case ReportsView:
+	case m.currentView == ReportsView:
 		content += m.renderReportsView()
 	}
 
@@ -666,9 +2003,23 @@ func (m App) renderTabs() string {
 		tabs = append(tabs, ActiveTabStyle.Render(reportsTab))
 	}
 
+	if name := m.currentWorkspaceName(); name != "" {
+		tabs = append(tabs, WorkspaceLabelStyle.Render(name))
+	}
+
 	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
 }
 
+func (m App) currentWorkspaceName() string {
+	currentID := m.workspaceService.CurrentWorkspaceID()
+	for _, ws := range m.workspaces {
+		if ws.ID == currentID {
+			return ws.Name
+		}
+	}
+	return ""
+}
+
 func (m App) renderTimerView() string {
 	return m.timerView.View()
 }
@@ -706,6 +2057,7 @@ func (m App) renderHelp() string {
 	helpContent += "  " + keyStyle.Render("1") + " " + descStyle.Render("Switch to Timer view") + "\n"
 	helpContent += "  " + keyStyle.Render("2") + " " + descStyle.Render("Switch to Time Entries view") + "\n"
 	helpContent += "  " + keyStyle.Render("3") + " " + descStyle.Render("Switch to Reports view") + "\n"
+	helpContent += "  " + keyStyle.Render("w") + " " + descStyle.Render("Switch workspace") + "\n"
 	helpContent += "  " + keyStyle.Render("r") + " " + descStyle.Render("Refresh current view") + "\n"
 	helpContent += "  " + keyStyle.Render("?") + " " + descStyle.Render("Show this help screen") + "\n"
 	helpContent += "  " + keyStyle.Render("q / Ctrl+C") + " " + descStyle.Render("Quit application") + "\n"
@@ -715,22 +2067,41 @@ func (m App) renderHelp() string {
 	helpContent += "  " + keyStyle.Render("x") + " " + descStyle.Render("Stop running timer") + "\n"
 	helpContent += "  " + keyStyle.Render("p") + " " + descStyle.Render("Select project/task") + "\n"
 	helpContent += "  " + keyStyle.Render("d") + " " + descStyle.Render("Edit description & tags of running timer") + "\n"
+	helpContent += "  " + keyStyle.Render("P") + " " + descStyle.Render("Toggle Pomodoro mode (one entry per work interval)") + "\n"
 
 	helpContent += sectionStyle.Render("Time Entries View") + "\n"
 	helpContent += "  " + keyStyle.Render("↑/↓ or k/j") + " " + descStyle.Render("Navigate entries") + "\n"
 	helpContent += "  " + keyStyle.Render("←/→ or h/l") + " " + descStyle.Render("Navigate days (Today view only)") + "\n"
 	helpContent += "  " + keyStyle.Render("t") + " " + descStyle.Render("Toggle between Today/This Week") + "\n"
 	helpContent += "  " + keyStyle.Render("s") + " " + descStyle.Render("Start timer from focused entry") + "\n"
+	helpContent += "  " + keyStyle.Render("n") + " " + descStyle.Render("Log a new entry for the selected day") + "\n"
+	helpContent += "  " + keyStyle.Render("e") + " " + descStyle.Render("Edit focused entry (ctrl+s saves)") + "\n"
+	helpContent += "  " + keyStyle.Render("n / Enter") + " " + descStyle.Render("Fill the focused gap with a new entry") + "\n"
+	helpContent += "  " + keyStyle.Render("T") + " " + descStyle.Render("Trim focused entry so it no longer overlaps another") + "\n"
+	helpContent += "  " + keyStyle.Render("D / Delete") + " " + descStyle.Render("Delete focused entry (u undoes for a few seconds)") + "\n"
+	helpContent += "  " + keyStyle.Render("E") + " " + descStyle.Render("Export the listed entries to CSV, JSON or Markdown") + "\n"
+	helpContent += "  " + keyStyle.Render("I") + " " + descStyle.Render("Import entries from CSV, Toggl or Timewarrior, with a preview first") + "\n"
 
 	helpContent += sectionStyle.Render("Reports View") + "\n"
-	helpContent += "  " + keyStyle.Render("←/→ or h/l") + " " + descStyle.Render("Navigate dates (prev/next day or week)") + "\n"
-	helpContent += "  " + keyStyle.Render("t") + " " + descStyle.Render("Toggle between Daily/Weekly report") + "\n"
+	helpContent += "  " + keyStyle.Render("←/→ or h/l") + " " + descStyle.Render("Navigate dates (prev/next day, week, month or range)") + "\n"
+	helpContent += "  " + keyStyle.Render("↑/↓ or k/j") + " " + descStyle.Render("Scroll the report") + "\n"
+	helpContent += "  " + keyStyle.Render("t") + " " + descStyle.Render("Cycle Daily/Weekly/Monthly report") + "\n"
+	helpContent += "  " + keyStyle.Render("c") + " " + descStyle.Render("Pick a custom date range") + "\n"
+	helpContent += "  " + keyStyle.Render("f") + " " + descStyle.Render("Filter by project, task, client, tag, user or billable") + "\n"
+	helpContent += "  " + keyStyle.Render("g/G") + " " + descStyle.Render("Group by project, client, task, tag, description or weekday, then by a second one") + "\n"
+	helpContent += "  " + keyStyle.Render("E") + " " + descStyle.Render("Export the report to CSV, JSON or Markdown") + "\n"
+
+	helpContent += sectionStyle.Render("Idle Prompt") + "\n"
+	helpContent += "  " + keyStyle.Render("k / Esc") + " " + descStyle.Render("Keep the time you were away in the running entry") + "\n"
+	helpContent += "  " + keyStyle.Render("d") + " " + descStyle.Render("Discard it and stop the entry when you went idle") + "\n"
+	helpContent += "  " + keyStyle.Render("s") + " " + descStyle.Render("Split it into a separate entry and keep the timer running") + "\n"
 
 	helpContent += sectionStyle.Render("Project/Task/Tag Selector") + "\n"
-	helpContent += "  " + keyStyle.Render("↑/↓ or k/j") + " " + descStyle.Render("Navigate list") + "\n"
+	helpContent += "  " + keyStyle.Render("type") + " " + descStyle.Render("Fuzzy-filter projects or tasks (recently used first)") + "\n"
+	helpContent += "  " + keyStyle.Render("↑/↓") + " " + descStyle.Render("Navigate list (k/j also work for tags)") + "\n"
 	helpContent += "  " + keyStyle.Render("Space") + " " + descStyle.Render("Toggle tag selection (when selecting tags)") + "\n"
 	helpContent += "  " + keyStyle.Render("Enter") + " " + descStyle.Render("Confirm selection") + "\n"
-	helpContent += "  " + keyStyle.Render("Esc") + " " + descStyle.Render("Go back or cancel") + "\n"
+	helpContent += "  " + keyStyle.Render("Esc") + " " + descStyle.Render("Clear filter, go back or cancel") + "\n"
 
 	helpContent += "\n\n" + lipgloss.NewStyle().
 		Foreground(theme.MutedColor).
@@ -753,8 +2124,74 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// idleTickCmd schedules the next idle check, unless idle detection is off.
+func (m *App) idleTickCmd() tea.Cmd {
+	if m.idleTimeout <= 0 {
+		return nil
+	}
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		return IdleTickMsg{}
+	})
+}
+
+// checkIdle asks the detector for the idle time while a timer runs. The
+// desktop is queried through external commands, so this runs off the
+// update loop.
+func (m *App) checkIdle() tea.Cmd {
+	if !m.timerService.GetState().IsRunning || m.idleView.IsShowing() {
+		return nil
+	}
+
+	ctx := m.requests.context()
+	return func() tea.Msg {
+		idleTime := m.idleDetector.Idle(ctx)
+		return IdleCheckedMsg{Idle: idleTime, At: time.Now()}
+	}
+}
+
+func (m *App) resolveIdle(choice domain.IdleChoice, period domain.IdlePeriod) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := m.timerService.ResolveIdle(m.requests.context(), choice, period)
+		return IdleResolvedMsg{Choice: choice, Period: period, Entry: entry, Err: err}
+	}
+}
+
+// bellCmd rings the terminal bell. Bubble Tea has no bell of its own, and
+// the control character leaves the rendered screen alone.
+func bellCmd() tea.Msg {
+	fmt.Fprint(os.Stdout, "\a")
+	return nil
+}
+
+func syncTickCmd() tea.Cmd {
+	return tea.Tick(syncInterval, func(time.Time) tea.Msg {
+		return SyncTickMsg{}
+	})
+}
+
+// syncJournal replays queued offline changes, if there are any.
+func (m *App) syncJournal() tea.Cmd {
+	if m.syncService.PendingCount() == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		result, err := m.syncService.Replay(m.requests.context())
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return SyncCompletedMsg{Result: result}
+	}
+}
+
+func undoExpiryCmd(entryID string) tea.Cmd {
+	return tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return UndoExpiredMsg{EntryID: entryID}
+	})
+}
+
 func (m *App) loadCurrentTimer() tea.Msg {
-	entry, err := m.timerService.GetCurrentTimer()
+	entry, err := m.timerService.GetCurrentTimer(m.requests.context())
 	if err != nil {
 		return ErrorMsg{Err: err}
 	}
@@ -762,31 +2199,109 @@ func (m *App) loadCurrentTimer() tea.Msg {
 	if entry != nil {
 		return TimerStartedMsg{Entry: entry}
 	}
+	if m.timerService.GetState().IsRunning {
+		return TimerAlreadyStoppedMsg{}
+	}
 
 	return nil
 }
 
+func (m *App) loadCachedData() tea.Cmd {
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	snapshot := m.cache.Snapshot()
+	if len(snapshot.Projects) == 0 && len(snapshot.Tags) == 0 {
+		return nil
+	}
+
+	return tea.Sequence(
+		func() tea.Msg {
+			return ProjectsLoadedMsg{WorkspaceID: workspaceID, Projects: snapshot.Projects, Tasks: snapshot.Tasks}
+		},
+		func() tea.Msg {
+			return TagsLoadedMsg{WorkspaceID: workspaceID, Tags: snapshot.Tags}
+		},
+		func() tea.Msg {
+			return ClientsLoadedMsg{WorkspaceID: workspaceID, Clients: snapshot.Clients}
+		},
+	)
+}
+
 func (m *App) loadProjects() tea.Msg {
-	projects, err := m.projectService.GetAllProjects()
+	ctx := m.requests.context()
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	projects, err := m.projectService.ReloadProjects(ctx)
 	if err != nil {
 		return ErrorMsg{Err: err}
 	}
 
+	tasksByProject := make(map[string][]api.Task, len(projects))
 	for _, project := range projects {
-		tasks, err := m.projectService.GetTasksForProject(project.ID)
+		tasks, err := m.projectService.ReloadTasksForProject(ctx, project.ID)
 		if err == nil {
-			for _, task := range tasks {
-				m.tasksMap[task.ID] = task.Name
-			}
+			tasksByProject[project.ID] = tasks
+		}
+	}
+
+	m.cache.Save()
+	return ProjectsLoadedMsg{WorkspaceID: workspaceID, Projects: projects, Tasks: tasksByProject}
+}
+
+func (m *App) loadRecentProjects() tea.Msg {
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	projectIDs, err := m.entryService.GetRecentProjectIDs(m.requests.context(), recentProjectsLookback)
+	if err != nil {
+		// Only affects ranking, not worth an error in the status bar.
+		return nil
+	}
+
+	return RecentProjectsLoadedMsg{WorkspaceID: workspaceID, ProjectIDs: projectIDs}
+}
+
+// loadTargetProgress refreshes the progress toward the hour targets, if any
+// are set.
+func (m *App) loadTargetProgress() tea.Cmd {
+	if !m.hourTargets.IsSet() {
+		return nil
+	}
+
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	ctx := m.requests.context()
+	return func() tea.Msg {
+		progress, err := m.targetService.GetProgress(ctx, time.Now())
+		if err != nil {
+			// The targets only add to the timer and reports; keep what is
+			// shown.
+			return nil
 		}
+		return TargetProgressLoadedMsg{WorkspaceID: workspaceID, Progress: progress}
+	}
+}
+
+func (m *App) loadWorkspaces() tea.Msg {
+	workspaces, err := m.workspaceService.GetWorkspaces(m.requests.context())
+	if err != nil {
+		return ErrorMsg{Err: err}
+	}
+
+	return WorkspacesLoadedMsg{Workspaces: workspaces}
+}
+
+func (m *App) loadClients() tea.Msg {
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	clients, err := m.clientService.ReloadClients(m.requests.context())
+	if err != nil {
+		// Client names only decorate projects; don't report a failure.
+		return nil
 	}
 
-	return ProjectsLoadedMsg{Projects: projects}
+	m.cache.Save()
+	return ClientsLoadedMsg{WorkspaceID: workspaceID, Clients: clients}
 }
 
 func (m *App) loadTasksForProject(projectID string) tea.Cmd {
+	ctx, seq := m.requests.begin(requestTasks)
 	return func() tea.Msg {
-		tasks, err := m.projectService.GetTasksForProject(projectID)
+		tasks, err := m.projectService.GetTasksForProject(ctx, projectID)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
@@ -794,13 +2309,14 @@ func (m *App) loadTasksForProject(projectID string) tea.Cmd {
 		return TasksLoadedMsg{
 			ProjectID: projectID,
 			Tasks:     tasks,
+			Seq:       seq,
 		}
 	}
 }
 
-func (m *App) startTimerWithTags(projectID, taskID *string, description string, tagIDs []string) tea.Cmd {
+func (m *App) startTimerWithTags(projectID, taskID *string, description string, tagIDs []string, billable bool) tea.Cmd {
 	return func() tea.Msg {
-		entry, err := m.timerService.StartTimer(description, projectID, taskID, tagIDs)
+		entry, err := m.timerService.StartTimer(m.requests.context(), description, projectID, taskID, tagIDs, billable)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
@@ -809,6 +2325,20 @@ func (m *App) startTimerWithTags(projectID, taskID *string, description string,
 	}
 }
 
+func (m *App) startPomodoroInterval(template *api.TimeEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := m.timerService.StartPomodoroInterval(m.requests.context(), template)
+		return PomodoroIntervalMsg{Phase: domain.PomodoroWork, Entry: entry, Err: err}
+	}
+}
+
+func (m *App) stopTimerForBreak(phase domain.PomodoroPhase) tea.Cmd {
+	return func() tea.Msg {
+		_, _, err := m.timerService.StopTimer(m.requests.context())
+		return PomodoroIntervalMsg{Phase: phase, Err: err}
+	}
+}
+
 func (m *App) startTimerFromSelectedEntry() (*App, tea.Cmd) {
 	selectedEntry := m.entriesView.GetSelectedEntry()
 	if selectedEntry == nil {
@@ -826,11 +2356,11 @@ func (m *App) startTimerFromSelectedEntry() (*App, tea.Cmd) {
 	}
 
 	m.statusBar.SetInfo("Starting timer from entry...")
-	return m, m.startTimerWithTags(projectID, taskID, description, tagIDs)
+	return m, m.startTimerWithTags(projectID, taskID, description, tagIDs, selectedEntry.Billable)
 }
 
 func (m *App) stopTimer() tea.Msg {
-	entry, alreadyStopped, err := m.timerService.StopTimer()
+	entry, alreadyStopped, err := m.timerService.StopTimer(m.requests.context())
 	if err != nil {
 		return ErrorMsg{Err: err}
 	}
@@ -850,16 +2380,11 @@ func (m *App) updateTimerDescriptionAndTags(description string, tagIDs []string)
 		entryID := m.timerService.GetState().CurrentEntry.ID
 		currentEntry := m.timerService.GetState().CurrentEntry
 
-		req := api.TimeEntryRequest{
-			Start:       currentEntry.TimeInterval.Start,
-			End:         currentEntry.TimeInterval.End,
-			Description: description,
-			ProjectID:   currentEntry.ProjectID,
-			TaskID:      currentEntry.TaskID,
-			TagIDs:      tagIDs,
-		}
+		req := domain.EntryToRequest(currentEntry)
+		req.Description = description
+		req.TagIDs = tagIDs
 
-		entry, err := m.timerService.UpdateTimeEntry(entryID, req)
+		entry, err := m.timerService.UpdateTimeEntry(m.requests.context(), entryID, req)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
@@ -868,65 +2393,184 @@ func (m *App) updateTimerDescriptionAndTags(description string, tagIDs []string)
 	}
 }
 
+func (m *App) createTimeEntry(req api.TimeEntryRequest) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := m.entryService.CreateTimeEntry(m.requests.context(), req)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+
+		return TimeEntryCreatedMsg{Entry: entry}
+	}
+}
+
+func (m *App) updateTimeEntry(entryID string, req api.TimeEntryRequest) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := m.entryService.UpdateTimeEntry(m.requests.context(), entryID, req)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+
+		return TimeEntryUpdatedMsg{Entry: entry}
+	}
+}
+
+func (m *App) deleteTimeEntry(entry api.TimeEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.entryService.DeleteTimeEntry(m.requests.context(), entry.ID); err != nil {
+			return ErrorMsg{Err: err}
+		}
+
+		return TimeEntryDeletedMsg{Entry: entry}
+	}
+}
+
+func (m *App) restoreTimeEntry(snapshot api.TimeEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := m.entryService.CreateTimeEntry(m.requests.context(), domain.EntryToRequest(&snapshot))
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+
+		return TimeEntryRestoredMsg{Entry: entry}
+	}
+}
+
 func (m *App) loadEntries() tea.Cmd {
+	ctx, seq := m.requests.begin(requestEntries)
+	viewMode := m.entriesView.GetViewMode()
+	selectedDate := m.entriesView.GetSelectedDate()
+
 	return func() tea.Msg {
 		var entries []api.TimeEntry
 		var err error
 
-		if m.entriesView.GetViewMode() == components.ViewToday {
-			selectedDate := m.entriesView.GetSelectedDate()
-			entries, err = m.entryService.GetEntriesForDate(selectedDate)
+		if viewMode == components.ViewToday {
+			entries, err = m.entryService.GetEntriesForDate(ctx, selectedDate)
 		} else {
-			entries, err = m.entryService.GetEntriesForWeek()
+			entries, err = m.entryService.GetEntriesForWeek(ctx)
 		}
 
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
 
-		return TimeEntriesLoadedMsg{Entries: entries}
+		return TimeEntriesLoadedMsg{Entries: entries, Seq: seq}
 	}
 }
 
-func (m *App) loadReports() tea.Cmd {
+func (m *App) planImport(path string, format importer.Format, opts domain.ImportOptions) tea.Cmd {
+	return func() tea.Msg {
+		records, format, err := importer.ReadFile(path, format)
+		if err != nil {
+			return ImportPlannedMsg{Err: err}
+		}
+		plan, err := m.importService.Plan(m.requests.context(), records, opts)
+		return ImportPlannedMsg{Plan: plan, Format: format, Err: err}
+	}
+}
+
+func (m *App) commitImport(plan *domain.ImportPlan) tea.Cmd {
 	return func() tea.Msg {
-		selectedDate := m.reportsView.GetSelectedDate()
+		created, err := m.importService.Commit(m.requests.context(), plan)
+		return ImportCommittedMsg{Created: created, Err: err}
+	}
+}
+
+func (m *App) loadReports() tea.Cmd {
+	return tea.Batch(m.loadSummaryReport(), m.loadGroupedReport())
+}
 
-		if m.reportsView.GetReportType() == components.DailyReport {
-			report, err := m.reportService.GetDailySummary(selectedDate, m.projectsMap, m.tasksMap)
+func (m *App) loadSummaryReport() tea.Cmd {
+	ctx, seq := m.requests.begin(requestReports)
+	selectedDate := m.reportsView.GetSelectedDate()
+	reportType := m.reportsView.GetReportType()
+	first, last := m.reportsView.GetRange()
+	filter := m.reportsView.GetFilter().Filter(m.reportService.CurrentUserID())
+
+	return func() tea.Msg {
+		switch reportType {
+		case components.DailyReport:
+			report, err := m.reportService.GetDailySummary(ctx, selectedDate, filter)
 			if err != nil {
 				return ErrorMsg{Err: err}
 			}
 			return DailyReportLoadedMsg{
 				Date:   selectedDate,
 				Report: report,
+				Seq:    seq,
 			}
-		} else {
-			weekday := int(selectedDate.Weekday())
-			if weekday == 0 {
-				weekday = 7
-			}
-			weekStart := selectedDate.AddDate(0, 0, -(weekday - 1))
 
-			report, err := m.reportService.GetWeeklySummary(weekStart, m.projectsMap, m.tasksMap)
+		case components.WeeklyReport:
+			weekStart := domain.WeekStart(selectedDate)
+			report, err := m.reportService.GetWeeklySummary(ctx, weekStart, filter)
 			if err != nil {
 				return ErrorMsg{Err: err}
 			}
 			return WeeklyReportLoadedMsg{
 				StartDate: weekStart,
 				Report:    report,
+				Seq:       seq,
+			}
+
+		case components.MonthlyReport:
+			report, err := m.reportService.GetMonthlySummary(ctx, selectedDate, filter)
+			if err != nil {
+				return ErrorMsg{Err: err}
+			}
+			return RangeReportLoadedMsg{Report: report, Seq: seq}
+
+		default:
+			report, err := m.reportService.GetRangeSummary(ctx, first, last, filter)
+			if err != nil {
+				return ErrorMsg{Err: err}
 			}
+			return RangeReportLoadedMsg{Report: report, Seq: seq}
+		}
+	}
+}
+
+// loadGroupedReport loads the grouped breakdown of the current period, if a
+// grouping is selected.
+func (m *App) loadGroupedReport() tea.Cmd {
+	by, then := m.reportsView.GetGrouping()
+	if by == domain.GroupNone {
+		return nil
+	}
+
+	ctx, seq := m.requests.begin(requestGroupedReport)
+	first, last := m.reportsView.GetPeriod()
+	filter := m.reportsView.GetFilter().Filter(m.reportService.CurrentUserID())
+
+	return func() tea.Msg {
+		report, err := m.reportService.GetGroupedReport(ctx, first, last, filter, by, then)
+		if err != nil {
+			return ErrorMsg{Err: err}
 		}
+		return GroupedReportLoadedMsg{Report: report, Seq: seq}
+	}
+}
+
+// loadUsers fills the user row of the report filter. Without the list the
+// filter still offers Me and Everyone, so a failure is not reported.
+func (m *App) loadUsers() tea.Msg {
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	users, err := m.workspaceService.GetUsers(m.requests.context())
+	if err != nil {
+		return nil
 	}
+	return UsersLoadedMsg{WorkspaceID: workspaceID, Users: users}
 }
 
 func (m *App) loadTags() tea.Msg {
-	tags, err := m.tagService.GetAllTags()
+	workspaceID := m.workspaceService.CurrentWorkspaceID()
+	tags, err := m.tagService.ReloadTags(m.requests.context())
 	if err != nil {
 		return ErrorMsg{Err: err}
 	}
 
-	return TagsLoadedMsg{Tags: tags}
+	m.cache.Save()
+	return TagsLoadedMsg{WorkspaceID: workspaceID, Tags: tags}
 }
 
 func (m *App) refresh() tea.Cmd {
@@ -936,25 +2580,26 @@ func (m *App) refresh() tea.Cmd {
 	case ReportsView:
 		return m.loadReports()
 	default:
-		return m.loadCurrentTimer
+		return tea.Batch(m.loadCurrentTimer, m.loadTargetProgress())
 	}
 }
 
 func (m *App) loadDescriptionSuggestions(description string) tea.Cmd {
+	ctx, seq := m.requests.begin(requestSuggestions)
 	if len(description) < 3 {
 		return func() tea.Msg {
-			return DescriptionSuggestionsLoadedMsg{Suggestions: []string{}}
+			return DescriptionSuggestionsLoadedMsg{Suggestions: []string{}, Seq: seq}
 		}
 	}
 
 	return func() tea.Msg {
-		entries, err := m.entryService.GetEntriesByDescriptionContains(description)
+		entries, err := m.entryService.GetEntriesByDescriptionContains(ctx, description)
 		if err != nil {
-			return DescriptionSuggestionsLoadedMsg{Suggestions: []string{}}
+			return DescriptionSuggestionsLoadedMsg{Suggestions: []string{}, Seq: seq}
 		}
 
 		uniqueDescriptions := extractUniqueDescriptions(entries)
-		return DescriptionSuggestionsLoadedMsg{Suggestions: uniqueDescriptions}
+		return DescriptionSuggestionsLoadedMsg{Suggestions: uniqueDescriptions, Seq: seq}
 	}
 }
 
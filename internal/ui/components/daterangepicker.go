@@ -0,0 +1,152 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"main/internal/domain"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type rangePreset struct {
+	key   rune
+	label string
+	span  func(today time.Time) (time.Time, time.Time)
+}
+
+// rangePresets fill both dates with one key; letters never occur in a date.
+var rangePresets = []rangePreset{
+	{'m', "This month", func(today time.Time) (time.Time, time.Time) {
+		first := domain.MonthStart(today)
+		return first, first.AddDate(0, 1, -1)
+	}},
+	{'l', "Last month", func(today time.Time) (time.Time, time.Time) {
+		first := domain.MonthStart(today).AddDate(0, -1, 0)
+		return first, first.AddDate(0, 1, -1)
+	}},
+	{'d', "Last 30 days", func(today time.Time) (time.Time, time.Time) {
+		return today.AddDate(0, 0, -29), today
+	}},
+	{'y', "This year", func(today time.Time) (time.Time, time.Time) {
+		first := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, today.Location())
+		return first, first.AddDate(1, 0, -1)
+	}},
+}
+
+// DateRangePickerComponent edits the first and last day of a custom report.
+type DateRangePickerComponent struct {
+	from        string
+	to          string
+	focusedFrom bool
+	err         string
+	width       int
+	height      int
+}
+
+func NewDateRangePicker() *DateRangePickerComponent {
+	return &DateRangePickerComponent{focusedFrom: true}
+}
+
+func (c *DateRangePickerComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// Load fills the picker with a range and focuses the first day.
+func (c *DateRangePickerComponent) Load(first, last time.Time) {
+	c.from = first.Format(formDateLayout)
+	c.to = last.Format(formDateLayout)
+	c.focusedFrom = true
+	c.err = ""
+}
+
+func (c *DateRangePickerComponent) ToggleField() {
+	c.focusedFrom = !c.focusedFrom
+}
+
+func (c *DateRangePickerComponent) focusedBuffer() *string {
+	if c.focusedFrom {
+		return &c.from
+	}
+	return &c.to
+}
+
+func (c *DateRangePickerComponent) AddChar(char rune) {
+	if buf := c.focusedBuffer(); len(*buf) < len(formDateLayout) {
+		*buf += string(char)
+		c.err = ""
+	}
+}
+
+func (c *DateRangePickerComponent) DeleteChar() {
+	if buf := c.focusedBuffer(); len(*buf) > 0 {
+		*buf = (*buf)[:len(*buf)-1]
+		c.err = ""
+	}
+}
+
+// ApplyPreset fills both dates from the preset bound to key; it reports false
+// if there is none.
+func (c *DateRangePickerComponent) ApplyPreset(key rune) bool {
+	for _, preset := range rangePresets {
+		if preset.key != key {
+			continue
+		}
+		first, last := preset.span(domain.DayStart(time.Now()))
+		c.from = first.Format(formDateLayout)
+		c.to = last.Format(formDateLayout)
+		c.err = ""
+		return true
+	}
+	return false
+}
+
+func (c *DateRangePickerComponent) SetError(err error) {
+	c.err = err.Error()
+}
+
+// Build validates the dates and returns the first and last day of the range.
+func (c *DateRangePickerComponent) Build() (time.Time, time.Time, error) {
+	first, err := time.ParseInLocation(formDateLayout, strings.TrimSpace(c.from), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start date, expected YYYY-MM-DD")
+	}
+	last, err := time.ParseInLocation(formDateLayout, strings.TrimSpace(c.to), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end date, expected YYYY-MM-DD")
+	}
+	if last.Before(first) {
+		return time.Time{}, time.Time{}, errors.New("end date must not be before start date")
+	}
+	return first, last, nil
+}
+
+func (c *DateRangePickerComponent) View() string {
+	content := selectorTitleStyle.Render("Custom Report Range") + "\n\n"
+	content += c.renderField("From", c.from, c.focusedFrom)
+	content += c.renderField("To", c.to, !c.focusedFrom)
+
+	content += "\n"
+	for _, preset := range rangePresets {
+		content += selectorItemStyle.Render(string(preset.key)+": "+preset.label) + "\n"
+	}
+
+	if c.err != "" {
+		content += "\n" + formErrorStyle.Render(c.err) + "\n"
+	}
+
+	helpText := "tab/↑/↓: switch field | m/l/d/y: preset | enter: show report | esc: cancel"
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
+
+func (c *DateRangePickerComponent) renderField(label, value string, focused bool) string {
+	if !focused {
+		return "  " + formLabelStyle.Render(label) + formValueStyle.Render(value) + "\n"
+	}
+	return "▶ " + formFocusedLabelStyle.Render(label) + formInputStyle.Render(value) + "█\n"
+}
@@ -0,0 +1,426 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/domain"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type EntryFormField int
+
+const (
+	FieldDate EntryFormField = iota
+	FieldStart
+	FieldEnd
+	FieldDuration
+	FieldDescription
+	FieldProject
+	FieldTags
+	FieldBillable
+)
+
+const entryFormFieldCount = 8
+
+const formDateLayout = "2006-01-02"
+
+type EntryFormComponent struct {
+	entryID       string
+	date          time.Time
+	originalStart time.Time
+	originalEnd   *time.Time
+	dateInput     string
+	start         string
+	end           string
+	duration      string
+	description   string
+	projectID     *string
+	taskID        *string
+	tagIDs        []string
+	billable      bool
+	focused       EntryFormField
+	projects      map[string]string
+	tasks         map[string]string
+	tags          map[string]string
+	err           string
+	width         int
+	height        int
+}
+
+var (
+	formLabelStyle = lipgloss.NewStyle().
+			Width(14).
+			Foreground(theme.Subtext0Color)
+
+	formFocusedLabelStyle = lipgloss.NewStyle().
+				Width(14).
+				Foreground(theme.MauveColor).
+				Bold(true)
+
+	formValueStyle = lipgloss.NewStyle().
+			Foreground(theme.TextColor)
+
+	formInputStyle = lipgloss.NewStyle().
+			Foreground(theme.GreenColor).
+			Bold(true)
+
+	formErrorStyle = lipgloss.NewStyle().
+			Foreground(theme.RedColor)
+)
+
+func NewEntryForm() *EntryFormComponent {
+	return &EntryFormComponent{
+		projects: make(map[string]string),
+		tasks:    make(map[string]string),
+		tags:     make(map[string]string),
+	}
+}
+
+func (c *EntryFormComponent) LoadEntry(entry *api.TimeEntry) {
+	start := entry.TimeInterval.Start.Local()
+
+	c.entryID = entry.ID
+	c.date = start
+	c.originalStart = entry.TimeInterval.Start
+	c.originalEnd = entry.TimeInterval.End
+	c.dateInput = start.Format(formDateLayout)
+	c.start = start.Format("15:04")
+	c.end = ""
+	if entry.TimeInterval.End != nil {
+		c.end = entry.TimeInterval.End.Local().Format("15:04")
+	}
+	c.duration = ""
+	c.description = entry.Description
+	c.projectID = entry.ProjectID
+	c.taskID = entry.TaskID
+	c.tagIDs = append([]string(nil), entry.TagIDs...)
+	c.billable = entry.Billable
+	c.focused = FieldStart
+	c.err = ""
+}
+
+func (c *EntryFormComponent) LoadNew(date time.Time) {
+	c.entryID = ""
+	c.date = date
+	c.originalStart = time.Time{}
+	c.originalEnd = nil
+	c.dateInput = date.Format(formDateLayout)
+	c.start = ""
+	c.end = ""
+	c.duration = ""
+	c.description = ""
+	c.projectID = nil
+	c.taskID = nil
+	c.tagIDs = nil
+	c.billable = false
+	c.focused = FieldStart
+	c.err = ""
+}
+
+// LoadGap prepares a new entry filling the untracked time from start to end.
+// The times are rounded inwards to whole minutes so the entry can't overlap
+// its neighbours.
+func (c *EntryFormComponent) LoadGap(start, end time.Time) {
+	start = start.Local()
+	if rounded := start.Truncate(time.Minute); rounded.Before(start) {
+		start = rounded.Add(time.Minute)
+	}
+	end = end.Local()
+
+	c.LoadNew(start)
+	c.start = start.Format("15:04")
+	c.end = end.Format("15:04")
+	c.focused = FieldDescription
+}
+
+func (c *EntryFormComponent) IsNew() bool {
+	return c.entryID == ""
+}
+
+func (c *EntryFormComponent) SetProjects(projects map[string]string) {
+	c.projects = projects
+}
+
+func (c *EntryFormComponent) SetTasks(tasks map[string]string) {
+	c.tasks = tasks
+}
+
+func (c *EntryFormComponent) SetTags(tags map[string]string) {
+	c.tags = tags
+}
+
+func (c *EntryFormComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+func (c *EntryFormComponent) GetFocusedField() EntryFormField {
+	return c.focused
+}
+
+func (c *EntryFormComponent) NextField() {
+	c.focused = (c.focused + 1) % entryFormFieldCount
+}
+
+func (c *EntryFormComponent) PrevField() {
+	c.focused = (c.focused + entryFormFieldCount - 1) % entryFormFieldCount
+}
+
+func (c *EntryFormComponent) isTextField() bool {
+	return c.focusedBuffer() != nil
+}
+
+func (c *EntryFormComponent) focusedBuffer() *string {
+	switch c.focused {
+	case FieldDate:
+		return &c.dateInput
+	case FieldStart:
+		return &c.start
+	case FieldEnd:
+		return &c.end
+	case FieldDuration:
+		return &c.duration
+	case FieldDescription:
+		return &c.description
+	}
+	return nil
+}
+
+func (c *EntryFormComponent) AddChar(char rune) {
+	if buf := c.focusedBuffer(); buf != nil && len(*buf) < 255 {
+		*buf += string(char)
+		c.err = ""
+
+		// End and duration are alternatives; typing one clears the other.
+		switch c.focused {
+		case FieldEnd:
+			c.duration = ""
+		case FieldDuration:
+			c.end = ""
+		}
+	}
+}
+
+func (c *EntryFormComponent) DeleteChar() {
+	switch c.focused {
+	case FieldProject:
+		c.projectID = nil
+		c.taskID = nil
+		return
+	case FieldTags:
+		c.tagIDs = nil
+		return
+	}
+
+	if buf := c.focusedBuffer(); buf != nil && len(*buf) > 0 {
+		runes := []rune(*buf)
+		*buf = string(runes[:len(runes)-1])
+		c.err = ""
+	}
+}
+
+func (c *EntryFormComponent) GetProjectID() *string {
+	return c.projectID
+}
+
+func (c *EntryFormComponent) GetTagIDs() []string {
+	return c.tagIDs
+}
+
+func (c *EntryFormComponent) SetProjectSelection(projectID, taskID *string) {
+	c.projectID = projectID
+	c.taskID = taskID
+}
+
+func (c *EntryFormComponent) SetTagIDs(tagIDs []string) {
+	c.tagIDs = tagIDs
+}
+
+// SetBillable sets the billable flag, e.g. to the default of a newly chosen
+// project.
+func (c *EntryFormComponent) SetBillable(billable bool) {
+	c.billable = billable
+}
+
+func (c *EntryFormComponent) ToggleBillable() {
+	c.billable = !c.billable
+}
+
+func (c *EntryFormComponent) SetError(err error) {
+	c.err = err.Error()
+}
+
+func (c *EntryFormComponent) GetEntryID() string {
+	return c.entryID
+}
+
+// Build validates the form and returns the request to send. Unchanged times
+// are passed through untouched so that seconds are not truncated. An end time
+// before the start is taken to be on the next day.
+func (c *EntryFormComponent) Build() (api.TimeEntryRequest, error) {
+	day := c.date
+	dateChanged := c.dateInput != c.date.Format(formDateLayout)
+	if dateChanged {
+		parsed, err := time.ParseInLocation(formDateLayout, strings.TrimSpace(c.dateInput), time.Local)
+		if err != nil {
+			return api.TimeEntryRequest{}, errors.New("invalid date, expected YYYY-MM-DD")
+		}
+		day = parsed
+	}
+
+	start := c.originalStart
+	if c.IsNew() || dateChanged || c.start != c.originalStart.Local().Format("15:04") {
+		parsed, err := domain.ParseClockTime(day, c.start)
+		if err != nil {
+			return api.TimeEntryRequest{}, err
+		}
+		start = parsed
+	}
+
+	var end *time.Time
+	switch {
+	case strings.TrimSpace(c.duration) != "":
+		d, err := domain.ParseEntryDuration(c.duration)
+		if err != nil {
+			return api.TimeEntryRequest{}, err
+		}
+		computed := start.Add(d)
+		end = &computed
+	case strings.TrimSpace(c.end) == "":
+		if c.IsNew() || c.originalEnd != nil {
+			return api.TimeEntryRequest{}, errors.New("end time or duration is required")
+		}
+	case !dateChanged && c.originalEnd != nil && c.end == c.originalEnd.Local().Format("15:04"):
+		end = c.originalEnd
+	default:
+		parsed, err := domain.ParseClockTime(day, c.end)
+		if err != nil {
+			return api.TimeEntryRequest{}, err
+		}
+		// An end at or before the start on the same day means the entry runs
+		// past midnight, unless the date was typed in for this edit.
+		if !dateChanged && !parsed.After(start) {
+			parsed, err = domain.ParseClockTime(day.AddDate(0, 0, 1), c.end)
+			if err != nil {
+				return api.TimeEntryRequest{}, err
+			}
+		}
+		end = &parsed
+	}
+
+	if end != nil && !end.After(start) {
+		return api.TimeEntryRequest{}, errors.New("end time must be after start time, enter a duration for entries past midnight")
+	}
+
+	return api.TimeEntryRequest{
+		Start:       start.UTC(),
+		End:         utcPtr(end),
+		Description: c.description,
+		ProjectID:   c.projectID,
+		TaskID:      c.taskID,
+		TagIDs:      c.tagIDs,
+		Billable:    c.billable,
+	}, nil
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func (c *EntryFormComponent) View() string {
+	titleText := "Edit Time Entry"
+	if c.IsNew() {
+		titleText = "New Time Entry"
+	}
+	content := selectorTitleStyle.Render(titleText) + "\n\n"
+
+	endPlaceholder := "HH:MM"
+	if !c.IsNew() && c.originalEnd == nil {
+		endPlaceholder = "(running)"
+	}
+	content += c.renderField(FieldDate, "Date", c.dateInput, "YYYY-MM-DD")
+	content += c.renderField(FieldStart, "Start", c.start, "HH:MM")
+	content += c.renderField(FieldEnd, "End", c.end, endPlaceholder)
+	content += c.renderField(FieldDuration, "Duration", c.duration, "or e.g. 1h30m")
+	content += c.renderField(FieldDescription, "Description", c.description, "(no description)")
+	content += c.renderField(FieldProject, "Project", c.projectLabel(), "(no project)")
+	content += c.renderField(FieldTags, "Tags", c.tagsLabel(), "(no tags)")
+	content += c.renderField(FieldBillable, "Billable", c.billableLabel(), "")
+
+	if c.err != "" {
+		content += "\n" + formErrorStyle.Render(c.err) + "\n"
+	}
+
+	helpText := "tab/↑/↓: move | enter: select project/tags, toggle billable | backspace: clear | ctrl+s: save | esc: cancel"
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
+
+func (c *EntryFormComponent) renderField(field EntryFormField, label, value, placeholder string) string {
+	focused := c.focused == field
+
+	labelStyle := formLabelStyle
+	if focused {
+		labelStyle = formFocusedLabelStyle
+	}
+
+	rendered := formValueStyle.Render(value)
+	if value == "" {
+		rendered = lipgloss.NewStyle().Foreground(theme.Subtext0Color).Italic(true).Render(placeholder)
+	}
+	if focused && c.isTextField() {
+		rendered = formInputStyle.Render(value) + "█"
+	}
+
+	prefix := "  "
+	if focused {
+		prefix = "▶ "
+	}
+
+	return prefix + labelStyle.Render(label) + rendered + "\n"
+}
+
+func (c *EntryFormComponent) projectLabel() string {
+	if c.projectID == nil {
+		return ""
+	}
+	label := *c.projectID
+	if name, ok := c.projects[*c.projectID]; ok {
+		label = name
+	}
+	if c.taskID != nil {
+		if name, ok := c.tasks[*c.taskID]; ok {
+			label += " • " + name
+		}
+	}
+	return label
+}
+
+func (c *EntryFormComponent) billableLabel() string {
+	if c.billable {
+		return "[x] yes"
+	}
+	return "[ ] no"
+}
+
+func (c *EntryFormComponent) tagsLabel() string {
+	names := make([]string, 0, len(c.tagIDs))
+	for _, tagID := range c.tagIDs {
+		if name, ok := c.tags[tagID]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, tagID)
+		}
+	}
+	return strings.Join(names, ", ")
+}
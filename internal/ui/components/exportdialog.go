@@ -0,0 +1,143 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"main/internal/export"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ExportDialogComponent picks the format and file of an export. The file
+// name follows the format until the user edits it.
+type ExportDialogComponent struct {
+	title       string
+	kind        string
+	first       time.Time
+	last        time.Time
+	format      int
+	path        string
+	pathEdited  bool
+	focusedPath bool
+	// confirmOverwrite is set once the file turned out to exist; enter
+	// then replaces it.
+	confirmOverwrite bool
+	err              string
+	width            int
+	height           int
+}
+
+func NewExportDialog() *ExportDialogComponent {
+	return &ExportDialogComponent{}
+}
+
+func (c *ExportDialogComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// Load prepares an export of kind ("entries" or "report") covering the days
+// from first to last, keeping the last used format.
+func (c *ExportDialogComponent) Load(title, kind string, first, last time.Time) {
+	c.title = title
+	c.kind = kind
+	c.first = first
+	c.last = last
+	c.pathEdited = false
+	c.focusedPath = false
+	c.confirmOverwrite = false
+	c.err = ""
+	c.path = export.FileName(kind, first, last, c.Format())
+}
+
+func (c *ExportDialogComponent) Format() export.Format {
+	return export.Formats[c.format]
+}
+
+func (c *ExportDialogComponent) Path() string {
+	return strings.TrimSpace(c.path)
+}
+
+func (c *ExportDialogComponent) ToggleField() {
+	c.focusedPath = !c.focusedPath
+}
+
+func (c *ExportDialogComponent) IsPathFocused() bool {
+	return c.focusedPath
+}
+
+func (c *ExportDialogComponent) CycleFormat(direction int) {
+	c.format = (c.format + direction + len(export.Formats)) % len(export.Formats)
+	if !c.pathEdited {
+		c.path = export.FileName(c.kind, c.first, c.last, c.Format())
+	}
+	c.confirmOverwrite = false
+	c.err = ""
+}
+
+func (c *ExportDialogComponent) AddChar(char rune) {
+	c.path += string(char)
+	c.pathEdited = true
+	c.confirmOverwrite = false
+	c.err = ""
+}
+
+func (c *ExportDialogComponent) DeleteChar() {
+	if len(c.path) > 0 {
+		runes := []rune(c.path)
+		c.path = string(runes[:len(runes)-1])
+		c.pathEdited = true
+		c.confirmOverwrite = false
+		c.err = ""
+	}
+}
+
+// RequestOverwrite asks whether to replace the existing file.
+func (c *ExportDialogComponent) RequestOverwrite() {
+	c.confirmOverwrite = true
+	c.err = ""
+}
+
+func (c *ExportDialogComponent) IsConfirmingOverwrite() bool {
+	return c.confirmOverwrite
+}
+
+func (c *ExportDialogComponent) SetError(err error) {
+	c.err = err.Error()
+}
+
+func (c *ExportDialogComponent) View() string {
+	content := selectorTitleStyle.Render("Export "+c.title) + "\n\n"
+
+	var formats []string
+	for i, format := range export.Formats {
+		label := format.String()
+		if i == c.format {
+			label = formInputStyle.Render("[" + label + "]")
+		}
+		formats = append(formats, label)
+	}
+	formatLine := strings.Join(formats, "  ")
+	if c.focusedPath {
+		content += "  " + formLabelStyle.Render("Format") + formValueStyle.Render(formatLine) + "\n"
+		content += "▶ " + formFocusedLabelStyle.Render("File") + formInputStyle.Render(c.path) + "█\n"
+	} else {
+		content += "▶ " + formFocusedLabelStyle.Render("Format") + formatLine + "\n"
+		content += "  " + formLabelStyle.Render("File") + formValueStyle.Render(c.path) + "\n"
+	}
+
+	if c.err != "" {
+		content += "\n" + formErrorStyle.Render(c.err) + "\n"
+	}
+
+	helpText := "tab/↑/↓: switch field | ←/→: format | enter: export | esc: cancel"
+	if c.confirmOverwrite {
+		content += "\n" + formErrorStyle.Render(c.Path()+" already exists - press enter again to replace it") + "\n"
+		helpText = "enter: replace the file | type or ←/→: pick another | esc: cancel"
+	}
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
@@ -0,0 +1,119 @@
+package components
+
+import (
+	"fmt"
+
+	"main/internal/domain"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var idleChoices = []struct {
+	choice domain.IdleChoice
+	key    string
+	label  string
+}{
+	{domain.KeepIdle, "k", "Keep the idle time"},
+	{domain.DiscardIdle, "d", "Discard it and stop the timer when you left"},
+	{domain.SplitIdle, "s", "Split it into a separate entry and keep the timer running"},
+}
+
+// IdlePromptComponent asks what to do with the time the timer ran while the
+// user was away.
+type IdlePromptComponent struct {
+	period      domain.IdlePeriod
+	description string
+	selected    int
+	busy        bool
+	width       int
+	height      int
+}
+
+func NewIdlePrompt() *IdlePromptComponent {
+	return &IdlePromptComponent{}
+}
+
+func (c *IdlePromptComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// Load prepares the prompt for an idle period of the entry described by
+// description.
+func (c *IdlePromptComponent) Load(period domain.IdlePeriod, description string) {
+	c.period = period
+	c.description = description
+	c.selected = 0
+	c.busy = false
+}
+
+func (c *IdlePromptComponent) GetPeriod() domain.IdlePeriod {
+	return c.period
+}
+
+func (c *IdlePromptComponent) MoveUp() {
+	if c.selected > 0 {
+		c.selected--
+	}
+}
+
+func (c *IdlePromptComponent) MoveDown() {
+	if c.selected < len(idleChoices)-1 {
+		c.selected++
+	}
+}
+
+func (c *IdlePromptComponent) GetChoice() domain.IdleChoice {
+	return idleChoices[c.selected].choice
+}
+
+// ChoiceForKey returns the choice bound to a shortcut key.
+func (c *IdlePromptComponent) ChoiceForKey(key string) (domain.IdleChoice, bool) {
+	for _, option := range idleChoices {
+		if option.key == key {
+			return option.choice, true
+		}
+	}
+	return domain.KeepIdle, false
+}
+
+func (c *IdlePromptComponent) SetBusy() {
+	c.busy = true
+}
+
+func (c *IdlePromptComponent) IsBusy() bool {
+	return c.busy
+}
+
+func (c *IdlePromptComponent) View() string {
+	content := selectorTitleStyle.Render("Welcome back") + "\n\n"
+
+	description := c.description
+	if description == "" {
+		description = "(no description)"
+	}
+	content += fmt.Sprintf("You were away from %s to %s (%s)\nwhile the timer for %s was running.\n\n",
+		c.period.Start.Local().Format("15:04"),
+		c.period.End.Local().Format("15:04"),
+		domain.FormatDuration(c.period.Duration()),
+		formValueStyle.Bold(true).Render(description))
+
+	for i, option := range idleChoices {
+		line := option.key + ": " + option.label
+		if i == c.selected {
+			content += selectorSelectedStyle.Render("▶ "+line) + "\n"
+		} else {
+			content += selectorItemStyle.Render(line) + "\n"
+		}
+	}
+
+	if c.busy {
+		content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("Updating the timer...") + "\n"
+	}
+
+	helpText := "↑/↓: navigate | enter or k/d/s: choose | esc: keep"
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
@@ -0,0 +1,298 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"main/internal/domain"
+	"main/internal/importer"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rows of the import setup form.
+const (
+	importFieldPath = iota
+	importFieldFormat
+	importFieldCreate
+	importFieldOverlaps
+	importFieldCount
+)
+
+// importChromeHeight is the number of lines around the preview list: tabs,
+// the box, summary, help and the status bar.
+const importChromeHeight = 18
+
+var importStatusStyles = map[domain.ImportStatus]lipgloss.Style{
+	domain.ImportReady:     lipgloss.NewStyle().Foreground(theme.GreenColor).Bold(true),
+	domain.ImportDuplicate: lipgloss.NewStyle().Foreground(theme.Subtext0Color),
+	domain.ImportOverlap:   lipgloss.NewStyle().Foreground(theme.PeachColor).Bold(true),
+	domain.ImportInvalid:   lipgloss.NewStyle().Foreground(theme.RedColor).Bold(true),
+}
+
+// ImportComponent asks for a file and options, then previews what importing
+// it will do before anything is created.
+type ImportComponent struct {
+	path          string
+	format        int
+	createMissing bool
+	allowOverlaps bool
+	focused       int
+
+	plan         *domain.ImportPlan
+	planFormat   importer.Format
+	scrollOffset int
+
+	busy   string
+	err    string
+	width  int
+	height int
+}
+
+func NewImporter() *ImportComponent {
+	return &ImportComponent{}
+}
+
+func (c *ImportComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// Open starts at the setup form, keeping the file and options of the last
+// import.
+func (c *ImportComponent) Open() {
+	c.plan = nil
+	c.busy = ""
+	c.err = ""
+	c.focused = importFieldPath
+}
+
+func (c *ImportComponent) IsPreviewing() bool {
+	return c.plan != nil
+}
+
+func (c *ImportComponent) IsBusy() bool {
+	return c.busy != ""
+}
+
+// SetBusy shows what is being done, e.g. "Reading file...", until the next
+// SetPlan or SetError.
+func (c *ImportComponent) SetBusy(text string) {
+	c.busy = text
+	c.err = ""
+}
+
+func (c *ImportComponent) SetError(err error) {
+	c.busy = ""
+	c.err = err.Error()
+}
+
+func (c *ImportComponent) SetPlan(plan *domain.ImportPlan, format importer.Format) {
+	c.busy = ""
+	c.err = ""
+	c.plan = plan
+	c.planFormat = format
+	c.scrollOffset = 0
+}
+
+func (c *ImportComponent) GetPlan() *domain.ImportPlan {
+	return c.plan
+}
+
+// Back leaves the preview for the setup form, e.g. to change the options.
+func (c *ImportComponent) Back() {
+	c.plan = nil
+	c.err = ""
+}
+
+func (c *ImportComponent) Path() string {
+	return strings.TrimSpace(c.path)
+}
+
+func (c *ImportComponent) Format() importer.Format {
+	return importer.Formats[c.format]
+}
+
+func (c *ImportComponent) Options() domain.ImportOptions {
+	return domain.ImportOptions{CreateMissing: c.createMissing, AllowOverlaps: c.allowOverlaps}
+}
+
+func (c *ImportComponent) MoveUp() {
+	if c.plan != nil {
+		if c.scrollOffset > 0 {
+			c.scrollOffset--
+		}
+		return
+	}
+	c.focused = (c.focused + importFieldCount - 1) % importFieldCount
+}
+
+func (c *ImportComponent) MoveDown() {
+	if c.plan != nil {
+		if c.scrollOffset < len(c.plan.Items)-c.visibleItems() {
+			c.scrollOffset++
+		}
+		return
+	}
+	c.focused = (c.focused + 1) % importFieldCount
+}
+
+func (c *ImportComponent) IsPathFocused() bool {
+	return c.plan == nil && c.focused == importFieldPath
+}
+
+// Cycle steps the format, or flips the focused option.
+func (c *ImportComponent) Cycle(direction int) {
+	switch c.focused {
+	case importFieldFormat:
+		c.format = (c.format + direction + len(importer.Formats)) % len(importer.Formats)
+	case importFieldCreate:
+		c.createMissing = !c.createMissing
+	case importFieldOverlaps:
+		c.allowOverlaps = !c.allowOverlaps
+	}
+}
+
+func (c *ImportComponent) AddChar(char rune) {
+	c.path += string(char)
+	c.err = ""
+}
+
+func (c *ImportComponent) DeleteChar() {
+	if len(c.path) > 0 {
+		runes := []rune(c.path)
+		c.path = string(runes[:len(runes)-1])
+		c.err = ""
+	}
+}
+
+func (c *ImportComponent) visibleItems() int {
+	return max(c.height-importChromeHeight, 3)
+}
+
+func (c *ImportComponent) View() string {
+	if c.plan != nil {
+		return c.renderPreview()
+	}
+
+	content := selectorTitleStyle.Render("Import Time Entries") + "\n\n"
+	content += c.renderField(importFieldPath, "File", c.path, true)
+	content += c.renderField(importFieldFormat, "Format", c.Format().String(), false)
+	content += c.renderField(importFieldCreate, "Create", yesNo(c.createMissing)+" (missing projects, tasks and tags)", false)
+	content += c.renderField(importFieldOverlaps, "Overlaps", yesNo(c.allowOverlaps)+" (import entries that overlap others)", false)
+
+	content += c.renderStatus()
+	helpText := "↑/↓/tab: switch field | ←/→/space: change | enter: preview | esc: cancel"
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
+
+func (c *ImportComponent) renderField(field int, label, value string, input bool) string {
+	if field != c.focused {
+		return "  " + formLabelStyle.Render(label) + formValueStyle.Render(value) + "\n"
+	}
+	if input {
+		return "▶ " + formFocusedLabelStyle.Render(label) + formInputStyle.Render(value) + "█\n"
+	}
+	return "▶ " + formFocusedLabelStyle.Render(label) + formInputStyle.Render("◀ "+value+" ▶") + "\n"
+}
+
+func (c *ImportComponent) renderStatus() string {
+	switch {
+	case c.busy != "":
+		return "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(c.busy) + "\n"
+	case c.err != "":
+		return "\n" + formErrorStyle.Render(c.err) + "\n"
+	}
+	return ""
+}
+
+func (c *ImportComponent) renderPreview() string {
+	plan := c.plan
+	content := selectorTitleStyle.Render(fmt.Sprintf("Import Preview - %s (%s)", c.Path(), c.planFormat)) + "\n\n"
+
+	var counts []string
+	for _, status := range []domain.ImportStatus{domain.ImportReady, domain.ImportDuplicate, domain.ImportOverlap, domain.ImportInvalid} {
+		counts = append(counts, importStatusStyles[status].Render(fmt.Sprintf("%d %s", plan.Count(status), status)))
+	}
+	content += strings.Join(counts, "  ") + "\n"
+
+	var tasks []string
+	for _, task := range plan.NewTasks {
+		tasks = append(tasks, task.String())
+	}
+	for _, created := range []struct {
+		what  string
+		names []string
+	}{{"projects", plan.NewProjects}, {"tasks", tasks}, {"tags", plan.NewTags}} {
+		if len(created.names) > 0 {
+			content += formValueStyle.Render(fmt.Sprintf("Will create %s: %s", created.what, strings.Join(created.names, ", "))) + "\n"
+		}
+	}
+	content += "\n"
+
+	end := min(c.scrollOffset+c.visibleItems(), len(plan.Items))
+	for _, item := range plan.Items[c.scrollOffset:end] {
+		content += c.renderItem(item) + "\n"
+	}
+	if len(plan.Items) == 0 {
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("The file holds no entries") + "\n"
+	}
+
+	content += c.renderStatus()
+	ready := plan.Count(domain.ImportReady)
+	helpText := "↑/↓: scroll | esc: back"
+	if ready > 0 {
+		helpText = fmt.Sprintf("↑/↓: scroll | enter: import %d entries | esc: back", ready)
+	}
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
+
+func (c *ImportComponent) renderItem(item domain.ImportItem) string {
+	status := importStatusStyles[item.Status].Render(fmt.Sprintf("%-9s", item.Status))
+
+	line := ""
+	if !item.Record.Start.IsZero() {
+		line = item.Record.Start.Format("Mon Jan 2 15:04")
+	}
+	if !item.Record.End.IsZero() {
+		line += "-" + item.Record.End.Format("15:04")
+	}
+	if item.Record.Description != "" {
+		line += "  " + item.Record.Description
+	}
+	if item.Project != "" {
+		line += "  [" + item.Project
+		if item.Task != "" {
+			line += " • " + item.Task
+		}
+		line += "]"
+	}
+	if len(item.Tags) > 0 {
+		line += "  #" + strings.Join(item.Tags, " #")
+	}
+	if item.Billable {
+		line += "  $"
+	}
+	if item.Reason != "" {
+		line += "  (" + item.Reason + ")"
+	}
+
+	// Keep each item on one line so the list scrolls predictably.
+	maxWidth := c.width - 20
+	if runes := []rune(line); maxWidth > 0 && len(runes) > maxWidth {
+		line = string(runes[:maxWidth-1]) + "…"
+	}
+	return fmt.Sprintf("%4d ", item.Record.Line) + status + " " + line
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
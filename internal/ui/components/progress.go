@@ -0,0 +1,62 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"main/internal/domain"
+	"main/internal/ui/theme"
+)
+
+var (
+	progressFilledStyle = lipgloss.NewStyle().
+				Foreground(theme.BlueColor)
+
+	progressDoneStyle = lipgloss.NewStyle().
+				Foreground(theme.GreenColor)
+
+	progressEmptyStyle = lipgloss.NewStyle().
+				Foreground(theme.Surface1Color)
+
+	balanceAheadStyle = lipgloss.NewStyle().
+				Foreground(theme.GreenColor).
+				Bold(true)
+
+	balanceBehindStyle = lipgloss.NewStyle().
+				Foreground(theme.PeachColor).
+				Bold(true)
+)
+
+// renderProgress draws tracked time against a target as a bar of width
+// cells followed by the numbers, e.g. "█████░░░░░ 4h 0m 0s of 8h 0m 0s (50%)".
+func renderProgress(tracked, target time.Duration, width int) string {
+	ratio := float64(tracked) / float64(target)
+	filled := min(int(ratio*float64(width)), width)
+
+	style := progressFilledStyle
+	if tracked >= target {
+		style = progressDoneStyle
+	}
+	bar := style.Render(strings.Repeat("█", filled)) +
+		progressEmptyStyle.Render(strings.Repeat("░", width-filled))
+
+	text := fmt.Sprintf(" %s of %s (%d%%)", domain.FormatDuration(tracked), domain.FormatDuration(target), int(ratio*100))
+	if tracked > target {
+		text += fmt.Sprintf(", %s over", domain.FormatDuration(tracked-target))
+	}
+	return bar + text
+}
+
+// renderBalance shows the overtime balance, colored by whether it is ahead
+// or behind.
+func renderBalance(progress *domain.TargetProgress) string {
+	style := balanceAheadStyle
+	if progress.Balance < 0 {
+		style = balanceBehindStyle
+	}
+	return style.Render(domain.FormatBalance(progress.Balance)) +
+		lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+			Render(" since "+progress.BalanceSince.Format("Jan 2, 2006")+", not counting today")
+}
@@ -1,9 +1,11 @@
 package components
 
 import (
+	"fmt"
 	"slices"
 
 	"main/internal/api"
+	"main/internal/domain"
 	"main/internal/ui/theme"
 
 	"github.com/charmbracelet/lipgloss"
@@ -28,6 +30,10 @@ type ProjectSelectorComponent struct {
 	currentTagCursor   int
 	mode               SelectorMode
 	filterInput        string
+	recentProjectIDs   []string
+	clients            map[string]string
+	projectMatches     []domain.FuzzyMatch
+	taskMatches        []domain.FuzzyMatch
 	description        string
 	width              int
 	height             int
@@ -54,6 +60,11 @@ var (
 				Border(lipgloss.RoundedBorder()).
 				Padding(1).
 				BorderForeground(theme.MauveColor)
+
+	selectorMatchStyle = lipgloss.NewStyle().
+				Foreground(theme.YellowColor).
+				Bold(true).
+				Underline(true)
 )
 
 func NewProjectSelector() *ProjectSelectorComponent {
@@ -67,13 +78,118 @@ func NewProjectSelector() *ProjectSelectorComponent {
 
 func (c *ProjectSelectorComponent) SetProjects(projects []api.Project) {
 	c.projects = projects
-	c.selectedProject = 0
+	c.filterProjects()
+}
+
+// SetRecentProjects sets the projects to rank first, most recently used first.
+func (c *ProjectSelectorComponent) SetRecentProjects(projectIDs []string) {
+	c.recentProjectIDs = projectIDs
+	c.refilterProjects()
+}
+
+// SetClients sets the client names, keyed by client ID, shown in front of
+// project names and matched by the filter.
+func (c *ProjectSelectorComponent) SetClients(clients map[string]string) {
+	c.clients = clients
+	c.refilterProjects()
+}
+
+func (c *ProjectSelectorComponent) projectLabel(project api.Project) string {
+	if project.ClientID != nil {
+		if client, ok := c.clients[*project.ClientID]; ok {
+			return client + " / " + project.Name
+		}
+	}
+	return project.Name
+}
+
+// refilterProjects re-ranks the project list, keeping the current selection
+// if it still matches.
+func (c *ProjectSelectorComponent) refilterProjects() {
+	selected := c.selectedProject
+	c.filterProjects()
+	if matchPosition(c.projectMatches, selected) >= 0 {
+		c.selectedProject = selected
+	}
 }
 
 func (c *ProjectSelectorComponent) SetTasks(tasks []api.Task) {
 	c.tasks = tasks
-	c.selectedTask = 0
 	c.mode = SelectingTask
+	c.filterInput = ""
+	c.filterTasks()
+}
+
+func (c *ProjectSelectorComponent) filterProjects() {
+	names := make([]string, len(c.projects))
+	for i, p := range c.projects {
+		names[i] = c.projectLabel(p)
+	}
+	boost := domain.RecentProjectBoost(c.projects, c.recentProjectIDs)
+	c.projectMatches = domain.FuzzyFilter(c.filterInput, names, boost)
+
+	c.selectedProject = -1
+	if len(c.projectMatches) > 0 {
+		c.selectedProject = c.projectMatches[0].Index
+	}
+}
+
+func (c *ProjectSelectorComponent) filterTasks() {
+	names := make([]string, len(c.tasks))
+	for i, t := range c.tasks {
+		names[i] = t.Name
+	}
+	c.taskMatches = domain.FuzzyFilter(c.filterInput, names, nil)
+
+	c.selectedTask = -1
+	if len(c.taskMatches) > 0 {
+		c.selectedTask = c.taskMatches[0].Index
+	}
+}
+
+// AddFilterChar narrows the project or task list and selects the best match.
+func (c *ProjectSelectorComponent) AddFilterChar(char rune) {
+	switch c.mode {
+	case SelectingProject:
+		c.filterInput += string(char)
+		c.filterProjects()
+	case SelectingTask:
+		c.filterInput += string(char)
+		c.filterTasks()
+	}
+}
+
+func (c *ProjectSelectorComponent) DeleteFilterChar() {
+	if c.filterInput == "" {
+		return
+	}
+	runes := []rune(c.filterInput)
+	c.filterInput = string(runes[:len(runes)-1])
+	switch c.mode {
+	case SelectingProject:
+		c.filterProjects()
+	case SelectingTask:
+		c.filterTasks()
+	}
+}
+
+// ClearFilter empties the filter and reports whether there was one.
+func (c *ProjectSelectorComponent) ClearFilter() bool {
+	if c.filterInput == "" {
+		return false
+	}
+	c.filterInput = ""
+	switch c.mode {
+	case SelectingProject:
+		c.filterProjects()
+	case SelectingTask:
+		c.filterTasks()
+	}
+	return true
+}
+
+func (c *ProjectSelectorComponent) GetFilter() string {
+	return c.filterInput
 }
 
 func (c *ProjectSelectorComponent) SetTags(tags []api.Tag) {
@@ -103,13 +219,9 @@ func (c *ProjectSelectorComponent) SetSize(width, height int) {
 func (c *ProjectSelectorComponent) MoveUp() {
 	switch c.mode {
 	case SelectingProject:
-		if c.selectedProject > 0 {
-			c.selectedProject--
-		}
+		c.selectedProject = moveMatch(c.projectMatches, c.selectedProject, -1)
 	case SelectingTask:
-		if c.selectedTask > 0 {
-			c.selectedTask--
-		}
+		c.selectedTask = moveMatch(c.taskMatches, c.selectedTask, -1)
 	case SelectingTags:
 		if c.currentTagCursor > 0 {
 			c.currentTagCursor--
@@ -120,13 +232,9 @@ func (c *ProjectSelectorComponent) MoveUp() {
 func (c *ProjectSelectorComponent) MoveDown() {
 	switch c.mode {
 	case SelectingProject:
-		if c.selectedProject < len(c.projects)-1 {
-			c.selectedProject++
-		}
+		c.selectedProject = moveMatch(c.projectMatches, c.selectedProject, 1)
 	case SelectingTask:
-		if c.selectedTask < len(c.tasks)-1 {
-			c.selectedTask++
-		}
+		c.selectedTask = moveMatch(c.taskMatches, c.selectedTask, 1)
 	case SelectingTags:
 		if c.currentTagCursor < len(c.tags)-1 {
 			c.currentTagCursor++
@@ -134,6 +242,26 @@ func (c *ProjectSelectorComponent) MoveDown() {
 	}
 }
 
+// moveMatch moves the selection by delta within the filtered order and returns
+// the newly selected index.
+func moveMatch(matches []domain.FuzzyMatch, selected, delta int) int {
+	pos := matchPosition(matches, selected)
+	if pos < 0 {
+		return selected
+	}
+	pos = min(max(pos+delta, 0), len(matches)-1)
+	return matches[pos].Index
+}
+
+func matchPosition(matches []domain.FuzzyMatch, selected int) int {
+	for pos, match := range matches {
+		if match.Index == selected {
+			return pos
+		}
+	}
+	return -1
+}
+
 func (c *ProjectSelectorComponent) Back() bool {
 	if c.mode == SelectingTags {
 		c.mode = EnteringDescription
@@ -154,6 +282,10 @@ func (c *ProjectSelectorComponent) Back() bool {
 		c.mode = SelectingProject
 		c.selectedTask = -1
 		c.tasks = nil
+		c.taskMatches = nil
+		// Show the full list again, keeping the project that was picked.
+		c.filterInput = ""
+		c.refilterProjects()
 		return false
 	}
 	return true
@@ -295,11 +427,13 @@ func (c *ProjectSelectorComponent) GetMode() SelectorMode {
 
 func (c *ProjectSelectorComponent) Reset() {
 	c.mode = SelectingProject
-	c.selectedProject = 0
+	c.filterInput = ""
+	c.filterProjects()
 	c.selectedTask = -1
 	c.selectedTags = make(map[int]bool)
 	c.currentTagCursor = 0
 	c.tasks = nil
+	c.taskMatches = nil
 	c.description = ""
 	c.suggestions = nil
 	c.selectedSuggestion = 0
@@ -314,6 +448,23 @@ func (c *ProjectSelectorComponent) GetSelectedProjectID() *string {
 	return nil
 }
 
+func (c *ProjectSelectorComponent) GetSelectedTaskID() *string {
+	if c.selectedTask >= 0 && c.selectedTask < len(c.tasks) {
+		tid := c.tasks[c.selectedTask].ID
+		return &tid
+	}
+	return nil
+}
+
+func (c *ProjectSelectorComponent) SelectProject(projectID string) {
+	for i, project := range c.projects {
+		if project.ID == projectID {
+			c.selectedProject = i
+			return
+		}
+	}
+}
+
 func (c *ProjectSelectorComponent) View() string {
 	switch c.mode {
 	case SelectingProject:
@@ -328,64 +479,112 @@ func (c *ProjectSelectorComponent) View() string {
 
 func (c *ProjectSelectorComponent) renderProjectList() string {
 	title := selectorTitleStyle.Render("Select Project")
-	content := title + "\n\n"
-
-	visibleStart := 0
-	visibleEnd := len(c.projects)
-	maxVisible := 10
+	content := title + "\n" + c.renderFilter(len(c.projectMatches), len(c.projects)) + "\n"
 
-	if len(c.projects) > maxVisible {
-		if c.selectedProject > maxVisible/2 {
-			visibleStart = c.selectedProject - maxVisible/2
-		}
-		visibleEnd = visibleStart + maxVisible
-		if visibleEnd > len(c.projects) {
-			visibleEnd = len(c.projects)
-			visibleStart = max(visibleEnd-maxVisible, 0)
-		}
+	if len(c.projectMatches) == 0 {
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("No matching projects") + "\n"
 	}
 
-	for i := visibleStart; i < visibleEnd; i++ {
-		project := c.projects[i]
-		line := project.Name
-
-		if i == c.selectedProject {
-			content += selectorSelectedStyle.Render("▶ "+line) + "\n"
-		} else {
-			content += selectorItemStyle.Render(line) + "\n"
-		}
+	visibleStart, visibleEnd := visibleRange(matchPosition(c.projectMatches, c.selectedProject), len(c.projectMatches), 10)
+	for _, match := range c.projectMatches[visibleStart:visibleEnd] {
+		content += renderMatchLine(c.projectLabel(c.projects[match.Index]), match.Positions, match.Index == c.selectedProject)
 	}
 
-	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("↑/↓: navigate | enter: select | esc: cancel")
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("type to filter | ↑/↓: navigate | enter: select | esc: cancel")
 
 	return selectorBoxStyle.Width(c.width - 4).Render(content)
 }
 
 func (c *ProjectSelectorComponent) renderTaskList() string {
 	title := selectorTitleStyle.Render("Select Task")
-	content := title + "\n\n"
 
 	if len(c.tasks) == 0 {
+		content := title + "\n\n"
 		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("No tasks available for this project") + "\n\n"
 		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("enter: continue without task | esc: back")
 		return selectorBoxStyle.Width(c.width - 4).Render(content)
 	}
 
-	for i, task := range c.tasks {
-		line := task.Name
+	content := title + "\n" + c.renderFilter(len(c.taskMatches), len(c.tasks)) + "\n"
 
-		if i == c.selectedTask {
-			content += selectorSelectedStyle.Render("▶ "+line) + "\n"
-		} else {
-			content += selectorItemStyle.Render(line) + "\n"
-		}
+	if len(c.taskMatches) == 0 {
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("No matching tasks, enter continues without task") + "\n"
 	}
 
-	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("↑/↓: navigate | enter: select | esc: back")
+	visibleStart, visibleEnd := visibleRange(matchPosition(c.taskMatches, c.selectedTask), len(c.taskMatches), 10)
+	for _, match := range c.taskMatches[visibleStart:visibleEnd] {
+		content += renderMatchLine(c.tasks[match.Index].Name, match.Positions, match.Index == c.selectedTask)
+	}
+
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("type to filter | ↑/↓: navigate | enter: select | esc: back")
 
 	return selectorBoxStyle.Width(c.width - 4).Render(content)
 }
 
+func (c *ProjectSelectorComponent) renderFilter(matched, total int) string {
+	if c.filterInput == "" {
+		return "\n"
+	}
+	inputStyle := lipgloss.NewStyle().
+		Foreground(theme.GreenColor).
+		Bold(true)
+	countStyle := lipgloss.NewStyle().Foreground(theme.Subtext0Color)
+	return " " + inputStyle.Render(c.filterInput) + "█ " + countStyle.Render(fmt.Sprintf("(%d/%d)", matched, total)) + "\n"
+}
+
+// visibleRange returns the window of at most maxVisible rows around cursor.
+func visibleRange(cursor, total, maxVisible int) (int, int) {
+	if total <= maxVisible {
+		return 0, total
+	}
+	start := 0
+	if cursor > maxVisible/2 {
+		start = cursor - maxVisible/2
+	}
+	end := start + maxVisible
+	if end > total {
+		end = total
+		start = max(end-maxVisible, 0)
+	}
+	return start, end
+}
+
+// renderMatchLine renders a list row with the fuzzy-matched characters
+// highlighted.
+func renderMatchLine(name string, positions []int, selected bool) string {
+	base := lipgloss.NewStyle()
+	prefix := ""
+	rowStyle := selectorItemStyle
+	if selected {
+		base = base.Foreground(theme.MauveColor).Bold(true)
+		prefix = base.Render("▶ ")
+		rowStyle = lipgloss.NewStyle().PaddingLeft(1)
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+
+	var line string
+	runes := []rune(name)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		segment := string(runes[start:end])
+		if matched[start] {
+			line += selectorMatchStyle.Render(segment)
+		} else {
+			line += base.Render(segment)
+		}
+		start = end
+	}
+
+	return rowStyle.Render(prefix+line) + "\n"
+}
+
 func (c *ProjectSelectorComponent) renderDescriptionInput() string {
 	title := selectorTitleStyle.Render("Enter Description")
 	content := title + "\n\n"
@@ -430,7 +629,7 @@ func (c *ProjectSelectorComponent) renderDescriptionInput() string {
 
 	projectName := "Unknown"
 	if c.selectedProject >= 0 && c.selectedProject < len(c.projects) {
-		projectName = c.projects[c.selectedProject].Name
+		projectName = c.projectLabel(c.projects[c.selectedProject])
 	}
 
 	summaryStyle := lipgloss.NewStyle().Foreground(theme.Subtext0Color)
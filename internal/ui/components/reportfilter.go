@@ -0,0 +1,354 @@
+package components
+
+import (
+	"sort"
+	"strings"
+
+	"main/internal/api"
+	"main/internal/domain"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rows of the report filter panel.
+const (
+	FilterProject = iota
+	FilterTask
+	FilterClient
+	FilterTag
+	FilterUser
+	FilterBillable
+	filterRowCount
+)
+
+// Option IDs of the user and billable rows; the first option of every row
+// has the empty ID and is the default.
+const (
+	filterAllUsers    = "all"
+	filterBillable    = "billable"
+	filterNonBillable = "non-billable"
+)
+
+type filterOption struct {
+	id    string
+	label string
+}
+
+type filterRow struct {
+	label    string
+	options  []filterOption
+	selected string
+	query    string
+}
+
+// ReportFilterComponent picks what the reports are narrowed to: one project,
+// task, client, tag and user, and billable status. Typing in a row jumps to
+// the best matching option, ←/→ step through the matches.
+type ReportFilterComponent struct {
+	rows    [filterRowCount]filterRow
+	saved   [filterRowCount]string
+	focused int
+	width   int
+	height  int
+}
+
+func NewReportFilter() *ReportFilterComponent {
+	c := &ReportFilterComponent{}
+	c.rows[FilterProject] = filterRow{label: "Project", options: []filterOption{{"", "Any"}}}
+	c.rows[FilterTask] = filterRow{label: "Task", options: []filterOption{{"", "Any"}}}
+	c.rows[FilterClient] = filterRow{label: "Client", options: []filterOption{{"", "Any"}}}
+	c.rows[FilterTag] = filterRow{label: "Tag", options: []filterOption{{"", "Any"}}}
+	c.rows[FilterUser] = filterRow{label: "User", options: []filterOption{{"", "Me"}, {filterAllUsers, "Everyone"}}}
+	c.rows[FilterBillable] = filterRow{label: "Billable", options: []filterOption{
+		{"", "Any"}, {filterBillable, "Billable"}, {filterNonBillable, "Non-billable"},
+	}}
+	return c
+}
+
+func (c *ReportFilterComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+func (c *ReportFilterComponent) SetProjects(projects []api.Project) {
+	options := make([]filterOption, 0, len(projects))
+	for _, p := range projects {
+		options = append(options, filterOption{p.ID, p.Name})
+	}
+	c.setOptions(FilterProject, "Any", options)
+}
+
+// SetTasks lists the tasks of the selected project; other projects' tasks
+// are ignored.
+func (c *ReportFilterComponent) SetTasks(projectID string, tasks []api.Task) {
+	if projectID != c.rows[FilterProject].selected {
+		return
+	}
+	options := make([]filterOption, 0, len(tasks))
+	for _, t := range tasks {
+		options = append(options, filterOption{t.ID, t.Name})
+	}
+	c.setOptions(FilterTask, "Any", options)
+}
+
+func (c *ReportFilterComponent) SetClients(clients map[string]string) {
+	options := make([]filterOption, 0, len(clients))
+	for id, name := range clients {
+		options = append(options, filterOption{id, name})
+	}
+	c.setOptions(FilterClient, "Any", options)
+}
+
+func (c *ReportFilterComponent) SetTags(tags []api.Tag) {
+	options := make([]filterOption, 0, len(tags))
+	for _, t := range tags {
+		options = append(options, filterOption{t.ID, t.Name})
+	}
+	c.setOptions(FilterTag, "Any", options)
+}
+
+// SetUsers lists the workspace members after Me and Everyone, leaving out
+// the current user, who is Me.
+func (c *ReportFilterComponent) SetUsers(users []api.User, currentUserID string) {
+	options := []filterOption{{filterAllUsers, "Everyone"}}
+	var members []filterOption
+	for _, u := range users {
+		if u.ID != currentUserID {
+			members = append(members, filterOption{u.ID, u.Name})
+		}
+	}
+	sortOptions(members)
+	c.rows[FilterUser].options = append([]filterOption{{"", "Me"}}, append(options, members...)...)
+	c.keepSelection(FilterUser)
+}
+
+// setOptions replaces a row's options, sorted by label after the default.
+func (c *ReportFilterComponent) setOptions(row int, defaultLabel string, options []filterOption) {
+	sortOptions(options)
+	c.rows[row].options = append([]filterOption{{"", defaultLabel}}, options...)
+	c.keepSelection(row)
+}
+
+// keepSelection falls back to the default when the selected option is gone,
+// e.g. after switching workspaces.
+func (c *ReportFilterComponent) keepSelection(row int) {
+	r := &c.rows[row]
+	for _, option := range r.options {
+		if option.id == r.selected {
+			return
+		}
+	}
+	r.selected = ""
+}
+
+func sortOptions(options []filterOption) {
+	sort.SliceStable(options, func(i, j int) bool {
+		return strings.ToLower(options[i].label) < strings.ToLower(options[j].label)
+	})
+}
+
+// Open remembers the current selection, so that Cancel can restore it.
+func (c *ReportFilterComponent) Open() {
+	for i := range c.rows {
+		c.saved[i] = c.rows[i].selected
+		c.rows[i].query = ""
+	}
+	c.focused = FilterProject
+}
+
+func (c *ReportFilterComponent) Cancel() {
+	for i := range c.rows {
+		c.rows[i].selected = c.saved[i]
+		c.rows[i].query = ""
+	}
+}
+
+// Changed reports whether the selection differs from the one at Open.
+func (c *ReportFilterComponent) Changed() bool {
+	for i := range c.rows {
+		if c.rows[i].selected != c.saved[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ReportFilterComponent) MoveUp() {
+	c.focused = (c.focused + filterRowCount - 1) % filterRowCount
+}
+
+func (c *ReportFilterComponent) MoveDown() {
+	c.focused = (c.focused + 1) % filterRowCount
+}
+
+// FocusedRow returns the row being edited, one of FilterProject to
+// FilterBillable.
+func (c *ReportFilterComponent) FocusedRow() int {
+	return c.focused
+}
+
+// SelectedProjectID returns the project filtered on, or "" for any.
+func (c *ReportFilterComponent) SelectedProjectID() string {
+	return c.rows[FilterProject].selected
+}
+
+// matches returns the indexes of the focused row's options that match its
+// query, best first.
+func (c *ReportFilterComponent) matches() []int {
+	r := &c.rows[c.focused]
+	if r.query == "" {
+		indexes := make([]int, len(r.options))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	labels := make([]string, len(r.options))
+	for i, option := range r.options {
+		labels[i] = option.label
+	}
+	var indexes []int
+	for _, match := range domain.FuzzyFilter(r.query, labels, nil) {
+		indexes = append(indexes, match.Index)
+	}
+	return indexes
+}
+
+// Cycle steps the focused row through its matching options.
+func (c *ReportFilterComponent) Cycle(direction int) {
+	r := &c.rows[c.focused]
+	indexes := c.matches()
+	if len(indexes) == 0 {
+		return
+	}
+
+	current := -1
+	for i, index := range indexes {
+		if r.options[index].id == r.selected {
+			current = i
+			break
+		}
+	}
+	next := (current + direction + len(indexes)) % len(indexes)
+	if current == -1 && direction < 0 {
+		next = len(indexes) - 1
+	}
+	c.selectOption(r.options[indexes[next]].id)
+}
+
+func (c *ReportFilterComponent) AddChar(char rune) {
+	c.rows[c.focused].query += string(char)
+	if indexes := c.matches(); len(indexes) > 0 {
+		c.selectOption(c.rows[c.focused].options[indexes[0]].id)
+	}
+}
+
+// DeleteChar shortens the focused row's query, or resets the row to its
+// default once the query is empty.
+func (c *ReportFilterComponent) DeleteChar() {
+	r := &c.rows[c.focused]
+	if r.query == "" {
+		c.selectOption("")
+		return
+	}
+	r.query = r.query[:len(r.query)-1]
+	if indexes := c.matches(); r.query != "" && len(indexes) > 0 {
+		c.selectOption(r.options[indexes[0]].id)
+	}
+}
+
+func (c *ReportFilterComponent) selectOption(id string) {
+	c.rows[c.focused].selected = id
+	if c.focused == FilterProject {
+		// Tasks belong to one project; the app loads the new project's.
+		c.rows[FilterTask].options = c.rows[FilterTask].options[:1]
+		c.rows[FilterTask].selected = ""
+		c.rows[FilterTask].query = ""
+	}
+}
+
+// Filter returns the report filter for the selection; currentUserID is used
+// when the user row is left at Me.
+func (c *ReportFilterComponent) Filter(currentUserID string) api.ReportFilter {
+	var filter api.ReportFilter
+	if id := c.rows[FilterProject].selected; id != "" {
+		filter.ProjectIDs = []string{id}
+	}
+	if id := c.rows[FilterTask].selected; id != "" {
+		filter.TaskIDs = []string{id}
+	}
+	if id := c.rows[FilterClient].selected; id != "" {
+		filter.ClientIDs = []string{id}
+	}
+	if id := c.rows[FilterTag].selected; id != "" {
+		filter.TagIDs = []string{id}
+	}
+
+	switch id := c.rows[FilterUser].selected; id {
+	case "":
+		filter.UserIDs = []string{currentUserID}
+	case filterAllUsers:
+	default:
+		filter.UserIDs = []string{id}
+	}
+
+	switch c.rows[FilterBillable].selected {
+	case filterBillable:
+		billable := true
+		filter.Billable = &billable
+	case filterNonBillable:
+		billable := false
+		filter.Billable = &billable
+	}
+	return filter
+}
+
+// Summary describes the active filters, e.g. "Project: Website, User:
+// Everyone", or returns "" when every row is at its default.
+func (c *ReportFilterComponent) Summary() string {
+	var parts []string
+	for i := range c.rows {
+		if c.rows[i].selected != "" {
+			parts = append(parts, c.rows[i].label+": "+c.selectedLabel(i))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c *ReportFilterComponent) selectedLabel(row int) string {
+	r := &c.rows[row]
+	for _, option := range r.options {
+		if option.id == r.selected {
+			return option.label
+		}
+	}
+	return r.selected
+}
+
+func (c *ReportFilterComponent) View() string {
+	content := selectorTitleStyle.Render("Filter Reports") + "\n\n"
+	for i := range c.rows {
+		content += c.renderRow(i)
+	}
+
+	helpText := "↑/↓/tab: switch filter | type to search | ←/→: cycle | backspace: clear | enter: apply | esc: cancel"
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(helpText)
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
+
+func (c *ReportFilterComponent) renderRow(row int) string {
+	r := &c.rows[row]
+	value := c.selectedLabel(row)
+	if row != c.focused {
+		return "  " + formLabelStyle.Render(r.label) + formValueStyle.Render(value) + "\n"
+	}
+
+	line := "▶ " + formFocusedLabelStyle.Render(r.label) + formInputStyle.Render("◀ "+value+" ▶")
+	if r.query != "" {
+		line += "  " + selectorMatchStyle.Render("/"+r.query)
+	}
+	return line + "\n"
+}
@@ -3,10 +3,12 @@ package components
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"main/internal/domain"
+	"main/internal/export"
 	"main/internal/ui/theme"
 )
 
@@ -15,16 +17,29 @@ type ReportType int
 const (
 	DailyReport ReportType = iota
 	WeeklyReport
+	MonthlyReport
+	RangeReport
 )
 
 type ReportsComponent struct {
 	reportType   ReportType
 	dailyReport  *domain.DailySummary
 	weeklyReport *domain.WeeklySummary
+	rangeReport  *domain.RangeSummary
 	selectedDate time.Time
-	tags         map[string]string
-	width        int
-	height       int
+	rangeFirst   time.Time
+	rangeLast    time.Time
+	// grouping replaces the client/project/task breakdown when its first
+	// dimension is set.
+	grouping      [2]domain.GroupBy
+	groupedReport *domain.GroupedReport
+	// targets are compared with the report when it shows the user's own,
+	// unfiltered time.
+	targets  *domain.HourTargets
+	progress *domain.TargetProgress
+	ownTime  bool
+	width    int
+	height   int
 }
 
 var (
@@ -38,6 +53,10 @@ var (
 				Foreground(theme.GreenColor).
 				MarginTop(1)
 
+	reportClientStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(theme.PeachColor)
+
 	reportProjectStyle = lipgloss.NewStyle().
 				Bold(true).
 				Foreground(theme.SapphireColor)
@@ -48,6 +67,13 @@ var (
 
 	reportBarStyle = lipgloss.NewStyle().
 			Foreground(theme.MauveColor)
+
+	reportBillingStyle = lipgloss.NewStyle().
+				Foreground(theme.Subtext1Color)
+
+	reportEarningsStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(theme.YellowColor)
 )
 
 func NewReportsComponent() *ReportsComponent {
@@ -65,12 +91,48 @@ func (c *ReportsComponent) GetReportType() ReportType {
 	return c.reportType
 }
 
+// ToggleReportType cycles through the daily, weekly and monthly reports; a
+// custom range goes back to the daily report.
 func (c *ReportsComponent) ToggleReportType() {
-	if c.reportType == DailyReport {
+	switch c.reportType {
+	case DailyReport:
 		c.reportType = WeeklyReport
-	} else {
+	case WeeklyReport:
+		c.reportType = MonthlyReport
+	default:
 		c.reportType = DailyReport
 	}
+	c.rangeReport = nil
+	c.groupedReport = nil
+}
+
+// SetRange switches to a custom report of the days from first to last.
+func (c *ReportsComponent) SetRange(first, last time.Time) {
+	c.reportType = RangeReport
+	c.rangeFirst = domain.DayStart(first)
+	c.rangeLast = domain.DayStart(last)
+	c.rangeReport = nil
+	c.groupedReport = nil
+}
+
+// GetRange returns the first and last day of the custom range.
+func (c *ReportsComponent) GetRange() (time.Time, time.Time) {
+	return c.rangeFirst, c.rangeLast
+}
+
+func (c *ReportsComponent) SetTargets(targets *domain.HourTargets) {
+	c.targets = targets
+}
+
+// SetTargetProgress provides the overtime balance shown under the report.
+func (c *ReportsComponent) SetTargetProgress(progress *domain.TargetProgress) {
+	c.progress = progress
+}
+
+// SetOwnTime tells whether the report shows only the user's own time, the
+// only time targets apply to.
+func (c *ReportsComponent) SetOwnTime(ownTime bool) {
+	c.ownTime = ownTime
 }
 
 func (c *ReportsComponent) SetDailyReport(report *domain.DailySummary) {
@@ -81,8 +143,109 @@ func (c *ReportsComponent) SetWeeklyReport(report *domain.WeeklySummary) {
 	c.weeklyReport = report
 }
 
-func (c *ReportsComponent) SetTags(tags map[string]string) {
-	c.tags = tags
+func (c *ReportsComponent) SetRangeReport(report *domain.RangeSummary) {
+	c.rangeReport = report
+}
+
+func (c *ReportsComponent) SetGroupedReport(report *domain.GroupedReport) {
+	c.groupedReport = report
+}
+
+// GetGrouping returns the dimensions of the grouped breakdown; the first is
+// GroupNone for the default client/project/task breakdown.
+func (c *ReportsComponent) GetGrouping() (domain.GroupBy, domain.GroupBy) {
+	return c.grouping[0], c.grouping[1]
+}
+
+// CycleGrouping steps the first dimension through domain.Groupings and back
+// to the default breakdown; the second is dropped if it now clashes.
+func (c *ReportsComponent) CycleGrouping() {
+	c.grouping[0] = nextGrouping(c.grouping[0], domain.GroupNone)
+	if c.grouping[0] == domain.GroupNone || c.grouping[1] == c.grouping[0] {
+		c.grouping[1] = domain.GroupNone
+	}
+	c.groupedReport = nil
+}
+
+// CycleSubgrouping steps the second dimension, skipping the first; it does
+// nothing while the default breakdown is shown.
+func (c *ReportsComponent) CycleSubgrouping() {
+	if c.grouping[0] == domain.GroupNone {
+		return
+	}
+	c.grouping[1] = nextGrouping(c.grouping[1], c.grouping[0])
+	c.groupedReport = nil
+}
+
+func nextGrouping(current, skip domain.GroupBy) domain.GroupBy {
+	options := append([]domain.GroupBy{domain.GroupNone}, domain.Groupings...)
+	for i, g := range options {
+		if g != current {
+			continue
+		}
+		next := options[(i+1)%len(options)]
+		if next == skip && next != domain.GroupNone {
+			next = options[(i+2)%len(options)]
+		}
+		return next
+	}
+	return domain.GroupNone
+}
+
+// ExportTable returns the report being shown for export: the grouped
+// breakdown when grouping is on, or false while it is still loading.
+func (c *ReportsComponent) ExportTable() (export.Table, bool) {
+	first, last := c.Period()
+	if c.grouping[0] != domain.GroupNone {
+		if c.groupedReport == nil {
+			return export.Table{}, false
+		}
+		title := fmt.Sprintf("%s - %s", first.Format("Jan 2"), last.Format("Jan 2, 2006"))
+		switch c.reportType {
+		case DailyReport:
+			title = first.Format("Monday, January 2, 2006")
+		case MonthlyReport:
+			title = first.Format("January 2006")
+		}
+		return export.GroupedReport(title, c.groupedReport), true
+	}
+
+	switch c.reportType {
+	case DailyReport:
+		if c.dailyReport == nil {
+			return export.Table{}, false
+		}
+		return export.DailyReport(c.dailyReport), true
+	case WeeklyReport:
+		if c.weeklyReport == nil {
+			return export.Table{}, false
+		}
+		return export.WeeklyReport(c.weeklyReport), true
+	}
+	if c.rangeReport == nil {
+		return export.Table{}, false
+	}
+	title := first.Format("January 2006")
+	if c.reportType == RangeReport {
+		title = fmt.Sprintf("%s - %s", first.Format("Jan 2"), last.Format("Jan 2, 2006"))
+	}
+	return export.RangeReport(title, c.rangeReport), true
+}
+
+// Period returns the first and last day shown by the current report.
+func (c *ReportsComponent) Period() (time.Time, time.Time) {
+	switch c.reportType {
+	case WeeklyReport:
+		first := domain.WeekStart(c.selectedDate)
+		return first, first.AddDate(0, 0, 6)
+	case MonthlyReport:
+		first := domain.MonthStart(c.selectedDate)
+		return first, first.AddDate(0, 1, -1)
+	case RangeReport:
+		return c.rangeFirst, c.rangeLast
+	}
+	day := domain.DayStart(c.selectedDate)
+	return day, day
 }
 
 func (c *ReportsComponent) SetSelectedDate(date time.Time) {
@@ -94,18 +257,27 @@ func (c *ReportsComponent) GetSelectedDate() time.Time {
 }
 
 func (c *ReportsComponent) NextDate() {
-	if c.reportType == DailyReport {
-		c.selectedDate = c.selectedDate.AddDate(0, 0, 1)
-	} else {
-		c.selectedDate = c.selectedDate.AddDate(0, 0, 7)
-	}
+	c.shift(1)
 }
 
 func (c *ReportsComponent) PrevDate() {
-	if c.reportType == DailyReport {
-		c.selectedDate = c.selectedDate.AddDate(0, 0, -1)
-	} else {
-		c.selectedDate = c.selectedDate.AddDate(0, 0, -7)
+	c.shift(-1)
+}
+
+// shift moves by one report period; a custom range moves by its own length.
+func (c *ReportsComponent) shift(direction int) {
+	c.groupedReport = nil
+	switch c.reportType {
+	case DailyReport:
+		c.selectedDate = c.selectedDate.AddDate(0, 0, direction)
+	case WeeklyReport:
+		c.selectedDate = c.selectedDate.AddDate(0, 0, 7*direction)
+	case MonthlyReport:
+		c.selectedDate = domain.MonthStart(c.selectedDate).AddDate(0, direction, 0)
+	case RangeReport:
+		days := int(c.rangeLast.Sub(c.rangeFirst).Hours()/24+0.5) + 1
+		c.rangeFirst = c.rangeFirst.AddDate(0, 0, days*direction)
+		c.rangeLast = c.rangeLast.AddDate(0, 0, days*direction)
 	}
 }
 
@@ -115,10 +287,13 @@ func (c *ReportsComponent) SetSize(width, height int) {
 }
 
 func (c *ReportsComponent) View() string {
-	if c.reportType == DailyReport {
+	switch c.reportType {
+	case DailyReport:
 		return c.renderDailyReport()
+	case WeeklyReport:
+		return c.renderWeeklyReport()
 	}
-	return c.renderWeeklyReport()
+	return c.renderRangeReport()
 }
 
 func (c *ReportsComponent) renderDailyReport() string {
@@ -133,50 +308,22 @@ func (c *ReportsComponent) renderDailyReport() string {
 	if c.dailyReport.TotalDuration == 0 {
 		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).
 			Italic(true).Render("No time tracked on this day")
-		return content
+		return content + c.renderTarget(0, c.targets.ForDay(c.dailyReport.Date))
 	}
 
-	projects := make([]string, 0, len(c.dailyReport.ByProject))
-	for projectID := range c.dailyReport.ByProject {
-		projects = append(projects, projectID)
-	}
-	sort.Slice(projects, func(i, j int) bool {
-		return c.dailyReport.ByProject[projects[i]].TotalDuration >
-			c.dailyReport.ByProject[projects[j]].TotalDuration
-	})
-
-	for _, projectID := range projects {
-		projectSummary := c.dailyReport.ByProject[projectID]
-		projectLine := fmt.Sprintf("%s - %s",
-			projectSummary.ProjectName,
-			domain.FormatDuration(projectSummary.TotalDuration))
-		content += reportProjectStyle.Render(projectLine) + "\n"
-
-		tasks := make([]string, 0, len(projectSummary.ByTask))
-		for taskID := range projectSummary.ByTask {
-			tasks = append(tasks, taskID)
-		}
-		sort.Slice(tasks, func(i, j int) bool {
-			return projectSummary.ByTask[tasks[i]].Duration >
-				projectSummary.ByTask[tasks[j]].Duration
-		})
-
-		for _, taskID := range tasks {
-			taskSummary := projectSummary.ByTask[taskID]
-			taskLine := fmt.Sprintf("  • %s - %s",
-				taskSummary.TaskName,
-				domain.FormatDuration(taskSummary.Duration))
-			content += reportTaskStyle.Render(taskLine) + "\n"
-		}
-
-		content += "\n"
+	if c.grouping[0] != domain.GroupNone {
+		content += c.renderGroups("") + "\n"
+	} else {
+		content += renderProjectBreakdown(c.dailyReport.ByProject, c.dailyReport.ByClient, "", true)
 	}
 
-	totalLine := fmt.Sprintf("Total: %s", domain.FormatDuration(c.dailyReport.TotalDuration))
+	totalLine := fmt.Sprintf("Total: %s", domain.FormatTotal(c.dailyReport.TotalDuration, c.dailyReport.Running))
 	content += reportTotalStyle.Render(totalLine)
+	content += renderBilling(c.dailyReport.Billing)
+	content += c.renderTarget(c.dailyReport.TotalDuration, c.targets.ForDay(c.dailyReport.Date))
 
 	helpText := "\n\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).
-		Render("←/→: prev/next day | t: toggle report type")
+		Render("←/→: prev/next day | ↑/↓: scroll | t: toggle report type | c: custom range | f: filter | g/G: group | E: export")
 
 	return content + helpText
 }
@@ -195,7 +342,7 @@ func (c *ReportsComponent) renderWeeklyReport() string {
 	if c.weeklyReport.TotalDuration == 0 {
 		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).
 			Italic(true).Render("No time tracked this week")
-		return content
+		return content + c.renderTarget(0, c.targets.ForWeek(c.weeklyReport.StartDate))
 	}
 
 	content += lipgloss.NewStyle().Bold(true).Render("Daily Breakdown:") + "\n"
@@ -220,20 +367,210 @@ func (c *ReportsComponent) renderWeeklyReport() string {
 		}
 	}
 
-	content += "\n" + lipgloss.NewStyle().Bold(true).Render("By Project:") + "\n"
+	if c.grouping[0] != domain.GroupNone {
+		content += "\n" + c.renderGroups("  ")
+	} else {
+		heading := "By Project:"
+		if domain.HasClients(c.weeklyReport.ByClient) {
+			heading = "By Client:"
+		}
+		content += "\n" + lipgloss.NewStyle().Bold(true).Render(heading) + "\n"
+
+		content += renderProjectBreakdown(c.weeklyReport.ByProject, c.weeklyReport.ByClient, "  ", false)
+	}
+
+	totalLine := fmt.Sprintf("\nTotal: %s", domain.FormatTotal(c.weeklyReport.TotalDuration, c.weeklyReport.Running))
+	content += reportTotalStyle.Render(totalLine)
+	content += renderBilling(c.weeklyReport.Billing)
+	content += c.renderTarget(c.weeklyReport.TotalDuration, c.targets.ForWeek(c.weeklyReport.StartDate))
+
+	helpText := "\n\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+		Render("←/→: prev/next week | ↑/↓: scroll | t: toggle report type | c: custom range | f: filter | g/G: group | E: export")
+
+	return content + helpText
+}
+
+func (c *ReportsComponent) renderRangeReport() string {
+	if c.rangeReport == nil {
+		return lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+			Render("Loading report...")
+	}
+
+	title := c.rangeReport.Start.Format("January 2006")
+	navHelp := "←/→: prev/next month"
+	if c.reportType == RangeReport {
+		title = fmt.Sprintf("%s - %s",
+			c.rangeReport.Start.Format("Jan 2"),
+			c.rangeReport.End.AddDate(0, 0, -1).Format("Jan 2, 2006"))
+		navHelp = "←/→: shift range"
+	}
+	content := reportHeaderStyle.Render(title) + "\n\n"
 
-	projects := make([]string, 0, len(c.weeklyReport.ByProject))
-	for projectID := range c.weeklyReport.ByProject {
+	if c.rangeReport.TotalDuration == 0 {
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+			Italic(true).Render("No time tracked in this period")
+		return content + c.renderTarget(0, c.targets.ForRange(c.rangeReport.Start, c.rangeReport.End))
+	}
+
+	content += lipgloss.NewStyle().Bold(true).Render("Weekly Breakdown:") + "\n"
+	for _, week := range c.rangeReport.Weeks() {
+		label := fmt.Sprintf("  %s - %s", week.First.Format("Jan 2"), week.Last.Format("Jan 2"))
+		if week.Duration == 0 {
+			content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(label+": -") + "\n"
+			continue
+		}
+		content += fmt.Sprintf("%s: %s %s\n", label,
+			domain.FormatDuration(week.Duration),
+			c.createBar(week.Duration, c.rangeReport.TotalDuration, 20))
+	}
+
+	// Long spans list only the days with tracked time.
+	var longest time.Duration
+	for _, duration := range c.rangeReport.ByDay {
+		longest = max(longest, duration)
+	}
+	content += "\n" + lipgloss.NewStyle().Bold(true).Render("Daily Breakdown:") + "\n"
+	for day := c.rangeReport.Start; day.Before(c.rangeReport.End); day = day.AddDate(0, 0, 1) {
+		duration := c.rangeReport.ByDay[day.Format(domain.DayKeyLayout)]
+		if duration == 0 {
+			continue
+		}
+		content += fmt.Sprintf("  %s: %s %s\n", day.Format("Mon Jan 2"),
+			domain.FormatDuration(duration),
+			c.createBar(duration, longest, 20))
+	}
+
+	if c.grouping[0] != domain.GroupNone {
+		content += "\n" + c.renderGroups("  ")
+	} else {
+		heading := "By Project:"
+		if domain.HasClients(c.rangeReport.ByClient) {
+			heading = "By Client:"
+		}
+		content += "\n" + lipgloss.NewStyle().Bold(true).Render(heading) + "\n"
+		content += renderProjectBreakdown(c.rangeReport.ByProject, c.rangeReport.ByClient, "  ", false)
+	}
+
+	totalLine := fmt.Sprintf("\nTotal: %s", domain.FormatTotal(c.rangeReport.TotalDuration, c.rangeReport.Running))
+	content += reportTotalStyle.Render(totalLine)
+	content += renderBilling(c.rangeReport.Billing)
+	content += c.renderTarget(c.rangeReport.TotalDuration, c.targets.ForRange(c.rangeReport.Start, c.rangeReport.End))
+
+	helpText := "\n\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+		Render(navHelp+" | ↑/↓: scroll | t: toggle report type | c: custom range | f: filter | g/G: group | E: export")
+
+	return content + helpText
+}
+
+// renderTarget compares the report's total with the time expected in its
+// period, followed by the overtime balance.
+func (c *ReportsComponent) renderTarget(total, target time.Duration) string {
+	if !c.ownTime || !c.targets.IsSet() {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(9)
+	content := "\n\n"
+	if target > 0 {
+		content += labelStyle.Render("Target:") + renderProgress(total, target, 20)
+	} else {
+		content += labelStyle.Render("Target:") + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("none in this period")
+	}
+	if c.progress != nil {
+		content += "\n" + labelStyle.Render("Balance:") + renderBalance(c.progress)
+	}
+	return content
+}
+
+// renderGroups lists the grouped breakdown under a heading naming its
+// dimensions, e.g. "By Tag › Project:".
+func (c *ReportsComponent) renderGroups(indent string) string {
+	heading := "By " + groupingLabel(c.grouping[0])
+	if c.grouping[1] != domain.GroupNone {
+		heading += " › " + groupingLabel(c.grouping[1])
+	}
+	content := lipgloss.NewStyle().Bold(true).Render(heading+":") + "\n"
+
+	if c.groupedReport == nil {
+		return content + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(indent+"Loading...") + "\n"
+	}
+
+	for _, group := range c.groupedReport.Groups {
+		line := fmt.Sprintf("%s%s - %s %s", indent, group.Name,
+			domain.FormatDuration(group.Duration),
+			c.createBar(group.Duration, c.groupedReport.Total, 20))
+		content += reportProjectStyle.Render(line) + "\n"
+		for _, child := range group.Children {
+			childLine := fmt.Sprintf("%s  • %s - %s", indent, child.Name, domain.FormatDuration(child.Duration))
+			content += reportTaskStyle.Render(childLine) + "\n"
+		}
+	}
+	return content
+}
+
+func groupingLabel(g domain.GroupBy) string {
+	name := g.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// renderBilling shows the billable split and earnings below the total; it is
+// omitted when nothing was billable.
+func renderBilling(billing domain.Billing) string {
+	if billing.BillableDuration == 0 {
+		return ""
+	}
+
+	content := "\n" + reportBillingStyle.Render(fmt.Sprintf("Billable: %s | Non-billable: %s",
+		domain.FormatDuration(billing.BillableDuration),
+		domain.FormatDuration(billing.NonBillableDuration)))
+	if len(billing.Earnings) > 0 {
+		content += "\n" + reportEarningsStyle.Render("Earned: "+billing.Earnings.String())
+	}
+	return content
+}
+
+// renderProjectBreakdown lists projects and their tasks by duration, grouped
+// under their clients when any project has one.
+func renderProjectBreakdown(byProject map[string]*domain.ProjectSummary, byClient map[string]*domain.ClientSummary, indent string, spaced bool) string {
+	if !domain.HasClients(byClient) {
+		return renderProjects(byProject, indent, spaced)
+	}
+
+	clients := make([]string, 0, len(byClient))
+	for clientID := range byClient {
+		clients = append(clients, clientID)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return byClient[clients[i]].TotalDuration > byClient[clients[j]].TotalDuration
+	})
+
+	var content string
+	for _, clientID := range clients {
+		clientSummary := byClient[clientID]
+		clientLine := fmt.Sprintf("%s%s - %s",
+			indent,
+			clientSummary.ClientName,
+			domain.FormatDuration(clientSummary.TotalDuration))
+		content += reportClientStyle.Render(clientLine) + "\n"
+		content += renderProjects(clientSummary.ByProject, indent+"  ", spaced)
+	}
+	return content
+}
+
+func renderProjects(byProject map[string]*domain.ProjectSummary, indent string, spaced bool) string {
+	projects := make([]string, 0, len(byProject))
+	for projectID := range byProject {
 		projects = append(projects, projectID)
 	}
 	sort.Slice(projects, func(i, j int) bool {
-		return c.weeklyReport.ByProject[projects[i]].TotalDuration >
-			c.weeklyReport.ByProject[projects[j]].TotalDuration
+		return byProject[projects[i]].TotalDuration > byProject[projects[j]].TotalDuration
 	})
 
+	var content string
 	for _, projectID := range projects {
-		projectSummary := c.weeklyReport.ByProject[projectID]
-		projectLine := fmt.Sprintf("  %s - %s",
+		projectSummary := byProject[projectID]
+		projectLine := fmt.Sprintf("%s%s - %s",
+			indent,
 			projectSummary.ProjectName,
 			domain.FormatDuration(projectSummary.TotalDuration))
 		content += reportProjectStyle.Render(projectLine) + "\n"
@@ -249,20 +586,18 @@ func (c *ReportsComponent) renderWeeklyReport() string {
 
 		for _, taskID := range tasks {
 			taskSummary := projectSummary.ByTask[taskID]
-			taskLine := fmt.Sprintf("    • %s - %s",
+			taskLine := fmt.Sprintf("%s  • %s - %s",
+				indent,
 				taskSummary.TaskName,
 				domain.FormatDuration(taskSummary.Duration))
 			content += reportTaskStyle.Render(taskLine) + "\n"
 		}
-	}
 
-	totalLine := fmt.Sprintf("\nTotal: %s", domain.FormatDuration(c.weeklyReport.TotalDuration))
-	content += reportTotalStyle.Render(totalLine)
-
-	helpText := "\n\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).
-		Render("←/→: prev/next week | t: toggle report type")
-
-	return content + helpText
+		if spaced {
+			content += "\n"
+		}
+	}
+	return content
 }
 
 func (c *ReportsComponent) createBar(value, max time.Duration, maxWidth int) string {
@@ -2,15 +2,18 @@ package components
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"main/internal/ui/theme"
 )
 
 type StatusBarComponent struct {
-	message string
-	msgType StatusType
-	width   int
+	message      string
+	msgType      StatusType
+	undoDeadline time.Time
+	pendingSync  int
+	width        int
 }
 
 type StatusType int
@@ -67,7 +70,27 @@ func (c *StatusBarComponent) View() string {
 		style = style.Width(c.width)
 	}
 
-	return style.Render(c.message)
+	message := c.message
+	if remaining := time.Until(c.undoDeadline); remaining > 0 {
+		message += fmt.Sprintf(" | u: undo (%ds)", int(remaining.Seconds())+1)
+	}
+	if c.pendingSync > 0 {
+		message += fmt.Sprintf(" | offline: %d queued", c.pendingSync)
+	}
+
+	return style.Render(message)
+}
+
+func (c *StatusBarComponent) SetUndoDeadline(deadline time.Time) {
+	c.undoDeadline = deadline
+}
+
+func (c *StatusBarComponent) SetPendingSync(count int) {
+	c.pendingSync = count
+}
+
+func (c *StatusBarComponent) ClearUndo() {
+	c.undoDeadline = time.Time{}
 }
 
 func (c *StatusBarComponent) SetError(err error) {
@@ -0,0 +1,292 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"main/internal/api"
+	"main/internal/domain"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// timelineSlot is the time each line of the timeline stands for.
+	timelineSlot = 30 * time.Minute
+	// timelineBlockWidth is the number of cells a fully tracked slot fills.
+	timelineBlockWidth = 6
+	// TimelineWidth is the width the timeline takes next to the entries,
+	// including TimelinePadding.
+	TimelineWidth = 40
+	// TimelinePadding is the space between the entries and the timeline.
+	TimelinePadding = 2
+	// timelineLabelWidth is what is left of a line for the label after the
+	// hour, the axis, the block and the space before the label.
+	timelineLabelWidth = TimelineWidth - TimelinePadding - 8 - timelineBlockWidth
+)
+
+var (
+	timelineAxisStyle = lipgloss.NewStyle().
+				Foreground(theme.Overlay0Color)
+
+	timelineHourStyle = lipgloss.NewStyle().
+				Foreground(theme.Subtext0Color)
+
+	timelineEmptyStyle = lipgloss.NewStyle().
+				Foreground(theme.Surface1Color)
+
+	timelineLabelStyle = lipgloss.NewStyle().
+				Foreground(theme.TextColor)
+
+	timelineSelectedStyle = lipgloss.NewStyle().
+				Foreground(theme.MauveColor).
+				Bold(true)
+
+	timelineNoProjectColor = theme.Overlay1Color
+)
+
+// TimelineComponent draws the entries of a day as blocks on a vertical hour
+// axis, each in the color of its project. The running timer is drawn from
+// the timer state up to the time of the last tick, so it grows live.
+type TimelineComponent struct {
+	timerState *domain.TimerState
+	entries    []api.TimeEntry
+	colors     map[string]string
+	date       time.Time
+	hours      domain.WorkingHours
+	selectedID string
+	now        time.Time
+	height     int
+}
+
+// timelineBlock is an entry as drawn: its span within the day and color.
+type timelineBlock struct {
+	entry   *api.TimeEntry
+	start   time.Time
+	end     time.Time
+	color   lipgloss.TerminalColor
+	running bool
+}
+
+func NewTimelineComponent(state *domain.TimerState) *TimelineComponent {
+	return &TimelineComponent{
+		timerState: state,
+		colors:     make(map[string]string),
+		date:       time.Now(),
+		hours:      domain.DefaultWorkingHours(),
+		now:        time.Now(),
+	}
+}
+
+func (c *TimelineComponent) SetEntries(entries []api.TimeEntry, date time.Time) {
+	c.entries = entries
+	c.date = date
+}
+
+// SetProjects picks up the colors of the projects.
+func (c *TimelineComponent) SetProjects(projects []api.Project) {
+	c.colors = make(map[string]string, len(projects))
+	for _, project := range projects {
+		c.colors[project.ID] = project.Color
+	}
+}
+
+// SetWorkingHours sets the hours always shown on the axis; earlier and
+// later entries extend it.
+func (c *TimelineComponent) SetWorkingHours(hours domain.WorkingHours) {
+	c.hours = hours
+}
+
+// SetSelected highlights the entry with entryID; empty highlights none.
+func (c *TimelineComponent) SetSelected(entryID string) {
+	c.selectedID = entryID
+}
+
+// SetNow moves the end of the running timer to now.
+func (c *TimelineComponent) SetNow(now time.Time) {
+	c.now = now
+}
+
+func (c *TimelineComponent) SetHeight(height int) {
+	c.height = height
+}
+
+func (c *TimelineComponent) color(projectID *string) lipgloss.TerminalColor {
+	if projectID == nil {
+		return timelineNoProjectColor
+	}
+	if color, ok := c.colors[*projectID]; ok && strings.HasPrefix(color, "#") {
+		return lipgloss.Color(color)
+	}
+	return theme.BlueColor
+}
+
+// blocks returns the entries clipped to the day. The running entry comes
+// from the timer state rather than the loaded entries, so that it shows even
+// when it started after they were loaded.
+func (c *TimelineComponent) blocks(dayStart, dayEnd time.Time) []timelineBlock {
+	var blocks []timelineBlock
+	add := func(entry *api.TimeEntry, start, end time.Time, running bool) {
+		start, end = start.Local(), end.Local()
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if !end.After(start) {
+			return
+		}
+		blocks = append(blocks, timelineBlock{
+			entry:   entry,
+			start:   start,
+			end:     end,
+			color:   c.color(entry.ProjectID),
+			running: running,
+		})
+	}
+
+	state := c.timerState
+	for i := range c.entries {
+		entry := &c.entries[i]
+		if entry.TimeInterval.End != nil {
+			add(entry, entry.TimeInterval.Start, *entry.TimeInterval.End, false)
+		} else if !state.IsRunning || state.CurrentEntry == nil || state.CurrentEntry.ID != entry.ID {
+			add(entry, entry.TimeInterval.Start, c.now, false)
+		}
+	}
+	if state.IsRunning && state.CurrentEntry != nil {
+		running := *state.CurrentEntry
+		running.Description = state.Description
+		running.ProjectID = state.ProjectID
+		add(&running, state.StartTime, c.now, true)
+	}
+	return blocks
+}
+
+// axis returns the whole hours to draw: the working hours, stretched to
+// take in every block.
+func (c *TimelineComponent) axis(dayStart time.Time, blocks []timelineBlock) (first, last time.Time) {
+	first, last = c.hours.On(dayStart)
+	for _, block := range blocks {
+		if block.start.Before(first) {
+			first = block.start
+		}
+		if block.end.After(last) {
+			last = block.end
+		}
+	}
+
+	first = dayStart.Add(first.Sub(dayStart).Truncate(time.Hour))
+	if rest := last.Sub(dayStart) % time.Hour; rest != 0 {
+		last = last.Add(time.Hour - rest)
+	}
+	return first, last
+}
+
+// dominant returns the block covering most of the slot from start to end,
+// and how much of the slot it covers.
+func dominant(blocks []timelineBlock, start, end time.Time) (*timelineBlock, float64) {
+	var best *timelineBlock
+	var bestCover time.Duration
+	for i := range blocks {
+		from := latest(blocks[i].start, start)
+		to := earliest(blocks[i].end, end)
+		if cover := to.Sub(from); cover > bestCover {
+			best = &blocks[i]
+			bestCover = cover
+		}
+	}
+	return best, float64(bestCover) / float64(end.Sub(start))
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func (c *TimelineComponent) View() string {
+	dayStart := domain.DayStart(c.date)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	blocks := c.blocks(dayStart, dayEnd)
+	first, last := c.axis(dayStart, blocks)
+
+	labeled := make(map[*api.TimeEntry]bool)
+
+	var lines []string
+	for slot := first; slot.Before(last); slot = slot.Add(timelineSlot) {
+		hour := "     "
+		if slot.Sub(dayStart)%time.Hour == 0 {
+			hour = slot.Format("15:04")
+		}
+		line := timelineHourStyle.Render(hour) + timelineAxisStyle.Render(" ┤")
+
+		block, cover := dominant(blocks, slot, slot.Add(timelineSlot))
+		if block == nil {
+			lines = append(lines, line+timelineEmptyStyle.Render(strings.Repeat("·", timelineBlockWidth)))
+			continue
+		}
+
+		filled := min(max(int(cover*timelineBlockWidth+0.5), 1), timelineBlockWidth)
+		line += lipgloss.NewStyle().Foreground(block.color).Render(strings.Repeat("█", filled)) +
+			timelineEmptyStyle.Render(strings.Repeat("·", timelineBlockWidth-filled))
+
+		if !labeled[block.entry] {
+			labeled[block.entry] = true
+			line += " " + c.label(block)
+		}
+		lines = append(lines, line)
+	}
+
+	if c.height > 0 && len(lines) > c.height {
+		lines = c.scroll(lines, first, blocks)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// label names the block at the first line it fills, with the running
+// timer's elapsed time. It is cut to the cells left on the line, so that it
+// never wraps and every line stays one slot.
+func (c *TimelineComponent) label(block *timelineBlock) string {
+	text := block.entry.Description
+	if text == "" {
+		text = "(no description)"
+	}
+	if block.running {
+		text = "▶ " + text + " " + domain.FormatDuration(c.now.Sub(c.timerState.StartTime).Round(time.Second))
+	}
+	text = runewidth.Truncate(text, timelineLabelWidth, "…")
+
+	if block.entry.ID == c.selectedID {
+		return timelineSelectedStyle.Render(text)
+	}
+	return timelineLabelStyle.Render(text)
+}
+
+// scroll keeps the lines around the selected entry, or the running timer,
+// when they don't all fit.
+func (c *TimelineComponent) scroll(lines []string, first time.Time, blocks []timelineBlock) []string {
+	focus := c.now
+	for _, block := range blocks {
+		if block.entry.ID == c.selectedID {
+			focus = block.start
+			break
+		}
+	}
+
+	index := int(focus.Sub(first) / timelineSlot)
+	start := min(max(index-c.height/2, 0), len(lines)-c.height)
+	return lines[start : start+c.height]
+}
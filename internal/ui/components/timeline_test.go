@@ -0,0 +1,38 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"main/internal/api"
+	"main/internal/domain"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTimelineLinesFitWidth(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	end := day.Add(10 * time.Hour)
+	long := strings.Repeat("Quarterly planning 計画 ", 4)
+
+	state := domain.NewTimerState()
+	state.IsRunning = true
+	state.CurrentEntry = &api.TimeEntry{ID: "running"}
+	state.Description = long
+	state.StartTime = day.Add(11 * time.Hour)
+
+	timeline := NewTimelineComponent(state)
+	timeline.SetEntries([]api.TimeEntry{{
+		ID:           "done",
+		Description:  long,
+		TimeInterval: api.TimeInterval{Start: day.Add(9 * time.Hour), End: &end},
+	}}, day)
+	timeline.SetNow(day.Add(12 * time.Hour))
+
+	for i, line := range strings.Split(timeline.View(), "\n") {
+		if width := lipgloss.Width(line); width > TimelineWidth-TimelinePadding {
+			t.Errorf("line %d is %d cells wide, want at most %d: %q", i, width, TimelineWidth-TimelinePadding, line)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"main/internal/api"
@@ -18,8 +19,23 @@ const (
 	ViewThisWeek
 )
 
+// entryRow is a line of the list: an entry, or a gap between entries.
+type entryRow struct {
+	entry *api.TimeEntry
+	gap   *domain.EntryGap
+}
+
+func (r entryRow) start() time.Time {
+	if r.gap != nil {
+		return r.gap.Start
+	}
+	return r.entry.TimeInterval.Start
+}
+
 type EntriesComponent struct {
 	entries       []api.TimeEntry
+	analysis      *domain.DayAnalysis
+	rows          []entryRow
 	projects      map[string]string
 	tasks         map[string]string
 	tags          map[string]string
@@ -47,6 +63,13 @@ var (
 	entryDurationStyle = lipgloss.NewStyle().
 				Foreground(theme.GreenColor).
 				Bold(true)
+
+	entryGapStyle = lipgloss.NewStyle().
+			Foreground(theme.PeachColor).
+			Italic(true)
+
+	entryOverlapStyle = lipgloss.NewStyle().
+				Foreground(theme.RedColor)
 )
 
 func NewEntriesComponent() *EntriesComponent {
@@ -59,12 +82,43 @@ func NewEntriesComponent() *EntriesComponent {
 	}
 }
 
+// SetEntries replaces the entries and drops the analysis of the previous
+// ones.
 func (c *EntriesComponent) SetEntries(entries []api.TimeEntry) {
 	c.entries = entries
-	if c.selectedIndex >= len(entries) {
-		c.selectedIndex = 0
+	c.analysis = nil
+	c.buildRows()
+}
+
+// SetAnalysis shows the gaps between the entries inline and marks the
+// entries that overlap; nil hides them.
+func (c *EntriesComponent) SetAnalysis(analysis *domain.DayAnalysis) {
+	c.analysis = analysis
+	c.buildRows()
+}
+
+func (c *EntriesComponent) GetAnalysis() *domain.DayAnalysis {
+	return c.analysis
+}
+
+// buildRows lists the entries with the gaps between them. The gaps are put
+// in place by sorting everything by start, newest first like the entries
+// come from the API.
+func (c *EntriesComponent) buildRows() {
+	c.rows = c.rows[:0]
+	for i := range c.entries {
+		c.rows = append(c.rows, entryRow{entry: &c.entries[i]})
 	}
-	if len(entries) > 0 && c.selectedIndex < 0 {
+	if c.analysis != nil {
+		for i := range c.analysis.Gaps {
+			c.rows = append(c.rows, entryRow{gap: &c.analysis.Gaps[i]})
+		}
+		sort.SliceStable(c.rows, func(i, j int) bool {
+			return c.rows[i].start().After(c.rows[j].start())
+		})
+	}
+
+	if c.selectedIndex >= len(c.rows) || c.selectedIndex < 0 {
 		c.selectedIndex = 0
 	}
 }
@@ -106,7 +160,7 @@ func (c *EntriesComponent) ToggleViewMode() {
 }
 
 func (c *EntriesComponent) NextItem() {
-	if c.selectedIndex < len(c.entries)-1 {
+	if c.selectedIndex < len(c.rows)-1 {
 		c.selectedIndex++
 	}
 }
@@ -118,10 +172,19 @@ func (c *EntriesComponent) PrevItem() {
 }
 
 func (c *EntriesComponent) GetSelectedEntry() *api.TimeEntry {
-	if len(c.entries) == 0 || c.selectedIndex < 0 || c.selectedIndex >= len(c.entries) {
+	if c.selectedIndex < 0 || c.selectedIndex >= len(c.rows) {
+		return nil
+	}
+	return c.rows[c.selectedIndex].entry
+}
+
+// GetSelectedGap returns the gap under the cursor, nil when an entry is
+// selected.
+func (c *EntriesComponent) GetSelectedGap() *domain.EntryGap {
+	if c.selectedIndex < 0 || c.selectedIndex >= len(c.rows) {
 		return nil
 	}
-	return &c.entries[c.selectedIndex]
+	return c.rows[c.selectedIndex].gap
 }
 
 func (c *EntriesComponent) GetSelectedDate() time.Time {
@@ -145,7 +208,7 @@ func (c *EntriesComponent) PrevDate() {
 }
 
 func (c *EntriesComponent) View() string {
-	if len(c.entries) == 0 {
+	if len(c.rows) == 0 {
 		return c.renderEmpty()
 	}
 
@@ -153,32 +216,38 @@ func (c *EntriesComponent) View() string {
 	maxVisible := 8
 
 	visibleStart := 0
-	visibleEnd := len(c.entries)
+	visibleEnd := len(c.rows)
 
-	if len(c.entries) > maxVisible {
+	if len(c.rows) > maxVisible {
 		if c.selectedIndex > maxVisible/2 {
 			visibleStart = c.selectedIndex - maxVisible/2
 		}
 		visibleEnd = visibleStart + maxVisible
-		if visibleEnd > len(c.entries) {
-			visibleEnd = len(c.entries)
+		if visibleEnd > len(c.rows) {
+			visibleEnd = len(c.rows)
 			visibleStart = max(visibleEnd - maxVisible, 0)
 		}
 	}
 
 	for i := visibleStart; i < visibleEnd; i++ {
-		entry := c.entries[i]
-		entryView := c.formatEntry(&entry, i == c.selectedIndex)
-		content += entryView + "\n"
+		row := c.rows[i]
+		if row.gap != nil {
+			content += c.formatGap(row.gap, i == c.selectedIndex) + "\n"
+			continue
+		}
+		content += c.formatEntry(row.entry, i == c.selectedIndex) + "\n"
 	}
 
 	helpText := ""
-	if c.viewMode == ViewToday {
+	if c.GetSelectedGap() != nil {
 		helpText = lipgloss.NewStyle().Foreground(theme.Subtext0Color).
-			Render(fmt.Sprintf("↑/↓: navigate | ←/→ or h/l: prev/next day | t: toggle view | s: start timer (%d entries)", len(c.entries)))
+			Render(fmt.Sprintf("↑/↓: navigate | ←/→ or h/l: prev/next day | t: toggle view | n/enter: fill gap | E: export | I: import (%d entries)", len(c.entries)))
+	} else if c.viewMode == ViewToday {
+		helpText = lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+			Render(fmt.Sprintf("↑/↓: navigate | ←/→ or h/l: prev/next day | t: toggle view | s: start timer | n: new | e: edit | T: trim overlap | D: delete | E: export | I: import (%d entries)", len(c.entries)))
 	} else {
 		helpText = lipgloss.NewStyle().Foreground(theme.Subtext0Color).
-			Render(fmt.Sprintf("↑/↓: navigate | t: toggle view | s: start timer (%d entries)", len(c.entries)))
+			Render(fmt.Sprintf("↑/↓: navigate | t: toggle view | s: start timer | n: new | e: edit | D: delete | E: export | I: import (%d entries)", len(c.entries)))
 	}
 
 	return content + "\n" + helpText
@@ -194,7 +263,10 @@ func (c *EntriesComponent) renderEmpty() string {
 		modeStr = "this week"
 	}
 
-	return emptyStyle.Render(fmt.Sprintf("No time entries for %s", modeStr))
+	helpText := lipgloss.NewStyle().Foreground(theme.Subtext0Color).
+		Render("n: log a new entry | I: import entries | t: toggle view")
+
+	return emptyStyle.Render(fmt.Sprintf("No time entries for %s", modeStr)) + "\n\n" + helpText
 }
 
 func (c *EntriesComponent) formatEntry(entry *api.TimeEntry, selected bool) string {
@@ -210,8 +282,14 @@ func (c *EntriesComponent) formatEntry(entry *api.TimeEntry, selected bool) stri
 		line2 += " • " + projectName + taskName
 	}
 	line2 += tagsStr
+	if entry.Billable {
+		line2 += " " + lipgloss.NewStyle().Foreground(theme.YellowColor).Render("$")
+	}
 
 	content := line1 + "\n" + line2
+	for _, overlap := range c.analysis.OverlapsOf(entry.ID) {
+		content += "\n" + c.formatOverlap(entry.ID, overlap)
+	}
 
 	if selected {
 		return entrySelectedStyle.Render("▶ " + content)
@@ -219,6 +297,27 @@ func (c *EntriesComponent) formatEntry(entry *api.TimeEntry, selected bool) stri
 	return entryItemStyle.Render(content)
 }
 
+func (c *EntriesComponent) formatGap(gap *domain.EntryGap, selected bool) string {
+	content := entryGapStyle.Render(fmt.Sprintf("⋯ untracked %s - %s (%s)",
+		gap.Start.Local().Format("15:04"),
+		gap.End.Local().Format("15:04"),
+		domain.FormatDuration(gap.Duration())))
+
+	if selected {
+		return entrySelectedStyle.Render("▶ " + content)
+	}
+	return entryItemStyle.Render(content)
+}
+
+func (c *EntriesComponent) formatOverlap(entryID string, overlap domain.EntryOverlap) string {
+	other := overlap.Other(entryID)
+	return entryOverlapStyle.Render(fmt.Sprintf("⚠ overlaps %q %s - %s (%s)",
+		c.formatDescription(&other),
+		overlap.Start.Local().Format("15:04"),
+		overlap.End.Local().Format("15:04"),
+		domain.FormatDuration(overlap.Duration())))
+}
+
 func (c *EntriesComponent) formatDescription(entry *api.TimeEntry) string {
 	if entry.Description == "" {
 		return "(no description)"
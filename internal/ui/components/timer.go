@@ -1,6 +1,9 @@
 package components
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/charmbracelet/lipgloss"
 	"main/internal/domain"
 	"main/internal/ui/theme"
@@ -8,6 +11,8 @@ import (
 
 type TimerComponent struct {
 	timerState         *domain.TimerState
+	pomodoro           *domain.Pomodoro
+	progress           *domain.TargetProgress
 	projects           map[string]string
 	tags               map[string]string
 	editingDescription bool
@@ -22,11 +27,15 @@ var (
 
 	timerRunningColor = theme.GreenColor
 	timerStoppedColor = theme.Overlay0Color
+
+	pomodoroWorkColor  = theme.RedColor
+	pomodoroBreakColor = theme.TealColor
 )
 
-func NewTimerComponent(state *domain.TimerState) *TimerComponent {
+func NewTimerComponent(state *domain.TimerState, pomodoro *domain.Pomodoro) *TimerComponent {
 	return &TimerComponent{
 		timerState: state,
+		pomodoro:   pomodoro,
 		projects:   make(map[string]string),
 	}
 }
@@ -39,10 +48,20 @@ func (c *TimerComponent) SetTagMap(tags map[string]string) {
 	c.tags = tags
 }
 
+// SetTargetProgress shows progress toward the day's and week's targets;
+// nil hides it.
+func (c *TimerComponent) SetTargetProgress(progress *domain.TargetProgress) {
+	c.progress = progress
+}
+
 func (c *TimerComponent) IsRunning() bool {
 	return c.timerState.IsRunning
 }
 
+func (c *TimerComponent) IsPomodoroActive() bool {
+	return c.pomodoro.Active
+}
+
 func (c *TimerComponent) StartEditingDescription() {
 	if c.timerState.IsRunning {
 		c.editingDescription = true
@@ -96,6 +115,9 @@ func (c *TimerComponent) renderRunningTimer() string {
 		Bold(true)
 
 	content := statusStyle.Render("⏱ RUNNING") + "\n\n"
+	if c.pomodoro.Active {
+		content += c.renderPomodoro() + "\n"
+	}
 	content += lipgloss.NewStyle().Bold(true).Render("Time: ") + durationStr + "\n"
 
 	if c.editingDescription {
@@ -151,6 +173,7 @@ func (c *TimerComponent) renderRunningTimer() string {
 		}
 	}
 
+	content += c.renderTargets()
 	return timerBoxStyle.Render(content)
 }
 
@@ -158,8 +181,58 @@ func (c *TimerComponent) renderStoppedTimer() string {
 	statusStyle := lipgloss.NewStyle().
 		Foreground(timerStoppedColor)
 
+	if c.pomodoro.Active {
+		content := statusStyle.Render("⏸ STOPPED FOR A BREAK") + "\n\n"
+		content += c.renderPomodoro()
+		content += c.renderTargets()
+		return timerBoxStyle.Render(content)
+	}
+
 	content := statusStyle.Render("⏸ STOPPED") + "\n\n"
-	content += lipgloss.NewStyle().Foreground(timerStoppedColor).Render("No timer running")
+	content += lipgloss.NewStyle().Foreground(timerStoppedColor).Render("No timer running") + "\n"
+	content += c.renderTargets()
 
 	return timerBoxStyle.Render(content)
 }
+
+// renderPomodoro shows the countdown of the current interval and where it
+// is in the cycle.
+func (c *TimerComponent) renderPomodoro() string {
+	phaseColor := pomodoroWorkColor
+	if c.pomodoro.Phase.IsBreak() {
+		phaseColor = pomodoroBreakColor
+	}
+	phaseStyle := lipgloss.NewStyle().
+		Foreground(phaseColor).
+		Bold(true)
+
+	remaining := c.pomodoro.Remaining(time.Now()).Round(time.Second)
+	content := phaseStyle.Render("🍅 "+c.pomodoro.Phase.String()) + "  " +
+		lipgloss.NewStyle().Bold(true).Render(domain.FormatDuration(remaining)+" left") + "\n"
+
+	cycle := fmt.Sprintf("Pomodoro %d of %d, %d completed", c.pomodoro.Cycle(), c.pomodoro.Settings.LongBreakEvery, c.pomodoro.Completed)
+	content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(cycle) + "\n"
+	return content
+}
+
+// renderTargets shows the time tracked today and this week, the running
+// timer included, against their targets, and the overtime balance.
+func (c *TimerComponent) renderTargets() string {
+	if c.progress == nil {
+		return ""
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Width(9)
+	day, week := c.progress.Running(c.timerState, time.Now())
+	content := "\n"
+	if c.progress.DayTarget > 0 {
+		content += labelStyle.Render("Today:") + renderProgress(day, c.progress.DayTarget, 20) + "\n"
+	} else {
+		content += labelStyle.Render("Today:") + domain.FormatDuration(day) + ", no target today\n"
+	}
+	if c.progress.WeekTarget > 0 {
+		content += labelStyle.Render("Week:") + renderProgress(week, c.progress.WeekTarget, 20) + "\n"
+	}
+	content += labelStyle.Render("Balance:") + renderBalance(c.progress) + "\n"
+	return content
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"main/internal/api"
+	"main/internal/ui/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type WorkspacePickerComponent struct {
+	workspaces  []api.Workspace
+	currentID   string
+	selected    int
+	width       int
+	height      int
+	loadingText string
+}
+
+func NewWorkspacePicker() *WorkspacePickerComponent {
+	return &WorkspacePickerComponent{
+		loadingText: "Loading workspaces...",
+	}
+}
+
+func (c *WorkspacePickerComponent) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+// SetWorkspaces fills the list and moves the cursor to the active workspace.
+func (c *WorkspacePickerComponent) SetWorkspaces(workspaces []api.Workspace, currentID string) {
+	c.workspaces = workspaces
+	c.currentID = currentID
+	c.selected = 0
+	for i, ws := range workspaces {
+		if ws.ID == currentID {
+			c.selected = i
+			break
+		}
+	}
+	if len(workspaces) == 0 {
+		c.loadingText = "No workspaces available"
+	}
+}
+
+func (c *WorkspacePickerComponent) MoveUp() {
+	if c.selected > 0 {
+		c.selected--
+	}
+}
+
+func (c *WorkspacePickerComponent) MoveDown() {
+	if c.selected < len(c.workspaces)-1 {
+		c.selected++
+	}
+}
+
+func (c *WorkspacePickerComponent) GetSelectedWorkspace() *api.Workspace {
+	if c.selected < 0 || c.selected >= len(c.workspaces) {
+		return nil
+	}
+	return &c.workspaces[c.selected]
+}
+
+func (c *WorkspacePickerComponent) View() string {
+	title := selectorTitleStyle.Render("Switch Workspace")
+	content := title + "\n\n"
+
+	if len(c.workspaces) == 0 {
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render(c.loadingText) + "\n\n"
+		content += lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("esc: cancel")
+		return selectorBoxStyle.Width(c.width - 4).Render(content)
+	}
+
+	for i, ws := range c.workspaces {
+		line := ws.Name
+		if ws.ID == c.currentID {
+			line += " (current)"
+		}
+
+		if i == c.selected {
+			content += selectorSelectedStyle.Render("▶ "+line) + "\n"
+		} else {
+			content += selectorItemStyle.Render(line) + "\n"
+		}
+	}
+
+	content += "\n" + lipgloss.NewStyle().Foreground(theme.Subtext0Color).Render("↑/↓: navigate | enter: switch | esc: cancel")
+
+	return selectorBoxStyle.Width(c.width - 4).Render(content)
+}
@@ -6,10 +6,16 @@ type KeyMap struct {
 	SwitchToTimer     key.Binding
 	SwitchToEntries   key.Binding
 	SwitchToReports   key.Binding
+	SwitchWorkspace   key.Binding
 	StartTimer        key.Binding
 	StopTimer         key.Binding
 	SelectProject     key.Binding
 	EditDescription   key.Binding
+	EditEntry         key.Binding
+	NewEntry          key.Binding
+	DeleteEntry       key.Binding
+	TrimOverlap       key.Binding
+	Undo              key.Binding
 	Refresh           key.Binding
 	Quit              key.Binding
 	Up                key.Binding
@@ -20,6 +26,13 @@ type KeyMap struct {
 	Back              key.Binding
 	Help              key.Binding
 	ToggleView        key.Binding
+	CustomRange       key.Binding
+	ReportFilter      key.Binding
+	Group             key.Binding
+	Subgroup          key.Binding
+	Export            key.Binding
+	Import            key.Binding
+	Pomodoro          key.Binding
 	Space             key.Binding
 }
 
@@ -37,6 +50,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("3"),
 			key.WithHelp("3", "reports"),
 		),
+		SwitchWorkspace: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "switch workspace"),
+		),
 		StartTimer: key.NewBinding(
 			key.WithKeys("s"),
 			key.WithHelp("s", "start timer"),
@@ -53,6 +70,26 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "edit description"),
 		),
+		EditEntry: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit entry"),
+		),
+		NewEntry: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new entry"),
+		),
+		DeleteEntry: key.NewBinding(
+			key.WithKeys("D", "delete"),
+			key.WithHelp("D", "delete entry"),
+		),
+		TrimOverlap: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "trim overlap"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
 		Refresh: key.NewBinding(
 			key.WithKeys("r"),
 			key.WithHelp("r", "refresh"),
@@ -93,6 +130,34 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "toggle"),
 		),
+		CustomRange: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "custom range"),
+		),
+		ReportFilter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter reports"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "group reports"),
+		),
+		Subgroup: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "subgroup reports"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "export"),
+		),
+		Import: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "import entries"),
+		),
+		Pomodoro: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "pomodoro mode"),
+		),
 		Space: key.NewBinding(
 			key.WithKeys(" "),
 			key.WithHelp("space", "toggle selection"),
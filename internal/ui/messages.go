@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"main/internal/api"
+	"main/internal/domain"
+	"main/internal/importer"
 )
 
 type ViewType int
@@ -27,41 +29,135 @@ type TimerAlreadyStoppedMsg struct {
 
 type TimeEntriesLoadedMsg struct {
 	Entries []api.TimeEntry
+	Seq     int
 }
 
 type ProjectsLoadedMsg struct {
-	Projects []api.Project
+	WorkspaceID string
+	Projects    []api.Project
+	Tasks       map[string][]api.Task
 }
 
 type TasksLoadedMsg struct {
 	ProjectID string
 	Tasks     []api.Task
+	Seq       int
 }
 
 type TagsLoadedMsg struct {
-	Tags []api.Tag
+	WorkspaceID string
+	Tags        []api.Tag
+}
+
+type ClientsLoadedMsg struct {
+	WorkspaceID string
+	Clients     []api.ClockifyClient
+}
+
+type UsersLoadedMsg struct {
+	WorkspaceID string
+	Users       []api.User
+}
+
+type RecentProjectsLoadedMsg struct {
+	WorkspaceID string
+	ProjectIDs  []string
+}
+
+type WorkspacesLoadedMsg struct {
+	Workspaces []api.Workspace
 }
 
 type DailyReportLoadedMsg struct {
 	Date   time.Time
 	Report any
+	Seq    int
 }
 
 type WeeklyReportLoadedMsg struct {
 	StartDate time.Time
 	Report    any
+	Seq       int
+}
+
+type GroupedReportLoadedMsg struct {
+	Report *domain.GroupedReport
+	Seq    int
+}
+
+// RangeReportLoadedMsg carries a monthly or custom-range report.
+type RangeReportLoadedMsg struct {
+	Report *domain.RangeSummary
+	Seq    int
 }
 
 type ErrorMsg struct {
 	Err error
 }
 
+// ExportedMsg reports a finished export; Err is set if the file could not
+// be written.
+type ExportedMsg struct {
+	Path string
+	Rows int
+	Err  error
+}
+
+type ImportPlannedMsg struct {
+	Plan   *domain.ImportPlan
+	Format importer.Format
+	Err    error
+}
+
+// ImportCommittedMsg reports how many entries an import created; Err is set
+// if it stopped early.
+type ImportCommittedMsg struct {
+	Created int
+	Err     error
+}
+
 type SwitchViewMsg struct {
 	View ViewType
 }
 
 type TickMsg time.Time
 
+type SyncTickMsg struct{}
+
+type SyncCompletedMsg struct {
+	Result *domain.SyncResult
+}
+
+// PomodoroIntervalMsg reports the timer change at the start of a Pomodoro
+// interval; Entry is the entry started for a work interval.
+type PomodoroIntervalMsg struct {
+	Phase domain.PomodoroPhase
+	Entry *api.TimeEntry
+	Err   error
+}
+
+type TargetProgressLoadedMsg struct {
+	WorkspaceID string
+	Progress    *domain.TargetProgress
+}
+
+type IdleTickMsg struct{}
+
+// IdleCheckedMsg carries how long the user had been idle at At.
+type IdleCheckedMsg struct {
+	Idle time.Duration
+	At   time.Time
+}
+
+// IdleResolvedMsg reports the outcome of a keep, discard or split choice;
+// Entry is the timer running afterwards.
+type IdleResolvedMsg struct {
+	Choice domain.IdleChoice
+	Period domain.IdlePeriod
+	Entry  *api.TimeEntry
+	Err    error
+}
+
 type ProjectSelectedMsg struct {
 	ProjectID *string
 	TaskID    *string
@@ -78,6 +174,27 @@ type TimerDescriptionUpdatedMsg struct {
 	Entry *api.TimeEntry
 }
 
+type TimeEntryUpdatedMsg struct {
+	Entry *api.TimeEntry
+}
+
+type TimeEntryCreatedMsg struct {
+	Entry *api.TimeEntry
+}
+
+type TimeEntryDeletedMsg struct {
+	Entry api.TimeEntry
+}
+
+type TimeEntryRestoredMsg struct {
+	Entry *api.TimeEntry
+}
+
+type UndoExpiredMsg struct {
+	EntryID string
+}
+
 type DescriptionSuggestionsLoadedMsg struct {
 	Suggestions []string
+	Seq         int
 }
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"context"
+	"sync"
+)
+
+type requestKind int
+
+const (
+	requestEntries requestKind = iota
+	requestReports
+	requestGroupedReport
+	requestSuggestions
+	requestTasks
+)
+
+// requestTracker owns the contexts of in-flight loads. Starting a new load of
+// a kind cancels the previous one, and the sequence number lets handlers drop
+// results that arrive after a newer load was started. It is shared by pointer
+// because App is copied on every Update.
+type requestTracker struct {
+	mu      sync.Mutex
+	root    context.Context
+	cancel  context.CancelFunc
+	pending map[requestKind]context.CancelFunc
+	seq     map[requestKind]int
+}
+
+func newRequestTracker() *requestTracker {
+	root, cancel := context.WithCancel(context.Background())
+	return &requestTracker{
+		root:    root,
+		cancel:  cancel,
+		pending: make(map[requestKind]context.CancelFunc),
+		seq:     make(map[requestKind]int),
+	}
+}
+
+func (t *requestTracker) context() context.Context {
+	return t.root
+}
+
+func (t *requestTracker) begin(kind requestKind) (context.Context, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cancel, ok := t.pending[kind]; ok {
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(t.root)
+	t.pending[kind] = cancel
+	t.seq[kind]++
+	return ctx, t.seq[kind]
+}
+
+func (t *requestTracker) isCurrent(kind requestKind, seq int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.seq[kind] == seq
+}
+
+func (t *requestTracker) cancelKind(kinds ...requestKind) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, kind := range kinds {
+		if cancel, ok := t.pending[kind]; ok {
+			cancel()
+			delete(t.pending, kind)
+		}
+		// Bump the sequence so a result already in flight is treated as stale.
+		t.seq[kind]++
+	}
+}
+
+func (t *requestTracker) cancelAll() {
+	t.cancel()
+}
@@ -36,6 +36,11 @@ var (
 			Foreground(theme.MutedColor).
 			Padding(0, 2)
 
+	WorkspaceLabelStyle = lipgloss.NewStyle().
+			Foreground(theme.Subtext0Color).
+			Italic(true).
+			Padding(0, 2)
+
 	TimerBoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			Padding(1, 2).
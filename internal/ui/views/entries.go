@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"main/internal/api"
+	"main/internal/domain"
 	"main/internal/ui/components"
 	"main/internal/ui/theme"
 
@@ -13,13 +14,26 @@ import (
 
 type EntriesView struct {
 	entriesComponent *components.EntriesComponent
+	entryForm        *components.EntryFormComponent
+	projectSelector  *components.ProjectSelectorComponent
+	timeline         *components.TimelineComponent
+	showForm         bool
+	confirmDelete    bool
+	showSelector     bool
 	width            int
 	height           int
 }
 
-func NewEntriesView() *EntriesView {
+// timelineMinWidth is the narrowest window that fits the timeline next to
+// the entries.
+const timelineMinWidth = 100
+
+func NewEntriesView(timerState *domain.TimerState) *EntriesView {
 	return &EntriesView{
 		entriesComponent: components.NewEntriesComponent(),
+		entryForm:        components.NewEntryForm(),
+		projectSelector:  components.NewProjectSelector(),
+		timeline:         components.NewTimelineComponent(timerState),
 	}
 }
 
@@ -27,22 +41,52 @@ func (v *EntriesView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
 	v.entriesComponent.SetSize(width, height)
+	v.entryForm.SetSize(width, height)
+	v.projectSelector.SetSize(width, height)
+	v.timeline.SetHeight(height - 10)
 }
 
 func (v *EntriesView) SetEntries(entries []api.TimeEntry) {
 	v.entriesComponent.SetEntries(entries)
+	v.timeline.SetEntries(entries, v.entriesComponent.GetSelectedDate())
+}
+
+// SetWorkingHours sets the hours the timeline always shows.
+func (v *EntriesView) SetWorkingHours(hours domain.WorkingHours) {
+	v.timeline.SetWorkingHours(hours)
+}
+
+// Tick lets the running timer grow on the timeline.
+func (v *EntriesView) Tick(now time.Time) {
+	v.timeline.SetNow(now)
+}
+
+func (v *EntriesView) SetAnalysis(analysis *domain.DayAnalysis) {
+	v.entriesComponent.SetAnalysis(analysis)
+}
+
+func (v *EntriesView) GetAnalysis() *domain.DayAnalysis {
+	return v.entriesComponent.GetAnalysis()
 }
 
 func (v *EntriesView) SetProjects(projects map[string]string) {
 	v.entriesComponent.SetProjects(projects)
+	v.entryForm.SetProjects(projects)
+}
+
+func (v *EntriesView) SetProjectList(projects []api.Project) {
+	v.projectSelector.SetProjects(projects)
+	v.timeline.SetProjects(projects)
 }
 
 func (v *EntriesView) SetTasks(tasks map[string]string) {
 	v.entriesComponent.SetTasks(tasks)
+	v.entryForm.SetTasks(tasks)
 }
 
 func (v *EntriesView) SetTags(tags map[string]string) {
 	v.entriesComponent.SetTags(tags)
+	v.entryForm.SetTags(tags)
 }
 
 func (v *EntriesView) GetViewMode() components.EntriesViewMode {
@@ -65,6 +109,10 @@ func (v *EntriesView) GetSelectedEntry() *api.TimeEntry {
 	return v.entriesComponent.GetSelectedEntry()
 }
 
+func (v *EntriesView) GetSelectedGap() *domain.EntryGap {
+	return v.entriesComponent.GetSelectedGap()
+}
+
 func (v *EntriesView) GetSelectedDate() time.Time {
 	return v.entriesComponent.GetSelectedDate()
 }
@@ -77,11 +125,89 @@ func (v *EntriesView) PrevDate() {
 	v.entriesComponent.PrevDate()
 }
 
+func (v *EntriesView) RequestDeleteConfirmation() {
+	v.confirmDelete = true
+}
+
+func (v *EntriesView) CancelDeleteConfirmation() {
+	v.confirmDelete = false
+}
+
+func (v *EntriesView) IsConfirmingDelete() bool {
+	return v.confirmDelete
+}
+
+func (v *EntriesView) ShowEditForm(entry *api.TimeEntry) {
+	v.entryForm.LoadEntry(entry)
+	v.showForm = true
+	v.showSelector = false
+}
+
+func (v *EntriesView) ShowNewForm(date time.Time) {
+	v.entryForm.LoadNew(date)
+	v.showForm = true
+	v.showSelector = false
+}
+
+// ShowGapForm opens the form for a new entry prefilled with the times of
+// gap.
+func (v *EntriesView) ShowGapForm(gap *domain.EntryGap) {
+	v.entryForm.LoadGap(gap.Start, gap.End)
+	v.showForm = true
+	v.showSelector = false
+}
+
+func (v *EntriesView) HideForm() {
+	v.showForm = false
+	v.HideSelector()
+}
+
+func (v *EntriesView) IsShowingForm() bool {
+	return v.showForm
+}
+
+func (v *EntriesView) GetEntryForm() *components.EntryFormComponent {
+	return v.entryForm
+}
+
+func (v *EntriesView) ShowProjectSelector() {
+	v.projectSelector.Reset()
+	if projectID := v.entryForm.GetProjectID(); projectID != nil {
+		v.projectSelector.SelectProject(*projectID)
+	}
+	v.showSelector = true
+}
+
+func (v *EntriesView) ShowTagSelector(allTags []api.Tag) {
+	v.projectSelector.SetTagsForEditing(v.entryForm.GetTagIDs(), allTags)
+	v.showSelector = true
+}
+
+func (v *EntriesView) HideSelector() {
+	v.projectSelector.Reset()
+	v.showSelector = false
+}
+
+func (v *EntriesView) IsShowingSelector() bool {
+	return v.showSelector
+}
+
+func (v *EntriesView) GetProjectSelector() *components.ProjectSelectorComponent {
+	return v.projectSelector
+}
+
 func (v *EntriesView) Update(msg tea.Msg) (*EntriesView, tea.Cmd) {
 	return v, nil
 }
 
 func (v *EntriesView) View() string {
+	if v.showSelector {
+		return v.projectSelector.View()
+	}
+	if v.showForm {
+		return v.entryForm.View()
+	}
+
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(theme.LavenderColor).
@@ -104,7 +230,45 @@ func (v *EntriesView) View() string {
 	}
 
 	content := titleStyle.Render("📋 Time Entries - "+viewModeStr) + "\n\n"
-	content += v.entriesComponent.View()
+	content += v.renderEntries()
+
+	if v.confirmDelete {
+		if entry := v.entriesComponent.GetSelectedEntry(); entry != nil {
+			description := entry.Description
+			if description == "" {
+				description = "(no description)"
+			}
+			confirmStyle := lipgloss.NewStyle().
+				Foreground(theme.RedColor).
+				Bold(true)
+			content += "\n\n" + confirmStyle.Render("Delete \""+description+"\"? (y/n)")
+		}
+	}
 
 	return content
 }
+
+// renderEntries shows the day's timeline next to the entries when the
+// window is wide enough.
+func (v *EntriesView) renderEntries() string {
+	if v.entriesComponent.GetViewMode() != components.ViewToday || v.width < timelineMinWidth {
+		return v.entriesComponent.View()
+	}
+
+	selectedID := ""
+	if entry := v.entriesComponent.GetSelectedEntry(); entry != nil {
+		selectedID = entry.ID
+	}
+	v.timeline.SetSelected(selectedID)
+
+	listWidth := v.width - components.TimelineWidth - 6
+	list := lipgloss.NewStyle().Width(listWidth).Render(v.entriesComponent.View())
+	timeline := lipgloss.NewStyle().
+		Width(components.TimelineWidth).
+		PaddingLeft(components.TimelinePadding).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(theme.Surface1Color).
+		Render(v.timeline.View())
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, timeline)
+}
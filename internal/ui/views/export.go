@@ -0,0 +1,48 @@
+package views
+
+import (
+	"time"
+
+	"main/internal/ui/components"
+)
+
+type ExportView struct {
+	dialog  *components.ExportDialogComponent
+	showing bool
+	width   int
+	height  int
+}
+
+func NewExportView() *ExportView {
+	return &ExportView{
+		dialog: components.NewExportDialog(),
+	}
+}
+
+func (v *ExportView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.dialog.SetSize(width, height)
+}
+
+// Show opens the dialog for an export of kind covering first to last.
+func (v *ExportView) Show(title, kind string, first, last time.Time) {
+	v.dialog.Load(title, kind, first, last)
+	v.showing = true
+}
+
+func (v *ExportView) Hide() {
+	v.showing = false
+}
+
+func (v *ExportView) IsShowing() bool {
+	return v.showing
+}
+
+func (v *ExportView) GetDialog() *components.ExportDialogComponent {
+	return v.dialog
+}
+
+func (v *ExportView) View() string {
+	return v.dialog.View()
+}
@@ -0,0 +1,47 @@
+package views
+
+import (
+	"main/internal/domain"
+	"main/internal/ui/components"
+)
+
+type IdleView struct {
+	prompt  *components.IdlePromptComponent
+	showing bool
+	width   int
+	height  int
+}
+
+func NewIdleView() *IdleView {
+	return &IdleView{
+		prompt: components.NewIdlePrompt(),
+	}
+}
+
+func (v *IdleView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.prompt.SetSize(width, height)
+}
+
+// Show asks about an idle period of the running entry.
+func (v *IdleView) Show(period domain.IdlePeriod, description string) {
+	v.prompt.Load(period, description)
+	v.showing = true
+}
+
+func (v *IdleView) Hide() {
+	v.showing = false
+}
+
+func (v *IdleView) IsShowing() bool {
+	return v.showing
+}
+
+func (v *IdleView) GetPrompt() *components.IdlePromptComponent {
+	return v.prompt
+}
+
+func (v *IdleView) View() string {
+	return v.prompt.View()
+}
@@ -0,0 +1,45 @@
+package views
+
+import (
+	"main/internal/ui/components"
+)
+
+type ImportView struct {
+	importer *components.ImportComponent
+	showing  bool
+	width    int
+	height   int
+}
+
+func NewImportView() *ImportView {
+	return &ImportView{
+		importer: components.NewImporter(),
+	}
+}
+
+func (v *ImportView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.importer.SetSize(width, height)
+}
+
+func (v *ImportView) Show() {
+	v.importer.Open()
+	v.showing = true
+}
+
+func (v *ImportView) Hide() {
+	v.showing = false
+}
+
+func (v *ImportView) IsShowing() bool {
+	return v.showing
+}
+
+func (v *ImportView) GetImporter() *components.ImportComponent {
+	return v.importer
+}
+
+func (v *ImportView) View() string {
+	return v.importer.View()
+}
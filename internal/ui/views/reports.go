@@ -1,17 +1,29 @@
 package views
 
 import (
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"main/internal/domain"
+	"main/internal/export"
 	"main/internal/ui/components"
 	"main/internal/ui/theme"
 )
 
+// reportsChromeHeight is the number of lines around the report body: tabs,
+// the view title and the status bar.
+const reportsChromeHeight = 12
+
 type ReportsView struct {
 	reportsComponent *components.ReportsComponent
+	rangePicker      *components.DateRangePickerComponent
+	showingPicker    bool
+	filter           *components.ReportFilterComponent
+	showingFilter    bool
+	scrollOffset     int
+	maxScrollOffset  int
 	width            int
 	height           int
 }
@@ -19,6 +31,8 @@ type ReportsView struct {
 func NewReportsView() *ReportsView {
 	return &ReportsView{
 		reportsComponent: components.NewReportsComponent(),
+		rangePicker:      components.NewDateRangePicker(),
+		filter:           components.NewReportFilter(),
 	}
 }
 
@@ -26,6 +40,8 @@ func (v *ReportsView) SetSize(width, height int) {
 	v.width = width
 	v.height = height
 	v.reportsComponent.SetSize(width, height)
+	v.rangePicker.SetSize(width, height)
+	v.filter.SetSize(width, height)
 }
 
 func (v *ReportsView) GetReportType() components.ReportType {
@@ -36,16 +52,112 @@ func (v *ReportsView) GetSelectedDate() time.Time {
 	return v.reportsComponent.GetSelectedDate()
 }
 
+func (v *ReportsView) GetRange() (time.Time, time.Time) {
+	return v.reportsComponent.GetRange()
+}
+
 func (v *ReportsView) SetDailyReport(report *domain.DailySummary) {
 	v.reportsComponent.SetDailyReport(report)
+	v.scrollOffset = 0
 }
 
 func (v *ReportsView) SetWeeklyReport(report *domain.WeeklySummary) {
 	v.reportsComponent.SetWeeklyReport(report)
+	v.scrollOffset = 0
+}
+
+func (v *ReportsView) SetRangeReport(report *domain.RangeSummary) {
+	v.reportsComponent.SetRangeReport(report)
+	v.scrollOffset = 0
+}
+
+func (v *ReportsView) SetTargets(targets *domain.HourTargets) {
+	v.reportsComponent.SetTargets(targets)
+}
+
+func (v *ReportsView) SetTargetProgress(progress *domain.TargetProgress) {
+	v.reportsComponent.SetTargetProgress(progress)
+}
+
+func (v *ReportsView) SetRange(first, last time.Time) {
+	v.reportsComponent.SetRange(first, last)
+}
+
+// ShowRangePicker opens the picker on the current custom range, or on the
+// current month if there is none yet.
+func (v *ReportsView) ShowRangePicker() {
+	first, last := v.reportsComponent.GetRange()
+	if first.IsZero() {
+		first = domain.MonthStart(time.Now())
+		last = first.AddDate(0, 1, -1)
+	}
+	v.rangePicker.Load(first, last)
+	v.showingPicker = true
+}
+
+func (v *ReportsView) HideRangePicker() {
+	v.showingPicker = false
+}
+
+func (v *ReportsView) IsShowingRangePicker() bool {
+	return v.showingPicker
+}
+
+func (v *ReportsView) GetRangePicker() *components.DateRangePickerComponent {
+	return v.rangePicker
+}
+
+func (v *ReportsView) ShowFilter() {
+	v.filter.Open()
+	v.showingFilter = true
+}
+
+func (v *ReportsView) HideFilter() {
+	v.showingFilter = false
+}
+
+func (v *ReportsView) IsShowingFilter() bool {
+	return v.showingFilter
 }
 
-func (v *ReportsView) SetTags(tags map[string]string) {
-	v.reportsComponent.SetTags(tags)
+func (v *ReportsView) GetFilter() *components.ReportFilterComponent {
+	return v.filter
+}
+
+func (v *ReportsView) ScrollUp() {
+	if v.scrollOffset > 0 {
+		v.scrollOffset--
+	}
+}
+
+func (v *ReportsView) ScrollDown() {
+	if v.scrollOffset < v.maxScrollOffset {
+		v.scrollOffset++
+	}
+}
+
+func (v *ReportsView) SetGroupedReport(report *domain.GroupedReport) {
+	v.reportsComponent.SetGroupedReport(report)
+}
+
+func (v *ReportsView) GetGrouping() (domain.GroupBy, domain.GroupBy) {
+	return v.reportsComponent.GetGrouping()
+}
+
+func (v *ReportsView) CycleGrouping() {
+	v.reportsComponent.CycleGrouping()
+}
+
+func (v *ReportsView) CycleSubgrouping() {
+	v.reportsComponent.CycleSubgrouping()
+}
+
+func (v *ReportsView) GetPeriod() (time.Time, time.Time) {
+	return v.reportsComponent.Period()
+}
+
+func (v *ReportsView) ExportTable() (export.Table, bool) {
+	return v.reportsComponent.ExportTable()
 }
 
 func (v *ReportsView) ToggleReportType() {
@@ -71,12 +183,44 @@ func (v *ReportsView) View() string {
 		MarginBottom(1)
 
 	reportTypeStr := "Daily"
-	if v.reportsComponent.GetReportType() == components.WeeklyReport {
+	switch v.reportsComponent.GetReportType() {
+	case components.WeeklyReport:
 		reportTypeStr = "Weekly"
+	case components.MonthlyReport:
+		reportTypeStr = "Monthly"
+	case components.RangeReport:
+		reportTypeStr = "Custom Range"
 	}
 
 	content := titleStyle.Render("📊 Reports - "+reportTypeStr) + "\n\n"
-	content += v.reportsComponent.View()
+	if v.showingPicker {
+		return content + v.rangePicker.View()
+	}
+	if v.showingFilter {
+		return content + v.filter.View()
+	}
+
+	extraLines := 0
+	summary := v.filter.Summary()
+	v.reportsComponent.SetOwnTime(summary == "")
+	if summary != "" {
+		filterStyle := lipgloss.NewStyle().Foreground(theme.YellowColor)
+		content += filterStyle.Render("Filter: "+summary) + "\n"
+		extraLines = 1
+	}
+
+	return content + v.scroll(v.reportsComponent.View(), extraLines)
+}
+
+// scroll cuts the report to the lines that fit below the title and
+// extraLines more, starting at the scroll offset.
+func (v *ReportsView) scroll(body string, extraLines int) string {
+	lines := strings.Split(body, "\n")
+	visible := max(v.height-reportsChromeHeight-extraLines, 5)
+
+	v.maxScrollOffset = max(len(lines)-visible, 0)
+	v.scrollOffset = min(v.scrollOffset, v.maxScrollOffset)
 
-	return content
+	end := min(v.scrollOffset+visible, len(lines))
+	return strings.Join(lines[v.scrollOffset:end], "\n")
 }
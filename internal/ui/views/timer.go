@@ -19,9 +19,9 @@ type TimerView struct {
 	height            int
 }
 
-func NewTimerView(timerState *domain.TimerState) *TimerView {
+func NewTimerView(timerState *domain.TimerState, pomodoro *domain.Pomodoro) *TimerView {
 	return &TimerView{
-		timerComponent:  components.NewTimerComponent(timerState),
+		timerComponent:  components.NewTimerComponent(timerState, pomodoro),
 		projectSelector: components.NewProjectSelector(),
 		showSelector:    false,
 	}
@@ -41,6 +41,10 @@ func (v *TimerView) SetProjectMap(projects map[string]string) {
 	v.timerComponent.SetProjectMap(projects)
 }
 
+func (v *TimerView) SetTargetProgress(progress *domain.TargetProgress) {
+	v.timerComponent.SetTargetProgress(progress)
+}
+
 func (v *TimerView) SetTagMap(tags map[string]string) {
 	v.timerComponent.SetTagMap(tags)
 }
@@ -110,6 +114,11 @@ func (v *TimerView) View() string {
 			helpText += " | x: stop timer | d: edit description & tags"
 		}
 		helpText += " | p: select project"
+		if v.timerComponent.IsPomodoroActive() {
+			helpText += " | P: leave Pomodoro mode"
+		} else {
+			helpText += " | P: Pomodoro mode"
+		}
 		content += mutedStyle.Render(helpText)
 	}
 
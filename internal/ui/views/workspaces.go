@@ -0,0 +1,49 @@
+package views
+
+import (
+	"main/internal/api"
+	"main/internal/ui/components"
+)
+
+type WorkspacesView struct {
+	picker  *components.WorkspacePickerComponent
+	showing bool
+	width   int
+	height  int
+}
+
+func NewWorkspacesView() *WorkspacesView {
+	return &WorkspacesView{
+		picker: components.NewWorkspacePicker(),
+	}
+}
+
+func (v *WorkspacesView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.picker.SetSize(width, height)
+}
+
+func (v *WorkspacesView) SetWorkspaces(workspaces []api.Workspace, currentID string) {
+	v.picker.SetWorkspaces(workspaces, currentID)
+}
+
+func (v *WorkspacesView) Show() {
+	v.showing = true
+}
+
+func (v *WorkspacesView) Hide() {
+	v.showing = false
+}
+
+func (v *WorkspacesView) IsShowing() bool {
+	return v.showing
+}
+
+func (v *WorkspacesView) GetPicker() *components.WorkspacePickerComponent {
+	return v.picker
+}
+
+func (v *WorkspacesView) View() string {
+	return v.picker.View()
+}